@@ -0,0 +1,118 @@
+package main
+
+import (
+	"Car-Management-System/driver"
+	"Car-Management-System/migrate"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: migrate [-dir <path>] <command> [args]
+
+Commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N applied migrations (default 1)
+  status         list migrations and whether they are applied
+  create <name>  write an empty up/down pair to -dir
+  seed           load the sample cars and engines, once
+`
+
+func main() {
+	dir := flag.String("dir", migrate.DefaultDir, "directory new migrations are created in")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal("create requires a migration name")
+		}
+		upPath, downPath, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			log.Fatalf("Error creating migration : %v", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upPath, downPath)
+		return
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using the environment")
+	}
+
+	driver.InitDB()
+	defer driver.CloseDB()
+
+	migrator, err := migrate.New(driver.GetDB())
+	if err != nil {
+		log.Fatalf("Error loading migrations : %v", err)
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Error applying migrations : %v", err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", count)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps %q", args[1])
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("Error rolling back migrations : %v", err)
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", count)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Error reading migration status : %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", "-"
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		w.Flush()
+
+	case "seed":
+		count, err := migrator.Seed(ctx)
+		if err != nil {
+			log.Fatalf("Error seeding the database : %v", err)
+		}
+		fmt.Printf("Applied %d seed(s)\n", count)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
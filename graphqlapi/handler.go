@@ -0,0 +1,124 @@
+package graphqlapi
+
+import (
+	"Car-Management-System/service"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.opentelemetry.io/otel"
+)
+
+// maxBodyBytes bounds the size of a request body.
+const maxBodyBytes = 1 << 20
+
+type Handler struct {
+	schema  graphql.Schema
+	engines service.EngineServiceInterface
+	limits  Limits
+}
+
+func NewHandler(schema graphql.Schema, engines service.EngineServiceInterface, limits Limits) *Handler {
+	return &Handler{
+		schema:  schema,
+		engines: engines,
+		limits:  limits,
+	}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeGraphQL runs a query given as a JSON body on POST, or as the
+// query, operationName and variables parameters on GET. Mutations are
+// only run on POST.
+func (h *Handler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("GraphQLHandler")
+	ctx, span := tracer.Start(r.Context(), "ServeGraphQL-Handler")
+	defer span.End()
+
+	var req request
+	if r.Method == http.MethodGet {
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("variables must be a JSON object"))
+				return
+			}
+		}
+	} else {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+		if err != nil {
+			log.Println("Error : ", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("request body must be a JSON object with a query"))
+			return
+		}
+	}
+
+	if req.Query == "" {
+		writeErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError("query is required"))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, gqlerrors.FormatError(err))
+		return
+	}
+
+	validation := graphql.ValidateDocument(&h.schema, doc, nil)
+	if !validation.IsValid {
+		writeErrors(w, http.StatusBadRequest, validation.Errors...)
+		return
+	}
+
+	if err := h.limits.check(&h.schema, doc, req.OperationName, req.Variables); err != nil {
+		writeErrors(w, http.StatusBadRequest, gqlerrors.NewFormattedError(err.Error()))
+		return
+	}
+
+	if r.Method == http.MethodGet && isMutation(doc, req.OperationName) {
+		writeErrors(w, http.StatusMethodNotAllowed, gqlerrors.NewFormattedError("mutations must be sent with POST"))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(ctx, h.engines),
+	})
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...gqlerrors.FormattedError) {
+	writeJSON(w, status, &graphql.Result{Errors: errs})
+}
+
+func writeJSON(w http.ResponseWriter, status int, result *graphql.Result) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,180 @@
+package graphqlapi
+
+import (
+	"Car-Management-System/store/listquery"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bounds the size of a query before it runs. MaxDepth is the
+// deepest allowed field nesting and MaxComplexity the highest allowed
+// cost, where each field costs 1 and the fields inside a list cost as
+// many times as the list's limit. Zero disables a check.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// check measures the operation named operationName in doc, which must
+// have passed validation against schema, against the limits.
+func (l Limits) check(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) error {
+	depth, complexity := measure(schema, doc, operationName, variables)
+
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
+
+// measure returns the depth and the cost of the operation named
+// operationName in doc.
+func measure(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) (int, int) {
+	fragments := map[string]*ast.FragmentDefinition{}
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operation == nil || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0
+	}
+
+	var root *graphql.Object
+	switch operation.Operation {
+	case ast.OperationTypeMutation:
+		root = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = schema.SubscriptionType()
+	default:
+		root = schema.QueryType()
+	}
+
+	m := measurer{schema: schema, fragments: fragments, variables: variables}
+	return m.selectionSet(operation.SelectionSet, root)
+}
+
+type measurer struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet returns the depth and the cost of set, which selects
+// fields of parent.
+func (m measurer) selectionSet(set *ast.SelectionSet, parent *graphql.Object) (int, int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	depth, cost := 0, 0
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			// Introspection is bounded by the schema, not by the data.
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			var definition *graphql.FieldDefinition
+			if parent != nil {
+				definition = parent.Fields()[selection.Name.Value]
+			}
+			if definition == nil {
+				d, c = m.selectionSet(selection.SelectionSet, nil)
+			} else {
+				d, c = m.selectionSet(selection.SelectionSet, objectType(definition.Type))
+				if isList(definition) {
+					c *= m.listLimit(selection)
+				}
+			}
+			d, c = d+1, c+1
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(selection.SelectionSet, m.typeCondition(selection.TypeCondition, parent))
+		case *ast.FragmentSpread:
+			if fragment, ok := m.fragments[selection.Name.Value]; ok {
+				d, c = m.selectionSet(fragment.SelectionSet, m.typeCondition(fragment.TypeCondition, parent))
+			}
+		}
+		depth = max(depth, d)
+		cost += c
+	}
+	return depth, cost
+}
+
+// isList reports whether definition returns a page of items, which is
+// what a field with a limit argument does.
+func isList(definition *graphql.FieldDefinition) bool {
+	for _, argument := range definition.Args {
+		if argument.Name() == "limit" {
+			return true
+		}
+	}
+	return false
+}
+
+// objectType returns the object t holds, through any list and non-null
+// wrappers, or nil for a scalar or enum.
+func objectType(t graphql.Type) *graphql.Object {
+	object, _ := graphql.GetNamed(t).(*graphql.Object)
+	return object
+}
+
+// typeCondition returns the type a fragment selects from, which is parent
+// when the fragment names none.
+func (m measurer) typeCondition(condition *ast.Named, parent *graphql.Object) *graphql.Object {
+	if condition == nil || condition.Name == nil {
+		return parent
+	}
+	return objectType(m.schema.Type(condition.Name.Value))
+}
+
+// listLimit returns the number of items field can return, going by its
+// limit argument the way the stores do.
+func (m measurer) listLimit(field *ast.Field) int {
+	limit := 0
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != "limit" {
+			continue
+		}
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			limit, _ = strconv.Atoi(value.Value)
+		case *ast.Variable:
+			switch v := m.variables[value.Name.Value].(type) {
+			case float64:
+				limit = int(v)
+			case int:
+				limit = v
+			}
+		}
+	}
+	return listquery.Limit(limit)
+}
+
+// isMutation reports whether the operation named operationName in doc is
+// a mutation.
+func isMutation(doc *ast.Document, operationName string) bool {
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return operation.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
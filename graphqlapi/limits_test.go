@@ -0,0 +1,149 @@
+package graphqlapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// nestedSchema has a list inside a list, like engines that list their
+// cars.
+func nestedSchema(t *testing.T) graphql.Schema {
+	t.Helper()
+
+	page := func(name string, item *graphql.Object, items string) *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: name,
+			Fields: graphql.Fields{
+				items:        &graphql.Field{Type: graphql.NewList(item)},
+				"totalCount": &graphql.Field{Type: graphql.Int},
+			},
+		})
+	}
+	limitArgs := graphql.FieldConfigArgument{"limit": &graphql.ArgumentConfig{Type: graphql.Int}}
+
+	carType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Car",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.ID},
+			"name": &graphql.Field{Type: graphql.String},
+		},
+	})
+	engineType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Engine",
+		Fields: graphql.Fields{
+			"id":   &graphql.Field{Type: graphql.ID},
+			"cars": &graphql.Field{Type: page("CarPage", carType, "cars"), Args: limitArgs},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"engines": &graphql.Field{Type: page("EnginePage", engineType, "engines"), Args: limitArgs},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+	return schema
+}
+
+// cost returns the depth and cost of the only operation in query.
+func cost(t *testing.T, schema graphql.Schema, query string, variables map[string]interface{}) (int, int) {
+	t.Helper()
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if result := graphql.ValidateDocument(&schema, doc, nil); !result.IsValid {
+		t.Fatalf("ValidateDocument() errors = %v", result.Errors)
+	}
+	return measure(&schema, doc, "", variables)
+}
+
+func TestComplexityOfNestedLists(t *testing.T) {
+	schema := nestedSchema(t)
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		depth     int
+		cost      int
+	}{
+		{
+			// cars: (id + name + the cars list) * 5 + 1 = 16
+			// engines: (id + 16 + the engines list) * 10 + 1 = 181
+			name:  "list inside a list",
+			query: `{ engines(limit: 10) { engines { id cars(limit: 5) { cars { id name } } } } }`,
+			depth: 5,
+			cost:  181,
+		},
+		{
+			name:  "inner list with the default limit",
+			query: `{ engines(limit: 2) { engines { cars { cars { id } } } } }`,
+			depth: 5,
+			cost:  (((1+1)*20+1)+1)*2 + 1,
+		},
+		{
+			name:      "limits from variables",
+			query:     `query($outer: Int, $inner: Int) { engines(limit: $outer) { engines { cars(limit: $inner) { totalCount } } } }`,
+			variables: map[string]interface{}{"outer": float64(3), "inner": float64(4)},
+			depth:     4,
+			cost:      ((1*4+1)+1)*3 + 1,
+		},
+		{
+			name:  "nested list in a fragment",
+			query: `{ engines(limit: 10) { engines { id ...cars } } } fragment cars on Engine { cars(limit: 5) { cars { id name } } }`,
+			depth: 5,
+			cost:  181,
+		},
+		{
+			name:  "nested list in an inline fragment",
+			query: `{ engines(limit: 10) { engines { id ... on Engine { cars(limit: 5) { cars { id name } } } } } }`,
+			depth: 5,
+			cost:  181,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			depth, complexity := cost(t, schema, tt.query, tt.variables)
+			if depth != tt.depth || complexity != tt.cost {
+				t.Fatalf("depth, cost = %d, %d, want %d, %d", depth, complexity, tt.depth, tt.cost)
+			}
+		})
+	}
+}
+
+func TestComplexityOfCarsQuery(t *testing.T) {
+	schema, err := NewSchema(nil, nil)
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+
+	// (id + engine { id } + the cars list + totalCount) * 5 + 1
+	_, complexity := cost(t, schema, `{ cars(limit: 5) { totalCount cars { id engine { id } } } }`, nil)
+	if want := (1+2+1+1)*5 + 1; complexity != want {
+		t.Fatalf("cost = %d, want %d", complexity, want)
+	}
+}
+
+func TestCheckRejectsCostlyNestedQuery(t *testing.T) {
+	schema := nestedSchema(t)
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ engines(limit: 100) { engines { cars(limit: 100) { cars { id } } } } }`})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	err = Limits{MaxComplexity: 2000}.check(&schema, doc, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "complexity 20201") {
+		t.Fatalf("check() error = %v, want the complexity of 20201 reported", err)
+	}
+}
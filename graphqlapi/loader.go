@@ -0,0 +1,96 @@
+package graphqlapi
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type loadersKey struct{}
+
+// engineLoader batches the engine lookups of one request. Load only
+// queues an id and returns a thunk; the executor resolves all thunks of a
+// level together, so the first thunk to run fetches every queued engine
+// in one query and the others read the result.
+type engineLoader struct {
+	service service.EngineServiceInterface
+
+	mu      sync.Mutex
+	pending []uuid.UUID
+	engines map[uuid.UUID]*models.Engine
+	errs    map[uuid.UUID]error
+}
+
+func newEngineLoader(service service.EngineServiceInterface) *engineLoader {
+	return &engineLoader{
+		service: service,
+		engines: map[uuid.UUID]*models.Engine{},
+		errs:    map[uuid.UUID]error{},
+	}
+}
+
+// withLoaders returns ctx with a fresh set of loaders. Loaders cache for
+// the length of a request, so each request needs its own.
+func withLoaders(ctx context.Context, engines service.EngineServiceInterface) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newEngineLoader(engines))
+}
+
+func engineLoaderFrom(ctx context.Context) *engineLoader {
+	loader, _ := ctx.Value(loadersKey{}).(*engineLoader)
+	return loader
+}
+
+// Load returns a thunk for the engine with id. The thunk yields nil if
+// there is no such engine.
+func (l *engineLoader) Load(ctx context.Context, id uuid.UUID) func() (interface{}, error) {
+	l.mu.Lock()
+	_, loaded := l.engines[id]
+	_, failed := l.errs[id]
+	if !loaded && !failed {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.dispatch(ctx)
+
+		if err, ok := l.errs[id]; ok {
+			return nil, err
+		}
+		if engine := l.engines[id]; engine != nil {
+			return *engine, nil
+		}
+		return nil, nil
+	}
+}
+
+// dispatch fetches the queued ids. l.mu must be held.
+func (l *engineLoader) dispatch(ctx context.Context) {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	ids := l.pending
+	l.pending = nil
+
+	// Cars are listed with their engines even if both are deleted, so
+	// deleted engines are loaded as well.
+	engines, err := l.service.GetEnginesByIds(ctx, ids, true)
+	for _, id := range ids {
+		if err != nil {
+			l.errs[id] = err
+			continue
+		}
+		if engine, ok := engines[id]; ok {
+			l.engines[id] = &engine
+		} else {
+			l.engines[id] = nil
+		}
+	}
+}
@@ -0,0 +1,816 @@
+package graphqlapi
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"Car-Management-System/vin"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+)
+
+var (
+	errInternal               = errors.New("internal error")
+	errVersionRequired        = errors.New("expectedVersion is required; pass the version returned by a query, or unconditional: true to write unconditionally")
+	errVersionConflictingArgs = errors.New("expectedVersion and unconditional cannot be used together")
+)
+
+// resolvers holds the services the schema delegates to.
+type resolvers struct {
+	cars    service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+// NewSchema returns the GraphQL schema for cars and engines. Queries need
+// the viewer role; mutations need the same roles as the matching REST
+// routes.
+func NewSchema(cars service.CarServiceInterface, engines service.EngineServiceInterface) (graphql.Schema, error) {
+	r := &resolvers{cars: cars, engines: engines}
+
+	powertrainValues := graphql.EnumValueConfigMap{}
+	for _, powertrain := range models.PowertrainTypes {
+		powertrainValues[powertrain] = &graphql.EnumValueConfig{Value: powertrain}
+	}
+	powertrainType := graphql.NewEnum(graphql.EnumConfig{
+		Name:   "Powertrain",
+		Values: powertrainValues,
+	})
+
+	engineType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Engine",
+		Fields: graphql.Fields{
+			"id":            engineField(graphql.NewNonNull(graphql.ID), func(e models.Engine) interface{} { return e.EngineID.String() }),
+			"displacement":  engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.Displacement }),
+			"noOfCylinders": engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.NoOfCylinders }),
+			"carRange":      engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.CarRange }),
+			"powertrain":    engineField(graphql.NewNonNull(powertrainType), func(e models.Engine) interface{} { return e.Type }),
+			"batteryKWh":    engineField(graphql.NewNonNull(graphql.Float), func(e models.Engine) interface{} { return e.BatteryKWh }),
+			"chargePowerKW": engineField(graphql.NewNonNull(graphql.Float), func(e models.Engine) interface{} { return e.ChargePowerKW }),
+			"motorPowerKW":  engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.MotorPowerKW }),
+			"motorTorqueNm": engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.MotorTorqueNm }),
+			"version":       engineField(graphql.NewNonNull(graphql.Int), func(e models.Engine) interface{} { return e.Version }),
+			"deletedAt":     engineField(graphql.DateTime, func(e models.Engine) interface{} { return timeOrNil(e.DeletedAt) }),
+		},
+	})
+
+	carType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Car",
+		Fields: graphql.Fields{
+			"id":        carField(graphql.NewNonNull(graphql.ID), func(c models.Car) interface{} { return c.ID.String() }),
+			"name":      carField(graphql.NewNonNull(graphql.String), func(c models.Car) interface{} { return c.Name }),
+			"year":      carField(graphql.NewNonNull(graphql.String), func(c models.Car) interface{} { return c.Year }),
+			"brand":     carField(graphql.NewNonNull(graphql.String), func(c models.Car) interface{} { return c.Brand }),
+			"fuelType":  carField(graphql.NewNonNull(graphql.String), func(c models.Car) interface{} { return c.FuelType }),
+			"vin":       carField(graphql.String, func(c models.Car) interface{} { return stringOrNil(c.VIN) }),
+			"price":     carField(graphql.NewNonNull(graphql.Float), func(c models.Car) interface{} { return c.Price }),
+			"version":   carField(graphql.NewNonNull(graphql.Int), func(c models.Car) interface{} { return c.Version }),
+			"createdAt": carField(graphql.NewNonNull(graphql.DateTime), func(c models.Car) interface{} { return c.CreatedAt }),
+			"updatedAt": carField(graphql.NewNonNull(graphql.DateTime), func(c models.Car) interface{} { return c.UpdatedAt }),
+			"deletedAt": carField(graphql.DateTime, func(c models.Car) interface{} { return timeOrNil(c.DeletedAt) }),
+			"engine": &graphql.Field{
+				Type:    engineType,
+				Resolve: r.carEngine,
+			},
+		},
+	})
+
+	carPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "CarPage",
+		Fields: graphql.Fields{
+			"cars": pageField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(carType))), func(p *models.CarPage) interface{} { return p.Cars }),
+			"nextCursor": pageField(graphql.String, func(p *models.CarPage) interface{} {
+				if p.NextCursor == "" {
+					return nil
+				}
+				return p.NextCursor
+			}),
+			"totalCount": pageField(graphql.NewNonNull(graphql.Int), func(p *models.CarPage) interface{} { return p.TotalCount }),
+			"limit":      pageField(graphql.NewNonNull(graphql.Int), func(p *models.CarPage) interface{} { return p.Limit }),
+		},
+	})
+
+	enginePageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "EnginePage",
+		Fields: graphql.Fields{
+			"engines": pageField(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(engineType))), func(p *models.EnginePage) interface{} {
+				engines := make([]models.Engine, 0, len(p.Engines))
+				for _, item := range p.Engines {
+					engines = append(engines, item.Engine)
+				}
+				return engines
+			}),
+			"nextCursor": pageField(graphql.String, func(p *models.EnginePage) interface{} {
+				if p.NextCursor == "" {
+					return nil
+				}
+				return p.NextCursor
+			}),
+			"totalCount": pageField(graphql.NewNonNull(graphql.Int), func(p *models.EnginePage) interface{} { return p.TotalCount }),
+			"limit":      pageField(graphql.NewNonNull(graphql.Int), func(p *models.EnginePage) interface{} { return p.Limit }),
+		},
+	})
+
+	carInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CarInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"year":     &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Filled in from the VIN when left out."},
+			"brand":    &graphql.InputObjectFieldConfig{Type: graphql.String, Description: "Filled in from the VIN when left out."},
+			"fuelType": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			"vin":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"engineId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.ID)},
+			"price":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		},
+	})
+
+	engineInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "EngineInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"displacement":  &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"noOfCylinders": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"carRange":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+			"powertrain":    &graphql.InputObjectFieldConfig{Type: powertrainType, DefaultValue: models.PowertrainICE},
+			"batteryKWh":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+			"chargePowerKW": &graphql.InputObjectFieldConfig{Type: graphql.Float},
+			"motorPowerKW":  &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"motorTorqueNm": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		},
+	})
+
+	vinInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "VinInfo",
+		Fields: graphql.Fields{
+			"vin":          vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.VIN }),
+			"wmi":          vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.WMI }),
+			"manufacturer": vinField(graphql.String, func(i vin.Info) interface{} { return stringOrNil(i.Manufacturer) }),
+			"brand":        vinField(graphql.String, func(i vin.Info) interface{} { return stringOrNil(i.Brand) }),
+			"country":      vinField(graphql.String, func(i vin.Info) interface{} { return stringOrNil(i.Country) }),
+			"region":       vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.Region }),
+			"vds":          vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.VDS }),
+			"modelYear":    vinField(graphql.Int, func(i vin.Info) interface{} { return intOrNil(i.ModelYear) }),
+			"modelYears":   vinField(graphql.NewList(graphql.NewNonNull(graphql.Int)), func(i vin.Info) interface{} { return i.ModelYears }),
+			"plantCode":    vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.PlantCode }),
+			"plant":        vinField(graphql.String, func(i vin.Info) interface{} { return stringOrNil(i.Plant) }),
+			"serial":       vinField(graphql.NewNonNull(graphql.String), func(i vin.Info) interface{} { return i.Serial }),
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"car": &graphql.Field{
+				Type: carType,
+				Args: graphql.FieldConfigArgument{
+					"id":             &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"includeDeleted": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.car,
+			},
+			"carByVin": &graphql.Field{
+				Type: carType,
+				Args: graphql.FieldConfigArgument{
+					"vin":            &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					"includeDeleted": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.carByVIN,
+			},
+			"cars": &graphql.Field{
+				Type: graphql.NewNonNull(carPageType),
+				Args: graphql.FieldConfigArgument{
+					"brand":           &graphql.ArgumentConfig{Type: graphql.String},
+					"fuelType":        &graphql.ArgumentConfig{Type: graphql.String},
+					"minYear":         &graphql.ArgumentConfig{Type: graphql.Int},
+					"maxYear":         &graphql.ArgumentConfig{Type: graphql.Int},
+					"minPrice":        &graphql.ArgumentConfig{Type: graphql.Float},
+					"maxPrice":        &graphql.ArgumentConfig{Type: graphql.Float},
+					"minDisplacement": &graphql.ArgumentConfig{Type: graphql.Int},
+					"maxDisplacement": &graphql.ArgumentConfig{Type: graphql.Int},
+					"cylinders":       &graphql.ArgumentConfig{Type: graphql.Int},
+					"sortBy":          &graphql.ArgumentConfig{Type: graphql.String},
+					"order":           &graphql.ArgumentConfig{Type: graphql.String},
+					"limit":           &graphql.ArgumentConfig{Type: graphql.Int},
+					"cursor":          &graphql.ArgumentConfig{Type: graphql.String},
+					"includeDeleted":  &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.listCars,
+			},
+			"engine": &graphql.Field{
+				Type: engineType,
+				Args: graphql.FieldConfigArgument{
+					"id":             &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					"includeDeleted": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.engine,
+			},
+			"engines": &graphql.Field{
+				Type: graphql.NewNonNull(enginePageType),
+				Args: graphql.FieldConfigArgument{
+					"minDisplacement": &graphql.ArgumentConfig{Type: graphql.Int},
+					"maxDisplacement": &graphql.ArgumentConfig{Type: graphql.Int},
+					"cylinders":       &graphql.ArgumentConfig{Type: graphql.Int},
+					"minRange":        &graphql.ArgumentConfig{Type: graphql.Int},
+					"maxRange":        &graphql.ArgumentConfig{Type: graphql.Int},
+					"limit":           &graphql.ArgumentConfig{Type: graphql.Int},
+					"cursor":          &graphql.ArgumentConfig{Type: graphql.String},
+					"includeDeleted":  &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+				},
+				Resolve: r.listEngines,
+			},
+			"decodeVin": &graphql.Field{
+				Type: graphql.NewNonNull(vinInfoType),
+				Args: graphql.FieldConfigArgument{
+					"vin": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: decodeVIN,
+			},
+		},
+	})
+
+	idArg := &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+	versionArg := &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Fail unless the current version matches, like If-Match. Required unless unconditional is true.",
+	}
+	unconditionalArg := &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "Write without checking the version, like If-Match: *.",
+	}
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createCar": &graphql.Field{
+				Type: graphql.NewNonNull(carType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(carInputType)},
+				},
+				Resolve: r.createCar,
+			},
+			"updateCar": &graphql.Field{
+				Type: graphql.NewNonNull(carType),
+				Args: graphql.FieldConfigArgument{
+					"id":              idArg,
+					"input":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(carInputType)},
+					"expectedVersion": versionArg,
+					"unconditional":   unconditionalArg,
+				},
+				Resolve: r.updateCar,
+			},
+			"deleteCar": &graphql.Field{
+				Type: graphql.NewNonNull(carType),
+				Args: graphql.FieldConfigArgument{
+					"id":              idArg,
+					"expectedVersion": versionArg,
+					"unconditional":   unconditionalArg,
+				},
+				Resolve: r.deleteCar,
+			},
+			"createEngine": &graphql.Field{
+				Type: graphql.NewNonNull(engineType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(engineInputType)},
+				},
+				Resolve: r.createEngine,
+			},
+			"updateEngine": &graphql.Field{
+				Type: graphql.NewNonNull(engineType),
+				Args: graphql.FieldConfigArgument{
+					"id":              idArg,
+					"input":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(engineInputType)},
+					"expectedVersion": versionArg,
+					"unconditional":   unconditionalArg,
+				},
+				Resolve: r.updateEngine,
+			},
+			"deleteEngine": &graphql.Field{
+				Type: graphql.NewNonNull(engineType),
+				Args: graphql.FieldConfigArgument{
+					"id":              idArg,
+					"expectedVersion": versionArg,
+					"unconditional":   unconditionalArg,
+				},
+				Resolve: r.deleteEngine,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func (r *resolvers) car(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "Car-GraphQL")
+	defer span.End()
+
+	id, _ := p.Args["id"].(string)
+	includeDeleted, _ := p.Args["includeDeleted"].(bool)
+
+	car, err := r.cars.GetCarById(ctx, id, includeDeleted)
+	if err != nil {
+		if errors.Is(err, models.ErrCarNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return *car, nil
+}
+
+func (r *resolvers) carByVIN(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "CarByVIN-GraphQL")
+	defer span.End()
+
+	includeDeleted, _ := p.Args["includeDeleted"].(bool)
+
+	car, err := r.cars.GetCarByVIN(ctx, stringArg(p, "vin"), includeDeleted)
+	if err != nil {
+		if errors.Is(err, models.ErrCarNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return *car, nil
+}
+
+func (r *resolvers) listCars(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "ListCars-GraphQL")
+	defer span.End()
+
+	filter := &models.CarFilter{
+		Brand:           stringArg(p, "brand"),
+		FuelType:        stringArg(p, "fuelType"),
+		MinYear:         intArg(p, "minYear"),
+		MaxYear:         intArg(p, "maxYear"),
+		MinPrice:        floatArg(p, "minPrice"),
+		MaxPrice:        floatArg(p, "maxPrice"),
+		MinDisplacement: int32(intArg(p, "minDisplacement")),
+		MaxDisplacement: int32(intArg(p, "maxDisplacement")),
+		Cylinders:       int32(intArg(p, "cylinders")),
+		SortBy:          stringArg(p, "sortBy"),
+		Order:           stringArg(p, "order"),
+		Limit:           intArg(p, "limit"),
+		Cursor:          stringArg(p, "cursor"),
+	}
+	filter.IncludeDeleted, _ = p.Args["includeDeleted"].(bool)
+
+	page, err := r.cars.ListCars(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return page, nil
+}
+
+// carEngine resolves a car's engine. A car read on its own already has
+// its engine, with a version; a listed car only has the engine id, and the
+// engine is loaded in a batch with those of the other listed cars.
+func (r *resolvers) carEngine(p graphql.ResolveParams) (interface{}, error) {
+	car, ok := p.Source.(models.Car)
+	if !ok {
+		return nil, nil
+	}
+	if car.Engine.Version != 0 {
+		return car.Engine, nil
+	}
+	if car.Engine.EngineID == uuid.Nil {
+		return nil, nil
+	}
+
+	loader := engineLoaderFrom(p.Context)
+	if loader == nil {
+		loader = newEngineLoader(r.engines)
+	}
+
+	load := loader.Load(p.Context, car.Engine.EngineID)
+	return func() (interface{}, error) {
+		engine, err := load()
+		if err != nil {
+			return nil, resolverError(err)
+		}
+		return engine, nil
+	}, nil
+}
+
+// decodeVIN decodes a VIN offline, without looking for a car that has it.
+func decodeVIN(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	_, span := tracer.Start(p.Context, "DecodeVIN-GraphQL")
+	defer span.End()
+
+	v := vin.Normalize(stringArg(p, "vin"))
+	if err := models.ValidateVIN(v); err != nil {
+		return nil, resolverError(err)
+	}
+
+	info, err := vin.Decode(v)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return info, nil
+}
+
+func (r *resolvers) engine(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "Engine-GraphQL")
+	defer span.End()
+
+	id, _ := p.Args["id"].(string)
+	includeDeleted, _ := p.Args["includeDeleted"].(bool)
+
+	engine, err := r.engines.GetEngineById(ctx, id, includeDeleted)
+	if err != nil {
+		if errors.Is(err, models.ErrEngineNotFound) {
+			return nil, nil
+		}
+		return nil, resolverError(err)
+	}
+	return *engine, nil
+}
+
+func (r *resolvers) listEngines(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "ListEngines-GraphQL")
+	defer span.End()
+
+	filter := &models.EngineFilter{
+		MinDisplacement: int32(intArg(p, "minDisplacement")),
+		MaxDisplacement: int32(intArg(p, "maxDisplacement")),
+		Cylinders:       int32(intArg(p, "cylinders")),
+		MinRange:        int32(intArg(p, "minRange")),
+		MaxRange:        int32(intArg(p, "maxRange")),
+		Limit:           intArg(p, "limit"),
+		Cursor:          stringArg(p, "cursor"),
+	}
+	filter.IncludeDeleted, _ = p.Args["includeDeleted"].(bool)
+
+	page, err := r.engines.ListEngines(ctx, filter)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return page, nil
+}
+
+func (r *resolvers) createCar(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "CreateCar-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleEditor, "createCar"); err != nil {
+		return nil, err
+	}
+
+	carReq, err := r.carRequest(ctx, p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := r.cars.CreateCar(ctx, carReq)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *car, nil
+}
+
+func (r *resolvers) updateCar(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "UpdateCar-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleEditor, "updateCar"); err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(p)
+	if err != nil {
+		return nil, err
+	}
+
+	carReq, err := r.carRequest(ctx, p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := p.Args["id"].(string)
+	car, err := r.cars.UpdateCar(ctx, id, carReq, version)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *car, nil
+}
+
+func (r *resolvers) deleteCar(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "DeleteCar-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleEditor, "deleteCar"); err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(p)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := p.Args["id"].(string)
+	car, err := r.cars.DeleteCar(ctx, id, version)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *car, nil
+}
+
+func (r *resolvers) createEngine(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "CreateEngine-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleEditor, "createEngine"); err != nil {
+		return nil, err
+	}
+
+	engineReq, err := engineRequest(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := r.engines.CreateEngine(ctx, engineReq)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *engine, nil
+}
+
+func (r *resolvers) updateEngine(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "UpdateEngine-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleEditor, "updateEngine"); err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(p)
+	if err != nil {
+		return nil, err
+	}
+
+	engineReq, err := engineRequest(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := p.Args["id"].(string)
+	engine, err := r.engines.UpdateEngine(ctx, id, engineReq, version)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *engine, nil
+}
+
+func (r *resolvers) deleteEngine(p graphql.ResolveParams) (interface{}, error) {
+	tracer := otel.Tracer("GraphQL")
+	ctx, span := tracer.Start(p.Context, "DeleteEngine-GraphQL")
+	defer span.End()
+
+	if err := requireRole(ctx, models.RoleAdmin, "deleteEngine"); err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(p)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := p.Args["id"].(string)
+	engine, err := r.engines.DeleteEngine(ctx, id, version)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	return *engine, nil
+}
+
+// carRequest turns a CarInput into the request the car service takes,
+// loading the named engine, and validates it.
+func (r *resolvers) carRequest(ctx context.Context, arg interface{}) (*models.CarRequest, error) {
+	input, _ := arg.(map[string]interface{})
+
+	engineID, _ := input["engineId"].(string)
+	engine, err := r.engines.GetEngineById(ctx, engineID, false)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	carReq := &models.CarRequest{
+		Engine: *engine,
+	}
+	carReq.Name, _ = input["name"].(string)
+	carReq.Year, _ = input["year"].(string)
+	carReq.Brand, _ = input["brand"].(string)
+	carReq.FuelType, _ = input["fuelType"].(string)
+	carReq.VIN, _ = input["vin"].(string)
+	if price, ok := input["price"].(float64); ok {
+		carReq.Price = float32(price)
+	}
+	carReq.ApplyVIN()
+
+	if err := models.ValidateRequest(*carReq); err != nil {
+		return nil, resolverError(err)
+	}
+	return carReq, nil
+}
+
+// engineRequest turns an EngineInput into the request the engine service
+// takes and validates it.
+func engineRequest(arg interface{}) (*models.EngineRequest, error) {
+	input, _ := arg.(map[string]interface{})
+
+	engineReq := &models.EngineRequest{}
+	if v, ok := input["displacement"].(int); ok {
+		engineReq.Displacement = int32(v)
+	}
+	if v, ok := input["noOfCylinders"].(int); ok {
+		engineReq.NoOfCylinders = int32(v)
+	}
+	if v, ok := input["carRange"].(int); ok {
+		engineReq.CarRange = int32(v)
+	}
+	engineReq.Type, _ = input["powertrain"].(string)
+	if v, ok := input["batteryKWh"].(float64); ok {
+		engineReq.BatteryKWh = float32(v)
+	}
+	if v, ok := input["chargePowerKW"].(float64); ok {
+		engineReq.ChargePowerKW = float32(v)
+	}
+	if v, ok := input["motorPowerKW"].(int); ok {
+		engineReq.MotorPowerKW = int32(v)
+	}
+	if v, ok := input["motorTorqueNm"].(int); ok {
+		engineReq.MotorTorqueNm = int32(v)
+	}
+
+	engineReq.SetDefaultType()
+	if err := models.ValidateEngineRequest(*engineReq); err != nil {
+		return nil, resolverError(err)
+	}
+	return engineReq, nil
+}
+
+// requireRole fails unless the caller has at least role.
+func requireRole(ctx context.Context, required string, operation string) error {
+	role, _ := ctx.Value("role").(string)
+	if !models.RoleAtLeast(role, required) {
+		return fmt.Errorf("role %q is not allowed to call %s", role, operation)
+	}
+	return nil
+}
+
+// resolverError passes on the errors of the service layer that the REST
+// handlers show to clients and hides the rest.
+func resolverError(err error) error {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return fieldErrors{validationErr}
+	case errors.Is(err, models.ErrNotFound),
+		errors.Is(err, models.ErrValidation),
+		errors.Is(err, models.ErrConflict),
+		errors.Is(err, models.ErrForeignKey),
+		errors.Is(err, models.ErrInvalidSortField),
+		errors.Is(err, models.ErrInvalidCursor):
+		return err
+	default:
+		log.Println("Error : ", err)
+		return errInternal
+	}
+}
+
+// fieldErrors reports each violation of a validation error in the
+// "fields" extension of the GraphQL error.
+type fieldErrors struct {
+	*models.ValidationError
+}
+
+func (e fieldErrors) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   "VALIDATION_FAILED",
+		"fields": e.Errors,
+	}
+}
+
+func carField(t graphql.Output, get func(models.Car) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			car, ok := p.Source.(models.Car)
+			if !ok {
+				return nil, nil
+			}
+			return get(car), nil
+		},
+	}
+}
+
+func engineField(t graphql.Output, get func(models.Engine) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			engine, ok := p.Source.(models.Engine)
+			if !ok {
+				return nil, nil
+			}
+			return get(engine), nil
+		},
+	}
+}
+
+func vinField(t graphql.Output, get func(vin.Info) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			info, ok := p.Source.(vin.Info)
+			if !ok {
+				return nil, nil
+			}
+			return get(info), nil
+		},
+	}
+}
+
+func pageField[P any](t graphql.Output, get func(*P) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			page, ok := p.Source.(*P)
+			if !ok {
+				return nil, nil
+			}
+			return get(page), nil
+		},
+	}
+}
+
+func timeOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func stringOrNil(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func intOrNil(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func stringArg(p graphql.ResolveParams, name string) string {
+	v, _ := p.Args[name].(string)
+	return v
+}
+
+// expectedVersion returns the version a write is conditional on. Like
+// If-Match on the REST API it is required, so that a client cannot
+// overwrite a change it has not seen by leaving it out; unconditional
+// opts out and returns 0.
+func expectedVersion(p graphql.ResolveParams) (int64, error) {
+	version := intArg(p, "expectedVersion")
+	unconditional, _ := p.Args["unconditional"].(bool)
+
+	switch {
+	case unconditional && version != 0:
+		return 0, errVersionConflictingArgs
+	case unconditional:
+		return 0, nil
+	case version < 1:
+		return 0, errVersionRequired
+	}
+	return int64(version), nil
+}
+
+func intArg(p graphql.ResolveParams, name string) int {
+	v, _ := p.Args[name].(int)
+	return v
+}
+
+func floatArg(p graphql.ResolveParams, name string) float64 {
+	v, _ := p.Args[name].(float64)
+	return v
+}
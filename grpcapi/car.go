@@ -0,0 +1,249 @@
+package grpcapi
+
+import (
+	"Car-Management-System/models"
+	pb "Car-Management-System/proto/carmanagement/v1"
+	"Car-Management-System/service"
+	"Car-Management-System/vin"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CarServer serves CarService on top of the car service. A car input only
+// names its engine, so the engine is loaded from the engine service to
+// fill in the request the car service expects.
+type CarServer struct {
+	pb.UnimplementedCarServiceServer
+	service service.CarServiceInterface
+	engines service.EngineServiceInterface
+}
+
+func NewCarServer(service service.CarServiceInterface, engines service.EngineServiceInterface) *CarServer {
+	return &CarServer{
+		service: service,
+		engines: engines,
+	}
+}
+
+func (s *CarServer) GetCar(ctx context.Context, req *pb.GetCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "GetCar-GRPC")
+	defer span.End()
+
+	car, err := s.service.GetCarById(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return carToProto(car), nil
+}
+
+func (s *CarServer) GetCarByVin(ctx context.Context, req *pb.GetCarByVinRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "GetCarByVin-GRPC")
+	defer span.End()
+
+	car, err := s.service.GetCarByVIN(ctx, req.GetVin(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return carToProto(car), nil
+}
+
+func (s *CarServer) ListCars(ctx context.Context, req *pb.ListCarsRequest) (*pb.ListCarsResponse, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "ListCars-GRPC")
+	defer span.End()
+
+	page, err := s.service.ListCars(ctx, &models.CarFilter{
+		Brand:           req.GetBrand(),
+		FuelType:        req.GetFuelType(),
+		MinYear:         int(req.GetMinYear()),
+		MaxYear:         int(req.GetMaxYear()),
+		MinPrice:        req.GetMinPrice(),
+		MaxPrice:        req.GetMaxPrice(),
+		MinDisplacement: req.GetMinDisplacement(),
+		MaxDisplacement: req.GetMaxDisplacement(),
+		Cylinders:       req.GetCylinders(),
+		SortBy:          req.GetSortBy(),
+		Order:           req.GetOrder(),
+		Limit:           int(req.GetLimit()),
+		Cursor:          req.GetCursor(),
+		IsEngine:        req.GetIncludeEngine(),
+		IncludeDeleted:  req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &pb.ListCarsResponse{
+		Cars:       make([]*pb.Car, 0, len(page.Cars)),
+		NextCursor: page.NextCursor,
+		TotalCount: int32(page.TotalCount),
+		Limit:      int32(page.Limit),
+	}
+	for i := range page.Cars {
+		resp.Cars = append(resp.Cars, carToProto(&page.Cars[i]))
+	}
+
+	return resp, nil
+}
+
+func (s *CarServer) CreateCar(ctx context.Context, req *pb.CreateCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "CreateCar-GRPC")
+	defer span.End()
+
+	carReq, err := s.carRequest(ctx, req.GetCar())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.service.CreateCar(ctx, carReq)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return carToProto(car), nil
+}
+
+func (s *CarServer) UpdateCar(ctx context.Context, req *pb.UpdateCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "UpdateCar-GRPC")
+	defer span.End()
+
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	carReq, err := s.carRequest(ctx, req.GetCar())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.service.UpdateCar(ctx, req.GetId(), carReq, version)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return carToProto(car), nil
+}
+
+func (s *CarServer) DeleteCar(ctx context.Context, req *pb.DeleteCarRequest) (*pb.Car, error) {
+	tracer := otel.Tracer("CarServer")
+	ctx, span := tracer.Start(ctx, "DeleteCar-GRPC")
+	defer span.End()
+
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := s.service.DeleteCar(ctx, req.GetId(), version)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return carToProto(car), nil
+}
+
+func (s *CarServer) DecodeVin(ctx context.Context, req *pb.DecodeVinRequest) (*pb.VinInfo, error) {
+	tracer := otel.Tracer("CarServer")
+	_, span := tracer.Start(ctx, "DecodeVin-GRPC")
+	defer span.End()
+
+	v := vin.Normalize(req.GetVin())
+	if err := models.ValidateVIN(v); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	info, err := vin.Decode(v)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	resp := &pb.VinInfo{
+		Vin:          info.VIN,
+		Wmi:          info.WMI,
+		Manufacturer: info.Manufacturer,
+		Brand:        info.Brand,
+		Country:      info.Country,
+		Region:       info.Region,
+		Vds:          info.VDS,
+		ModelYear:    int32(info.ModelYear),
+		PlantCode:    info.PlantCode,
+		Plant:        info.Plant,
+		Serial:       info.Serial,
+	}
+	for _, year := range info.ModelYears {
+		resp.ModelYears = append(resp.ModelYears, int32(year))
+	}
+
+	return resp, nil
+}
+
+// carRequest turns input into the request the car service takes, loading
+// the named engine. Invalid input is reported as INVALID_ARGUMENT before
+// the car service sees it.
+func (s *CarServer) carRequest(ctx context.Context, input *pb.CarInput) (*models.CarRequest, error) {
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "car is required")
+	}
+
+	if _, err := uuid.Parse(input.GetEngineId()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "engine_id must be a valid UUID")
+	}
+
+	engine, err := s.engines.GetEngineById(ctx, input.GetEngineId(), false)
+	if err != nil {
+		if errors.Is(err, models.ErrEngineNotFound) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, serviceError(err)
+	}
+
+	carReq := &models.CarRequest{
+		Name:     input.GetName(),
+		Year:     input.GetYear(),
+		Brand:    input.GetBrand(),
+		FuelType: input.GetFuelType(),
+		Engine:   *engine,
+		Price:    input.GetPrice(),
+		VIN:      input.GetVin(),
+	}
+	carReq.ApplyVIN()
+
+	if err := models.ValidateRequest(*carReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	return carReq, nil
+}
+
+func carToProto(car *models.Car) *pb.Car {
+	resp := &pb.Car{
+		Id:        car.ID.String(),
+		Name:      car.Name,
+		Year:      car.Year,
+		Brand:     car.Brand,
+		FuelType:  car.FuelType,
+		Vin:       car.VIN,
+		Engine:    engineToProto(&car.Engine),
+		Price:     car.Price,
+		Version:   car.Version,
+		CreatedAt: timestamppb.New(car.CreatedAt),
+		UpdatedAt: timestamppb.New(car.UpdatedAt),
+	}
+	if car.DeletedAt != nil {
+		resp.DeletedAt = timestamppb.New(*car.DeletedAt)
+	}
+	return resp
+}
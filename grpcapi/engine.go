@@ -0,0 +1,189 @@
+package grpcapi
+
+import (
+	"Car-Management-System/models"
+	pb "Car-Management-System/proto/carmanagement/v1"
+	"Car-Management-System/service"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EngineServer serves EngineService on top of the engine service.
+type EngineServer struct {
+	pb.UnimplementedEngineServiceServer
+	service service.EngineServiceInterface
+}
+
+func NewEngineServer(service service.EngineServiceInterface) *EngineServer {
+	return &EngineServer{
+		service: service,
+	}
+}
+
+func (s *EngineServer) GetEngine(ctx context.Context, req *pb.GetEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "GetEngine-GRPC")
+	defer span.End()
+
+	engine, err := s.service.GetEngineById(ctx, req.GetId(), req.GetIncludeDeleted())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return engineToProto(engine), nil
+}
+
+func (s *EngineServer) ListEngines(ctx context.Context, req *pb.ListEnginesRequest) (*pb.ListEnginesResponse, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "ListEngines-GRPC")
+	defer span.End()
+
+	page, err := s.service.ListEngines(ctx, &models.EngineFilter{
+		MinDisplacement: req.GetMinDisplacement(),
+		MaxDisplacement: req.GetMaxDisplacement(),
+		Cylinders:       req.GetCylinders(),
+		MinRange:        req.GetMinRange(),
+		MaxRange:        req.GetMaxRange(),
+		WithCars:        req.GetWithCars(),
+		Limit:           int(req.GetLimit()),
+		Cursor:          req.GetCursor(),
+		IncludeDeleted:  req.GetIncludeDeleted(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resp := &pb.ListEnginesResponse{
+		Engines:    make([]*pb.EngineListItem, 0, len(page.Engines)),
+		NextCursor: page.NextCursor,
+		TotalCount: int32(page.TotalCount),
+		Limit:      int32(page.Limit),
+	}
+	for i := range page.Engines {
+		item := &page.Engines[i]
+		respItem := &pb.EngineListItem{
+			Engine: engineToProto(&item.Engine),
+		}
+		if item.CarCount != nil {
+			count := int32(*item.CarCount)
+			respItem.CarCount = &count
+		}
+		for _, carID := range item.CarIDs {
+			respItem.CarIds = append(respItem.CarIds, carID.String())
+		}
+		resp.Engines = append(resp.Engines, respItem)
+	}
+
+	return resp, nil
+}
+
+func (s *EngineServer) CreateEngine(ctx context.Context, req *pb.CreateEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "CreateEngine-GRPC")
+	defer span.End()
+
+	engineReq, err := engineRequest(req.GetEngine())
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := s.service.CreateEngine(ctx, engineReq)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return engineToProto(engine), nil
+}
+
+func (s *EngineServer) UpdateEngine(ctx context.Context, req *pb.UpdateEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "UpdateEngine-GRPC")
+	defer span.End()
+
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	engineReq, err := engineRequest(req.GetEngine())
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := s.service.UpdateEngine(ctx, req.GetId(), engineReq, version)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return engineToProto(engine), nil
+}
+
+func (s *EngineServer) DeleteEngine(ctx context.Context, req *pb.DeleteEngineRequest) (*pb.Engine, error) {
+	tracer := otel.Tracer("EngineServer")
+	ctx, span := tracer.Start(ctx, "DeleteEngine-GRPC")
+	defer span.End()
+
+	version, err := expectedVersion(ctx, req.GetExpectedVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := s.service.DeleteEngine(ctx, req.GetId(), version)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	return engineToProto(engine), nil
+}
+
+// engineRequest turns input into the request the engine service takes.
+// Invalid input is reported as INVALID_ARGUMENT before the engine service
+// sees it.
+func engineRequest(input *pb.EngineInput) (*models.EngineRequest, error) {
+	if input == nil {
+		return nil, status.Error(codes.InvalidArgument, "engine is required")
+	}
+
+	engineReq := &models.EngineRequest{
+		Displacement:  input.GetDisplacement(),
+		NoOfCylinders: input.GetNoOfCylinders(),
+		CarRange:      input.GetCarRange(),
+		Powertrain: models.Powertrain{
+			Type:          input.GetPowertrain(),
+			BatteryKWh:    input.GetBatteryKwh(),
+			ChargePowerKW: input.GetChargePowerKw(),
+			MotorPowerKW:  input.GetMotorPowerKw(),
+			MotorTorqueNm: input.GetMotorTorqueNm(),
+		},
+	}
+
+	engineReq.SetDefaultType()
+	if err := models.ValidateEngineRequest(*engineReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	return engineReq, nil
+}
+
+func engineToProto(engine *models.Engine) *pb.Engine {
+	resp := &pb.Engine{
+		Id:            engine.EngineID.String(),
+		Displacement:  engine.Displacement,
+		NoOfCylinders: engine.NoOfCylinders,
+		CarRange:      engine.CarRange,
+		Version:       engine.Version,
+		Powertrain:    engine.Type,
+		BatteryKwh:    engine.BatteryKWh,
+		ChargePowerKw: engine.ChargePowerKW,
+		MotorPowerKw:  engine.MotorPowerKW,
+		MotorTorqueNm: engine.MotorTorqueNm,
+	}
+	if engine.DeletedAt != nil {
+		resp.DeletedAt = timestamppb.New(*engine.DeletedAt)
+	}
+	return resp
+}
@@ -0,0 +1,128 @@
+package grpcapi
+
+import (
+	"Car-Management-System/keys"
+	"Car-Management-System/middleware"
+	"Car-Management-System/models"
+	pb "Car-Management-System/proto/carmanagement/v1"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	requestCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_requests_total",
+			Help: "Total number of gRPC requests by status code",
+		},
+		[]string{
+			"method", "code",
+		},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "grpc_requests_duration_seconds",
+			Help: "Duration of gRPC requests in seconds",
+		},
+		[]string{
+			"method",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestCounter, requestDuration)
+}
+
+// methodPermissions maps full gRPC method names to the minimum role
+// allowed to call them, matching the REST routes they mirror. Methods
+// missing from the table are denied.
+var methodPermissions = map[string]string{
+	pb.CarService_GetCar_FullMethodName:      models.RoleViewer,
+	pb.CarService_GetCarByVin_FullMethodName: models.RoleViewer,
+	pb.CarService_ListCars_FullMethodName:    models.RoleViewer,
+	pb.CarService_CreateCar_FullMethodName:   models.RoleEditor,
+	pb.CarService_UpdateCar_FullMethodName:   models.RoleEditor,
+	pb.CarService_DeleteCar_FullMethodName:   models.RoleEditor,
+	pb.CarService_DecodeVin_FullMethodName:   models.RoleViewer,
+
+	pb.EngineService_GetEngine_FullMethodName:    models.RoleViewer,
+	pb.EngineService_ListEngines_FullMethodName:  models.RoleViewer,
+	pb.EngineService_CreateEngine_FullMethodName: models.RoleEditor,
+	pb.EngineService_UpdateEngine_FullMethodName: models.RoleEditor,
+	pb.EngineService_DeleteEngine_FullMethodName: models.RoleAdmin,
+}
+
+// publicServices need no credentials.
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// MetricInterceptor counts requests by method and status code and
+// records their duration.
+func MetricInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	requestDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	requestCounter.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+
+	return resp, err
+}
+
+// AuthInterceptor authenticates calls with the "authorization: Bearer
+// <jwt>" or "x-api-key" metadata, like AuthMiddleware, and then checks the
+// caller's role against methodPermissions.
+func AuthInterceptor(keySet *keys.KeySet, revoked middleware.RevocationChecker, apiKeys middleware.APIKeyAuthenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for _, prefix := range publicServices {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx, err := middleware.Authenticate(ctx, keySet, revoked, apiKeys, firstValue(md, "authorization"), firstValue(md, "x-api-key"))
+		if err != nil {
+			var authErr *middleware.AuthError
+			if errors.As(err, &authErr) && authErr.Status == http.StatusUnauthorized {
+				return nil, status.Error(codes.Unauthenticated, authErr.Message)
+			}
+			log.Println("Error authenticating request: ", err)
+			return nil, status.Error(codes.Internal, "failed to authenticate")
+		}
+
+		role, _ := ctx.Value("role").(string)
+
+		required, ok := methodPermissions[info.FullMethod]
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("no permission is defined for %s", info.FullMethod))
+		}
+		if !models.RoleAtLeast(role, required) {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("role %q is not allowed to call %s", role, info.FullMethod))
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"Car-Management-System/keys"
+	"Car-Management-System/middleware"
+	"Car-Management-System/models"
+	pb "Car-Management-System/proto/carmanagement/v1"
+	"Car-Management-System/service"
+	"context"
+	"errors"
+	"log"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer returns a gRPC server for cars and engines. Calls are traced
+// with OpenTelemetry, counted in Prometheus and authenticated like the
+// REST API, with the same roles per operation. The health and reflection
+// services need no credentials.
+func NewServer(cars service.CarServiceInterface, engines service.EngineServiceInterface, keySet *keys.KeySet, revoked middleware.RevocationChecker, apiKeys middleware.APIKeyAuthenticator) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			MetricInterceptor,
+			AuthInterceptor(keySet, revoked, apiKeys),
+		),
+	)
+
+	pb.RegisterCarServiceServer(server, NewCarServer(cars, engines))
+	pb.RegisterEngineServiceServer(server, NewEngineServer(engines))
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return server
+}
+
+// serviceError maps the errors of the service layer to gRPC statuses the
+// way the REST handlers map them to HTTP statuses.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrValidation):
+		return invalidArgument(err)
+	case errors.Is(err, models.ErrInvalidSortField), errors.Is(err, models.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrVersionMismatch),
+		errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrForeignKey):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		log.Println("Error : ", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// expectedVersion returns the version a write is conditional on. Like
+// If-Match on the REST API it is required, so that a client cannot
+// overwrite a change it has not seen by leaving it out; "if-match: *"
+// metadata opts out and returns 0.
+func expectedVersion(ctx context.Context, version int64) (int64, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	unconditional := firstValue(md, "if-match") == "*"
+
+	switch {
+	case unconditional && version != 0:
+		return 0, status.Error(codes.InvalidArgument, "expected_version and if-match: * cannot be used together")
+	case unconditional:
+		return 0, nil
+	case version < 1:
+		return 0, status.Error(codes.FailedPrecondition, "expected_version is required; send the version returned by a get, or if-match: * metadata to write unconditionally")
+	}
+	return version, nil
+}
+
+// invalidArgument reports a request that failed validation, with each
+// violation as a field violation of a BadRequest detail.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	var validationErr *models.ValidationError
+	if !errors.As(err, &validationErr) {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Errors {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Message,
+			Reason:      violation.Code,
+		})
+	}
+
+	detailed, detailErr := st.WithDetails(badRequest)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,119 @@
+package apikey
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyServiceInterface
+}
+
+func NewAPIKeyHandler(service service.APIKeyServiceInterface) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+	}
+}
+
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("APIKeyHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateAPIKey-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var keyReq models.APIKeyRequest
+	if err := json.Unmarshal(body, &keyReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return
+	}
+
+	createdKey, err := h.service.CreateAPIKey(ctx, userName, role, &keyReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdKey)
+}
+
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("APIKeyHandler")
+	ctx, span := tracer.Start(r.Context(), "ListAPIKeys-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+
+	keys, err := h.service.ListAPIKeys(ctx, userName)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("APIKeyHandler")
+	ctx, span := tracer.Start(r.Context(), "RevokeAPIKey-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+	id := mux.Vars(r)["id"]
+
+	revokedKey, err := h.service.RevokeAPIKey(ctx, userName, role, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, revokedKey)
+}
+
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidAPIKeyRequest):
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrAPIKeyForbidden):
+		problem.WriteStatus(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrAPIKeyNotFound), errors.Is(err, models.ErrUserNotFound):
+		problem.WriteStatus(w, r, http.StatusNotFound, err.Error())
+	default:
+		problem.Write(w, r, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("Error while marshalling : ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
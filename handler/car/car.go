@@ -1,12 +1,21 @@
 package car
 
 import (
+	"Car-Management-System/handler/etag"
+	"Car-Management-System/handler/export"
+	"Car-Management-System/handler/importer"
+	"Car-Management-System/handler/problem"
 	"Car-Management-System/models"
 	"Car-Management-System/service"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.opentelemetry.io/otel"
@@ -30,96 +39,125 @@ func (h *CarHandler) GetCarByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	resp, err := h.service.GetCarById(ctx, id)
+	includeDeleted := r.URL.Query().Get("includeDeleted") == "true"
+
+	var resp *models.Car
+	var err error
+
+	asOfParam := r.URL.Query().Get("asOf")
+	if asOfParam != "" {
+		asOf, parseErr := parseAsOf(asOfParam)
+		if parseErr != nil {
+			problem.WriteStatus(w, r, http.StatusBadRequest, parseErr.Error())
+			return
+		}
+		resp, err = h.service.GetCarAsOf(ctx, id, asOf)
+	} else {
+		resp, err = h.service.GetCarById(ctx, id, includeDeleted)
+	}
+
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	body, err := json.Marshal(resp)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	_, err = w.Write(body)
-	if err != nil {
-		log.Println("Error writing response : ", err)
+	// A historical snapshot is not the live row, so it gets a weak tag that
+	// can only be used for caching, never for If-Match.
+	tag := etag.Version(resp.Version, resp.Engine.Version)
+	if asOfParam != "" {
+		tag = etag.Body(body)
 	}
+
+	etag.WriteJSON(w, r, tag, body)
 }
 
-func (h *CarHandler) GetCarByBrand(w http.ResponseWriter, r *http.Request) {
+func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
-	ctx, span := tracer.Start(r.Context(), "GetCarByBrand-Handler")
+	ctx, span := tracer.Start(r.Context(), "CreateCar-Handler")
 	defer span.End()
 
-	brand := r.URL.Query().Get("brand")
-	isEngine := r.URL.Query().Get("isEngine") == "true"
-
-	resp, err := h.service.GetCarsByBrand(ctx, brand, isEngine)
+	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	body, err := json.Marshal(resp)
+	var carReq models.CarRequest
+	err = json.Unmarshal(body, &carReq)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error : ", err)
+		problem.WriteStatus(w, r, http.StatusBadRequest, "request body is not valid JSON: "+err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	createdCar, err := h.service.CreateCar(ctx, &carReq)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
 
-	_, err = w.Write(body)
+	responseBody, err := json.Marshal(createdCar)
 	if err != nil {
-		log.Println("Error writing response : ", err)
+		problem.Write(w, r, err)
+		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	_, _ = w.Write(responseBody)
 }
 
-func (h *CarHandler) CreateCar(w http.ResponseWriter, r *http.Request) {
+func (h *CarHandler) ImportCars(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
-	ctx, span := tracer.Start(r.Context(), "CreateCar-Handler")
+	ctx, span := tracer.Start(r.Context(), "ImportCars-Handler")
 	defer span.End()
 
-	body, err := io.ReadAll(r.Body)
+	format, err := importer.Format(r)
 	if err != nil {
-		log.Println("Error : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
-	var carReq models.CarRequest
-	err = json.Unmarshal(body, &carReq)
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	importRows, err := importer.ReadCars(http.MaxBytesReader(w, r.Body, importer.MaxBodyBytes), format)
 	if err != nil {
-		log.Println("Error : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
-	createdCar, err := h.service.CreateCar(ctx, &carReq)
-	if err != nil {
-		log.Println("Error Creating Car : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	report, err := h.service.ImportCars(ctx, importRows, dryRun)
+	if err != nil && !errors.Is(err, models.ErrImportRejected) {
+		if errors.Is(err, models.ErrForeignKey) {
+			problem.WriteStatus(w, r, http.StatusConflict, "an engine was deleted during the import, nothing was imported")
+			return
+		}
+		problem.Write(w, r, err)
 		return
 	}
 
-	responseBody, err := json.Marshal(createdCar)
+	status := http.StatusCreated
+	switch {
+	case err != nil:
+		status = http.StatusUnprocessableEntity
+	case dryRun:
+		status = http.StatusOK
+	}
+
+	responseBody, err := json.Marshal(report)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while marshalling : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 
 	_, _ = w.Write(responseBody)
 }
@@ -132,32 +170,34 @@ func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var carReq models.CarRequest
 	err = json.Unmarshal(body, &carReq)
 	if err != nil {
-		log.Println("Error : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.WriteStatus(w, r, http.StatusBadRequest, "request body is not valid JSON: "+err.Error())
 		return
 	}
 
-	updatedCar, err := h.service.UpdateCar(ctx, id, &carReq)
+	updatedCar, err := h.service.UpdateCar(ctx, id, &carReq, expectedVersion)
 	if err != nil {
-		log.Println("Error Updating Car : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseBody, err := json.Marshal(updatedCar)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while marshalling : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -168,6 +208,50 @@ func (h *CarHandler) UpdateCar(w http.ResponseWriter, r *http.Request) {
 
 }
 
+func (h *CarHandler) PatchCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "PatchCar-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	patch, err := models.NewPatch(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		w.Header().Set("Accept-Patch", models.MergePatchType+", "+models.JSONPatchType)
+		problem.Write(w, r, err)
+		return
+	}
+
+	patchedCar, err := h.service.PatchCar(ctx, id, patch, expectedVersion)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseBody, err := json.Marshal(patchedCar)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(responseBody)
+}
+
 func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("CarHandler")
 	ctx, span := tracer.Start(r.Context(), "DeleteCar-Handler")
@@ -175,17 +259,21 @@ func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
-	deletedCar, err := h.service.DeleteCar(ctx, id)
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	deletedCar, err := h.service.DeleteCar(ctx, id, expectedVersion)
 	if err != nil {
-		log.Println("Error Updating Car : ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 
 	responseBody, err := json.Marshal(deletedCar)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while marshalling : ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -196,4 +284,248 @@ func (h *CarHandler) DeleteCar(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		log.Println("Error writing response : ", err)
 	}
-}
\ No newline at end of file
+}
+
+func (h *CarHandler) ListCars(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "ListCars-Handler")
+	defer span.End()
+
+	filter, err := parseCarFilter(r.URL.Query())
+	if err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := h.service.ListCars(ctx, filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	etag.WriteJSON(w, r, etag.Body(body), body)
+}
+
+// ExportCars streams every car matching the listing filters with its
+// engine. Rows go out as they are read, so an error part way through can
+// only cut the file short; it is logged.
+func (h *CarHandler) ExportCars(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "ExportCars-Handler")
+	defer span.End()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.CSV
+	}
+
+	contentType, err := export.ContentType(format)
+	if err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := parseCarFilter(r.URL.Query())
+	if err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var writer export.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="cars-%s.%s"`, time.Now().UTC().Format("20060102"), format))
+		w.WriteHeader(http.StatusOK)
+
+		var err error
+		writer, err = export.NewWriter(w, format, "Cars", export.CarColumns)
+		return err
+	}
+
+	err = h.service.ExportCars(ctx, filter, func(car models.Car) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(car, export.CarValues(car))
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+
+	if err != nil {
+		if writer != nil {
+			log.Println("Error exporting cars, response cut short : ", err)
+			return
+		}
+		problem.Write(w, r, err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Println("Error exporting cars, response cut short : ", err)
+	}
+}
+
+func (h *CarHandler) RestoreCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "RestoreCar-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	restoredCar, err := h.service.RestoreCar(ctx, id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	responseBody, err := json.Marshal(restoredCar)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(responseBody)
+}
+
+func (h *CarHandler) PurgeCar(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "PurgeCar-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.PurgeCar(ctx, id); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetCarByVIN returns the car with the VIN in the path, which may be in
+// any case.
+func (h *CarHandler) GetCarByVIN(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "GetCarByVIN-Handler")
+	defer span.End()
+
+	includeDeleted := r.URL.Query().Get("includeDeleted") == "true"
+
+	resp, err := h.service.GetCarByVIN(ctx, mux.Vars(r)["vin"], includeDeleted)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	etag.WriteJSON(w, r, etag.Version(resp.Version, resp.Engine.Version), body)
+}
+
+func (h *CarHandler) GetCarHistory(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("CarHandler")
+	ctx, span := tracer.Start(r.Context(), "GetCarHistory-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	history, err := h.service.GetCarHistory(ctx, id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := json.Marshal(history)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	etag.WriteJSON(w, r, etag.Body(body), body)
+}
+
+// parseAsOf accepts an RFC 3339 timestamp or a plain date, which is read
+// as the end of that day in UTC.
+func parseAsOf(value string) (time.Time, error) {
+	if asOf, err := time.Parse(time.RFC3339, value); err == nil {
+		return asOf, nil
+	}
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		return day.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, errors.New("asOf must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
+
+func parseCarFilter(query url.Values) (*models.CarFilter, error) {
+	filter := &models.CarFilter{
+		Brand:          query.Get("brand"),
+		FuelType:       query.Get("fuelType"),
+		SortBy:         query.Get("sortBy"),
+		Order:          query.Get("order"),
+		Cursor:         query.Get("cursor"),
+		IsEngine:       query.Get("isEngine") == "true",
+		IncludeDeleted: query.Get("includeDeleted") == "true",
+	}
+
+	ints := map[string]*int{
+		"minYear": &filter.MinYear,
+		"maxYear": &filter.MaxYear,
+		"limit":   &filter.Limit,
+	}
+	for key, dst := range ints {
+		if v := query.Get(key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a valid number", key)
+			}
+			*dst = n
+		}
+	}
+
+	int32s := map[string]*int32{
+		"minDisplacement": &filter.MinDisplacement,
+		"maxDisplacement": &filter.MaxDisplacement,
+		"cylinders":       &filter.Cylinders,
+	}
+	for key, dst := range int32s {
+		if v := query.Get(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a valid number", key)
+			}
+			*dst = int32(n)
+		}
+	}
+
+	floats := map[string]*float64{
+		"minPrice": &filter.MinPrice,
+		"maxPrice": &filter.MaxPrice,
+	}
+	for key, dst := range floats {
+		if v := query.Get(key); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a valid number", key)
+			}
+			*dst = n
+		}
+	}
+
+	return filter, nil
+}
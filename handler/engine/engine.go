@@ -1,12 +1,18 @@
 package engine
 
 import (
+	"Car-Management-System/handler/etag"
+	"Car-Management-System/handler/importer"
+	"Car-Management-System/handler/problem"
 	"Car-Management-System/models"
 	"Car-Management-System/service"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -31,27 +37,21 @@ func (e *EngineHandler) GetEngineById(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	resp, err := e.service.GetEngineById(ctx, id)
+	includeDeleted := r.URL.Query().Get("includeDeleted") == "true"
+
+	resp, err := e.service.GetEngineById(ctx, id, includeDeleted)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	body, err := json.Marshal(resp)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	_, err = w.Write(body)
-	if err != nil {
-		log.Println("Error writing response : ", err)
-	}
+	etag.WriteJSON(w, r, etag.Version(resp.Version), body)
 }
 
 func (e *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
@@ -61,31 +61,26 @@ func (e *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
 
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error reading request body ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var engineReq models.EngineRequest
 	err = json.Unmarshal(body, &engineReq)
 	if err != nil {
-		log.Println("Error Unmarshalling engine request body:", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.WriteStatus(w, r, http.StatusBadRequest, "request body is not valid JSON: "+err.Error())
 		return
 	}
 
 	createdEngine, err := e.service.CreateEngine(ctx, &engineReq)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while creating engine: ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	resBody, err := json.Marshal(createdEngine)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -95,6 +90,51 @@ func (e *EngineHandler) CreateEngine(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(resBody)
 }
 
+func (e *EngineHandler) ImportEngines(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandler")
+	ctx, span := tracer.Start(r.Context(), "ImportEngines-Handler")
+	defer span.End()
+
+	format, err := importer.Format(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	importRows, err := importer.ReadEngines(http.MaxBytesReader(w, r.Body, importer.MaxBodyBytes), format)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	report, err := e.service.ImportEngines(ctx, importRows, dryRun)
+	if err != nil && !errors.Is(err, models.ErrImportRejected) {
+		problem.Write(w, r, err)
+		return
+	}
+
+	status := http.StatusCreated
+	switch {
+	case err != nil:
+		status = http.StatusUnprocessableEntity
+	case dryRun:
+		status = http.StatusOK
+	}
+
+	resBody, err := json.Marshal(report)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, _ = w.Write(resBody)
+}
+
 func (e *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("EngineHandler")
 	ctx, span := tracer.Start(r.Context(), "UpdateEngine-Handler")
@@ -103,33 +143,34 @@ func (e *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		log.Println("Error reading request body ", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	var engineReq models.EngineRequest
 	err = json.Unmarshal(body, &engineReq)
 	if err != nil {
-		log.Println("Error Unmarshalling engine request body:", err)
-		w.WriteHeader(http.StatusBadRequest)
+		problem.WriteStatus(w, r, http.StatusBadRequest, "request body is not valid JSON: "+err.Error())
 		return
 	}
 
-	updatedEngine, err := e.service.UpdateEngine(ctx, id, &engineReq)
+	updatedEngine, err := e.service.UpdateEngine(ctx, id, &engineReq, expectedVersion)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while updating engine: ", err)
+		problem.Write(w, r, err)
 		return
 	}
 
 	resBody, err := json.Marshal(updatedEngine)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -139,6 +180,51 @@ func (e *EngineHandler) UpdateEngine(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(resBody)
 }
 
+func (e *EngineHandler) PatchEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandler")
+	ctx, span := tracer.Start(r.Context(), "PatchEngine-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	patch, err := models.NewPatch(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		w.Header().Set("Accept-Patch", models.MergePatchType+", "+models.JSONPatchType)
+		problem.Write(w, r, err)
+		return
+	}
+
+	patchedEngine, err := e.service.PatchEngine(ctx, id, patch, expectedVersion)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	resBody, err := json.Marshal(patchedEngine)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("ETag", etag.Version(patchedEngine.Version))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(resBody)
+}
+
 func (e *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
 	tracer := otel.Tracer("EngineHandler")
 	ctx, span := tracer.Start(r.Context(), "DeleteEngine-Handler")
@@ -146,31 +232,26 @@ func (e *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 
-	deletedEngine, err := e.service.DeleteEngine(ctx, id)
+	expectedVersion, err := etag.IfMatchVersion(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	deletedEngine, err := e.service.DeleteEngine(ctx, id, expectedVersion)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println("Error while deleting engine: ", err)
-		response := map[string]string{"error": "Invalid ID or Engine not found"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
+		problem.Write(w, r, err)
 		return
 	}
 
 	if deletedEngine.EngineID == uuid.Nil {
-		w.WriteHeader(http.StatusNotFound)
-		response := map[string]string{"error": "Engine Not Found"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
+		problem.Write(w, r, models.ErrEngineNotFound)
 		return
 	}
 
 	resBody, err := json.Marshal(deletedEngine)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		log.Println(err)
-		response := map[string]string{"error": "Invalid ID or Engine not found"}
-		jsonResponse, _ := json.Marshal(response)
-		_, _ = w.Write(jsonResponse)
+		problem.Write(w, r, err)
 		return
 	}
 
@@ -179,3 +260,104 @@ func (e *EngineHandler) DeleteEngine(w http.ResponseWriter, r *http.Request) {
 
 	_, _ = w.Write(resBody)
 }
+
+func (e *EngineHandler) RestoreEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandler")
+	ctx, span := tracer.Start(r.Context(), "RestoreEngine-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	restoredEngine, err := e.service.RestoreEngine(ctx, id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	resBody, err := json.Marshal(restoredEngine)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(resBody)
+}
+
+func (e *EngineHandler) PurgeEngine(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandler")
+	ctx, span := tracer.Start(r.Context(), "PurgeEngine-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	if err := e.service.PurgeEngine(ctx, id); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *EngineHandler) ListEngines(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EngineHandler")
+	ctx, span := tracer.Start(r.Context(), "ListEngines-Handler")
+	defer span.End()
+
+	filter, err := parseEngineFilter(r.URL.Query())
+	if err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := e.service.ListEngines(ctx, filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	etag.WriteJSON(w, r, etag.Body(body), body)
+}
+
+func parseEngineFilter(query url.Values) (*models.EngineFilter, error) {
+	filter := &models.EngineFilter{
+		Cursor:         query.Get("cursor"),
+		WithCars:       query.Get("withCars") == "true",
+		IncludeDeleted: query.Get("includeDeleted") == "true",
+	}
+
+	if v := query.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.New("limit must be a valid number")
+		}
+		filter.Limit = n
+	}
+
+	int32s := map[string]*int32{
+		"minDisplacement": &filter.MinDisplacement,
+		"maxDisplacement": &filter.MaxDisplacement,
+		"cylinders":       &filter.Cylinders,
+		"minRange":        &filter.MinRange,
+		"maxRange":        &filter.MaxRange,
+	}
+	for key, dst := range int32s {
+		if v := query.Get(key); v != "" {
+			n, err := strconv.ParseInt(v, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a valid number", key)
+			}
+			*dst = int32(n)
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,101 @@
+package etag
+
+import (
+	"Car-Management-System/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidIfMatch  = errors.New("If-Match must be a single entity tag returned by a GET, or *")
+	ErrIfMatchRequired = errors.New("If-Match is required; send the ETag returned by a GET, or * to write unconditionally")
+)
+
+// Version formats a strong entity tag from row versions. A car's tag
+// carries its own version and its engine's, e.g. "4.2", because the car
+// representation embeds the engine.
+func Version(versions ...int64) string {
+	parts := make([]string, len(versions))
+	for i, v := range versions {
+		parts[i] = strconv.FormatInt(v, 10)
+	}
+	return `"` + strings.Join(parts, ".") + `"`
+}
+
+// Body returns a weak entity tag derived from a response body, for
+// representations such as lists that have no single row version.
+func Body(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// IfMatchVersion returns the row version a write is conditional on, taken
+// from the first component of the If-Match tag. The header is required so
+// that a client cannot overwrite a change it has not seen by leaving it
+// out; "*" opts out and returns 0. Weak tags never match, as If-Match uses
+// strong comparison.
+func IfMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, ErrIfMatchRequired
+	}
+	if header == "*" {
+		return 0, nil
+	}
+	if strings.Contains(header, ",") {
+		return 0, ErrInvalidIfMatch
+	}
+	if strings.HasPrefix(header, "W/") {
+		return 0, models.ErrVersionMismatch
+	}
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrInvalidIfMatch
+	}
+
+	first, _, _ := strings.Cut(header[1:len(header)-1], ".")
+	version, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidIfMatch
+	}
+	return version, nil
+}
+
+// NoneMatch reports whether the If-None-Match header matches tag, using
+// the weak comparison RFC 9110 prescribes for it.
+func NoneMatch(r *http.Request, tag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes a 200 JSON response carrying tag, or an empty 304 when
+// the client already holds that version.
+func WriteJSON(w http.ResponseWriter, r *http.Request, tag string, body []byte) {
+	w.Header().Set("ETag", tag)
+
+	if NoneMatch(r, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(body); err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
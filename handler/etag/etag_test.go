@@ -0,0 +1,84 @@
+package etag
+
+import (
+	"Car-Management-System/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		err     error
+	}{
+		{name: "missing header", header: "", err: ErrIfMatchRequired},
+		{name: "blank header", header: "   ", err: ErrIfMatchRequired},
+		{name: "wildcard", header: "*", version: 0},
+		{name: "quoted version", header: `"4"`, version: 4},
+		{name: "car tag with engine version", header: `"4.2"`, version: 4},
+		{name: "surrounding whitespace", header: ` "7" `, version: 7},
+		{name: "weak tag", header: `W/"4"`, err: models.ErrVersionMismatch},
+		{name: "unquoted version", header: "4", err: ErrInvalidIfMatch},
+		{name: "missing closing quote", header: `"4`, err: ErrInvalidIfMatch},
+		{name: "lone quote", header: `"`, err: ErrInvalidIfMatch},
+		{name: "empty tag", header: `""`, err: ErrInvalidIfMatch},
+		{name: "list of tags", header: `"4", "5"`, err: ErrInvalidIfMatch},
+		{name: "zero version", header: `"0"`, err: ErrInvalidIfMatch},
+		{name: "negative version", header: `"-1"`, err: ErrInvalidIfMatch},
+		{name: "body hash", header: `"0a1b2c3d"`, err: ErrInvalidIfMatch},
+		{name: "garbage", header: "not an etag", err: ErrInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/cars/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := IfMatchVersion(r)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Fatalf("IfMatchVersion(%q) error = %v, want %v", tt.header, err, tt.err)
+			}
+			if version != tt.version {
+				t.Fatalf("IfMatchVersion(%q) = %d, want %d", tt.header, version, tt.version)
+			}
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		tag    string
+		want   bool
+	}{
+		{name: "missing header", header: "", tag: `"4.2"`, want: false},
+		{name: "same strong tag", header: `"4.2"`, tag: `"4.2"`, want: true},
+		{name: "different tag", header: `"4.1"`, tag: `"4.2"`, want: false},
+		{name: "wildcard", header: "*", tag: `"4.2"`, want: true},
+		{name: "weak header against strong tag", header: `W/"4.2"`, tag: `"4.2"`, want: true},
+		{name: "strong header against weak tag", header: `"abc"`, tag: `W/"abc"`, want: true},
+		{name: "match in a list", header: `"1.1", W/"4.2"`, tag: `"4.2"`, want: true},
+		{name: "no match in a list", header: `"1.1", "2.1"`, tag: `"4.2"`, want: false},
+		{name: "garbage", header: "not an etag", tag: `"4.2"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/cars/1", nil)
+			if tt.header != "" {
+				r.Header.Set("If-None-Match", tt.header)
+			}
+
+			if got := NoneMatch(r, tt.tag); got != tt.want {
+				t.Fatalf("NoneMatch(%q, %q) = %v, want %v", tt.header, tt.tag, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package events
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// HeartbeatInterval is how often an idle stream sends a comment, so
+// proxies and clients can tell a quiet stream from a dead connection.
+const HeartbeatInterval = 15 * time.Second
+
+// Authenticator checks again that the credentials r was sent with are
+// still valid, e.g. that its token has not been revoked since.
+type Authenticator func(ctx context.Context, r *http.Request) error
+
+type EventsHandler struct {
+	service      service.EventStreamServiceInterface
+	authenticate Authenticator
+	heartbeat    time.Duration
+}
+
+func NewEventsHandler(service service.EventStreamServiceInterface, authenticate Authenticator) *EventsHandler {
+	return &EventsHandler{
+		service:      service,
+		authenticate: authenticate,
+		heartbeat:    HeartbeatInterval,
+	}
+}
+
+// StreamEvents sends car and engine events as Server-Sent Events. The
+// "type" query parameter selects event types, repeated or comma
+// separated, and "brand" selects the events of cars of one brand. A
+// client reconnecting with Last-Event-ID is sent the events it missed.
+//
+// A stream opened with a token ends when the token expires, and every
+// stream ends at the first heartbeat after its credentials are revoked.
+// The client then has to reconnect with valid ones.
+func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("EventsHandler")
+	_, span := tracer.Start(r.Context(), "StreamEvents-Handler")
+
+	var types []string
+	for _, value := range r.URL.Query()["type"] {
+		for _, eventType := range strings.Split(value, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				types = append(types, eventType)
+			}
+		}
+	}
+
+	filter, err := models.NewEventStreamFilter(types, r.URL.Query().Get("brand"))
+	if err != nil {
+		span.End()
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		span.End()
+		log.Println("Error : response writer does not support flushing")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// The stream can stay open for hours, so the span only covers setting
+	// it up.
+	span.End()
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if expiresAt, ok := r.Context().Value("token_expires_at").(time.Time); ok {
+		ctx, cancel = context.WithDeadline(r.Context(), expiresAt)
+	} else {
+		ctx, cancel = context.WithCancel(r.Context())
+	}
+	stream := &eventWriter{w: w, flusher: flusher}
+
+	// The heartbeat must stop writing before the handler returns.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stream.heartbeat(ctx, cancel, h.heartbeat, func(ctx context.Context) error {
+			return h.authenticate(ctx, r)
+		})
+	}()
+
+	err = h.service.Stream(ctx, filter, r.Header.Get("Last-Event-ID"), stream.writeEvent)
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		log.Println("Error streaming events : ", err)
+	}
+
+	cancel()
+	wg.Wait()
+}
+
+// eventWriter writes events and heartbeats to one stream. Both come from
+// different goroutines, so writes are serialized.
+type eventWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (e *eventWriter) writeEvent(event models.Event) error {
+	if event.Type == models.EventStreamReset {
+		return e.write("id:\nevent: reset\ndata: {}\n\n")
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return e.write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.Sequence, event.Type, data))
+}
+
+// heartbeat writes a comment every interval while authenticate succeeds,
+// and cancels the stream once it fails or the connection is gone.
+func (e *eventWriter) heartbeat(ctx context.Context, cancel context.CancelFunc, interval time.Duration, authenticate func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := authenticate(ctx); err != nil {
+				if ctx.Err() == nil {
+					log.Println("Closing event stream : ", err)
+				}
+				cancel()
+				return
+			}
+			if err := e.write(": heartbeat\n\n"); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (e *eventWriter) write(message string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, err := fmt.Fprint(e.w, message); err != nil {
+		return err
+	}
+	e.flusher.Flush()
+	return nil
+}
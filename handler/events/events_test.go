@@ -0,0 +1,135 @@
+package events
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingStream sends nothing and returns once the stream's context ends.
+type blockingStream struct{}
+
+func (blockingStream) Stream(ctx context.Context, filter models.EventStreamFilter, lastEventID string, send func(models.Event) error) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// syncRecorder is a ResponseRecorder that can be read while the handler
+// is still writing to it.
+type syncRecorder struct {
+	mu sync.Mutex
+	*httptest.ResponseRecorder
+}
+
+func (s *syncRecorder) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ResponseRecorder.Write(b)
+}
+
+func (s *syncRecorder) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ResponseRecorder.Flush()
+}
+
+func (s *syncRecorder) body() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ResponseRecorder.Body.String()
+}
+
+// serve runs StreamEvents until it returns or the timeout passes, and
+// reports how long it ran.
+func serve(t *testing.T, handler *EventsHandler, r *http.Request, timeout time.Duration) (*syncRecorder, time.Duration) {
+	t.Helper()
+
+	recorder := &syncRecorder{ResponseRecorder: httptest.NewRecorder()}
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		defer close(done)
+		handler.StreamEvents(recorder, r)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("stream still open after %v", timeout)
+	}
+	return recorder, time.Since(start)
+}
+
+func newHandler(interval time.Duration, authenticate Authenticator) *EventsHandler {
+	handler := NewEventsHandler(blockingStream{}, authenticate)
+	handler.heartbeat = interval
+	return handler
+}
+
+func TestStreamEventsEndsWhenTokenExpires(t *testing.T) {
+	handler := newHandler(time.Hour, func(ctx context.Context, r *http.Request) error { return nil })
+
+	expiresAt := time.Now().Add(100 * time.Millisecond)
+	r := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	r = r.WithContext(context.WithValue(r.Context(), "token_expires_at", expiresAt))
+
+	recorder, elapsed := serve(t, handler, r, 2*time.Second)
+	if elapsed < 100*time.Millisecond {
+		t.Fatalf("stream ended after %v, before the token expired", elapsed)
+	}
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+}
+
+func TestStreamEventsEndsWhenCredentialsAreRevoked(t *testing.T) {
+	var checks atomic.Int32
+	handler := newHandler(20*time.Millisecond, func(ctx context.Context, r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("authenticated a request without its Authorization header")
+		}
+		if checks.Add(1) > 2 {
+			return errors.New("Token has been revoked")
+		}
+		return nil
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/events/stream", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	r = r.WithContext(context.WithValue(r.Context(), "token_expires_at", time.Now().Add(time.Hour)))
+
+	recorder, _ := serve(t, handler, r, 2*time.Second)
+	if got := checks.Load(); got != 3 {
+		t.Fatalf("credentials checked %d times, want 3", got)
+	}
+	if got := strings.Count(recorder.body(), ": heartbeat\n\n"); got != 2 {
+		t.Fatalf("sent %d heartbeats, want 2 before the revocation", got)
+	}
+}
+
+func TestStreamEventsWithoutTokenStaysOpen(t *testing.T) {
+	var checks atomic.Int32
+	handler := newHandler(10*time.Millisecond, func(ctx context.Context, r *http.Request) error {
+		checks.Add(1)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	r := httptest.NewRequest(http.MethodGet, "/events/stream", nil).WithContext(ctx)
+
+	_, elapsed := serve(t, handler, r, 2*time.Second)
+	if elapsed < 200*time.Millisecond {
+		t.Fatalf("stream ended after %v, before the client disconnected", elapsed)
+	}
+	if checks.Load() == 0 {
+		t.Fatal("credentials were never checked again")
+	}
+}
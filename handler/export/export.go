@@ -0,0 +1,262 @@
+package export
+
+import (
+	"Car-Management-System/models"
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+	XLSX   = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("format must be one of: csv, ndjson, xlsx")
+
+var contentTypes = map[string]string{
+	CSV:    "text/csv; charset=utf-8",
+	NDJSON: "application/x-ndjson",
+	XLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// CarColumns are the JSON field names of a car, with the engine's fields
+// prefixed by "engine.".
+var CarColumns = []string{
+	"id", "Name", "year", "brand", "fuel_type", "vin", "price", "version", "created_at", "updated_at", "deleted_at",
+	"engine.enigne_id", "engine.displacement", "engine.noOfCylinders", "engine.carRange",
+	"engine.powertrain", "engine.batteryKWh", "engine.chargePowerKW", "engine.motorPowerKW", "engine.motorTorqueNm", "engine.version",
+}
+
+// Writer writes one record at a time. NDJSON writes record itself, as the
+// API would return it; CSV and XLSX write values under the column header.
+type Writer interface {
+	Write(record interface{}, values []interface{}) error
+	Close() error
+}
+
+// CarValues returns the values of car in the order of CarColumns.
+func CarValues(car models.Car) []interface{} {
+	return []interface{}{
+		car.ID, car.Name, car.Year, car.Brand, car.FuelType, car.VIN, car.Price, car.Version, car.CreatedAt, car.UpdatedAt, car.DeletedAt,
+		car.Engine.EngineID, car.Engine.Displacement, car.Engine.NoOfCylinders, car.Engine.CarRange,
+		car.Engine.Type, car.Engine.BatteryKWh, car.Engine.ChargePowerKW, car.Engine.MotorPowerKW, car.Engine.MotorTorqueNm, car.Engine.Version,
+	}
+}
+
+// ContentType returns the media type of format, or ErrUnsupportedFormat.
+func ContentType(format string) (string, error) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return "", ErrUnsupportedFormat
+	}
+	return contentType, nil
+}
+
+// NewWriter starts an export in format on w, writing the header row for
+// CSV and XLSX straight away.
+func NewWriter(w io.Writer, format string, sheetName string, columns []string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w, columns)
+	case NDJSON:
+		return &ndjsonWriter{buf: bufio.NewWriter(w)}, nil
+	case XLSX:
+		return newXLSXWriter(w, sheetName, columns)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, columns []string) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer, record: make([]string, len(columns))}, nil
+}
+
+func (c *csvWriter) Write(_ interface{}, values []interface{}) error {
+	for i, value := range values {
+		c.record[i], _ = formatValue(value)
+	}
+	return c.writer.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonWriter struct {
+	buf *bufio.Writer
+}
+
+func (n *ndjsonWriter) Write(record interface{}, _ []interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := n.buf.Write(line); err != nil {
+		return err
+	}
+	return n.buf.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.buf.Flush()
+}
+
+// xlsxWriter streams a single-sheet workbook. The fixed parts of the
+// package are written first so the worksheet can be the last zip entry
+// and its rows can go out as they are produced.
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+	xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+func newXLSXWriter(w io.Writer, sheetName string, columns []string) (*xlsxWriter, error) {
+	zw := zip.NewWriter(w)
+
+	var escapedName strings.Builder
+	if err := xml.EscapeText(&escapedName, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, escapedName.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	x := &xlsxWriter{zip: zw, sheet: bufio.NewWriter(f)}
+	if _, err := x.sheet.WriteString(xlsxSheetStart); err != nil {
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+	if err := x.Write(nil, header); err != nil {
+		return nil, err
+	}
+
+	return x, nil
+}
+
+func (x *xlsxWriter) Write(_ interface{}, values []interface{}) error {
+	x.sheet.WriteString("<row>")
+	for _, value := range values {
+		text, numeric := formatValue(value)
+		switch {
+		case text == "":
+			x.sheet.WriteString("<c/>")
+		case numeric:
+			x.sheet.WriteString("<c><v>" + text + "</v></c>")
+		default:
+			x.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+			if err := xml.EscapeText(x.sheet, []byte(text)); err != nil {
+				return err
+			}
+			x.sheet.WriteString("</t></is></c>")
+		}
+	}
+	_, err := x.sheet.WriteString("</row>")
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := x.sheet.WriteString(xlsxSheetEnd); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+// formatValue renders a value as text and reports whether it is a number,
+// so spreadsheets can store it as one.
+func formatValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, false
+	case int:
+		return strconv.Itoa(v), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case uuid.UUID:
+		return v.String(), false
+	case time.Time:
+		return v.UTC().Format(time.RFC3339), false
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return v.UTC().Format(time.RFC3339), false
+	default:
+		return fmt.Sprint(v), false
+	}
+}
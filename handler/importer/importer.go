@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"Car-Management-System/models"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const (
+	CSV    = "csv"
+	NDJSON = "ndjson"
+
+	// MaxRows caps a single import so one request cannot hold a
+	// transaction open indefinitely.
+	MaxRows = 10000
+
+	MaxBodyBytes = 32 << 20
+)
+
+// Row is one data row of an import file. CSV rows carry their fields by
+// column name; NDJSON rows carry the raw JSON object. Err is set for a row
+// that could not be split into fields.
+type Row struct {
+	Line   int
+	Fields map[string]string
+	JSON   []byte
+	Err    error
+}
+
+// Format picks the import format from the request's Content-Type.
+func Format(r *http.Request) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", models.ErrUnsupportedImportFormat
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return CSV, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return NDJSON, nil
+	default:
+		return "", models.ErrUnsupportedImportFormat
+	}
+}
+
+// Read splits body into rows. A CSV file must start with a header naming
+// every one of columns and any of optional, each once and in any order;
+// an optional column left out is read as empty. Blank NDJSON lines are
+// skipped.
+func Read(body io.Reader, format string, columns []string, optional ...string) ([]Row, error) {
+	var rows []Row
+	var err error
+
+	switch format {
+	case CSV:
+		rows, err = readCSV(body, columns, optional)
+	case NDJSON:
+		rows, err = readNDJSON(body)
+	default:
+		return nil, models.ErrUnsupportedImportFormat
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return nil, fmt.Errorf("%w: file is larger than %d bytes", models.ErrInvalidImport, maxBytesErr.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no rows", models.ErrInvalidImport)
+	}
+
+	return rows, nil
+}
+
+func readCSV(body io.Reader, columns []string, optional []string) ([]Row, error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	headerErr := fmt.Errorf("%w: header must name each of %s exactly once", models.ErrInvalidImport, strings.Join(columns, ", "))
+	if len(optional) > 0 {
+		headerErr = fmt.Errorf("%w, and may name %s", headerErr, strings.Join(optional, ", "))
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil, fmt.Errorf("%w: file has no rows", models.ErrInvalidImport)
+		case errors.Is(err, csv.ErrFieldCount):
+			return nil, headerErr
+		}
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+	}
+
+	known := map[string]bool{}
+	for _, column := range columns {
+		known[column] = true
+	}
+	for _, column := range optional {
+		known[column] = true
+	}
+
+	seen := map[string]bool{}
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+		if !known[column] || seen[column] {
+			return nil, headerErr
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	for _, column := range columns {
+		if !seen[column] {
+			return nil, headerErr
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr error
+		if errors.Is(err, csv.ErrFieldCount) {
+			rowErr = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+		} else if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("%w: %v", models.ErrInvalidImport, err)
+			}
+			return nil, err
+		}
+
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("%w: at most %d rows can be imported at once", models.ErrInvalidImport, MaxRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line, Err: rowErr}
+		if rowErr == nil {
+			row.Fields = make(map[string]string, len(record))
+			for i, value := range record {
+				row.Fields[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func readNDJSON(body io.Reader) ([]Row, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	var rows []Row
+	line := 0
+	for scanner.Scan() {
+		line++
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		if len(rows) == MaxRows {
+			return nil, fmt.Errorf("%w: at most %d rows can be imported at once", models.ErrInvalidImport, MaxRows)
+		}
+
+		rows = append(rows, Row{Line: line, JSON: append([]byte(nil), content...)})
+	}
+
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line %d is longer than 1 MiB", models.ErrInvalidImport, line+1)
+		}
+		return nil, err
+	}
+
+	return rows, nil
+}
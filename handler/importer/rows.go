@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"Car-Management-System/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ReadCars reads a car import file in format. Rows that cannot be decoded
+// carry their error for ImportCars to report.
+func ReadCars(body io.Reader, format string) ([]models.CarImportRow, error) {
+	rows, err := Read(body, format, carImportColumns, "vin")
+	if err != nil {
+		return nil, err
+	}
+
+	carRows := make([]models.CarImportRow, len(rows))
+	for i, row := range rows {
+		carRows[i] = carRow(row)
+	}
+	return carRows, nil
+}
+
+// ReadEngines reads an engine import file in format. Rows that cannot be
+// decoded carry their error for ImportEngines to report.
+func ReadEngines(body io.Reader, format string) ([]models.EngineImportRow, error) {
+	rows, err := Read(body, format, engineImportColumns, enginePowertrainColumns...)
+	if err != nil {
+		return nil, err
+	}
+
+	engineRows := make([]models.EngineImportRow, len(rows))
+	for i, row := range rows {
+		engineRows[i] = engineRow(row)
+	}
+	return engineRows, nil
+}
+
+var carImportColumns = []string{"Name", "year", "brand", "fuel_type", "engine_id", "price"}
+
+// carRow decodes an NDJSON row as a POST /cars body, or a CSV row
+// by its carImportColumns and an optional vin column.
+func carRow(row Row) models.CarImportRow {
+	importRow := models.CarImportRow{Line: row.Line, Err: row.Err}
+	if row.Err != nil {
+		return importRow
+	}
+
+	if row.JSON != nil {
+		decoder := json.NewDecoder(bytes.NewReader(row.JSON))
+		decoder.DisallowUnknownFields()
+		importRow.Err = decoder.Decode(&importRow.Car)
+		return importRow
+	}
+
+	engineID, err := uuid.Parse(row.Fields["engine_id"])
+	if err != nil {
+		importRow.Err = errors.New("engine_id must be a valid UUID")
+		return importRow
+	}
+
+	price, err := strconv.ParseFloat(row.Fields["price"], 32)
+	if err != nil {
+		importRow.Err = errors.New("price must be a valid number")
+		return importRow
+	}
+
+	importRow.Car = models.CarRequest{
+		Name:     row.Fields["Name"],
+		Year:     row.Fields["year"],
+		Brand:    row.Fields["brand"],
+		FuelType: row.Fields["fuel_type"],
+		VIN:      row.Fields["vin"],
+		Engine:   models.Engine{EngineID: engineID},
+		Price:    float32(price),
+	}
+	return importRow
+}
+
+var engineImportColumns = []string{"displacement", "noOfCylinders", "carRange"}
+
+// enginePowertrainColumns may be left out of a CSV import, or left empty
+// in a row, for ICE engines.
+var enginePowertrainColumns = []string{"powertrain", "batteryKWh", "chargePowerKW", "motorPowerKW", "motorTorqueNm"}
+
+// engineRow decodes an NDJSON row as a POST /engine body, or a CSV
+// row by its engineImportColumns and enginePowertrainColumns.
+func engineRow(row Row) models.EngineImportRow {
+	importRow := models.EngineImportRow{Line: row.Line, Err: row.Err}
+	if row.Err != nil {
+		return importRow
+	}
+
+	if row.JSON != nil {
+		decoder := json.NewDecoder(bytes.NewReader(row.JSON))
+		decoder.DisallowUnknownFields()
+		importRow.Err = decoder.Decode(&importRow.Engine)
+		return importRow
+	}
+
+	fields := map[string]*int32{
+		"displacement":  &importRow.Engine.Displacement,
+		"noOfCylinders": &importRow.Engine.NoOfCylinders,
+		"carRange":      &importRow.Engine.CarRange,
+	}
+	for _, column := range engineImportColumns {
+		n, err := strconv.ParseInt(row.Fields[column], 10, 32)
+		if err != nil {
+			importRow.Err = fmt.Errorf("%s must be a valid number", column)
+			return importRow
+		}
+		*fields[column] = int32(n)
+	}
+
+	powertrain := &importRow.Engine.Powertrain
+	powertrain.Type = row.Fields["powertrain"]
+
+	floats := []struct {
+		column string
+		field  *float32
+	}{
+		{"batteryKWh", &powertrain.BatteryKWh},
+		{"chargePowerKW", &powertrain.ChargePowerKW},
+	}
+	for _, f := range floats {
+		if row.Fields[f.column] == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(row.Fields[f.column], 32)
+		if err != nil {
+			importRow.Err = fmt.Errorf("%s must be a valid number", f.column)
+			return importRow
+		}
+		*f.field = float32(v)
+	}
+
+	ints := []struct {
+		column string
+		field  *int32
+	}{
+		{"motorPowerKW", &powertrain.MotorPowerKW},
+		{"motorTorqueNm", &powertrain.MotorTorqueNm},
+	}
+	for _, f := range ints {
+		if row.Fields[f.column] == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(row.Fields[f.column], 10, 32)
+		if err != nil {
+			importRow.Err = fmt.Errorf("%s must be a valid number", f.column)
+			return importRow
+		}
+		*f.field = int32(v)
+	}
+
+	return importRow
+}
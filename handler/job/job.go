@@ -0,0 +1,164 @@
+package job
+
+import (
+	"Car-Management-System/handler/export"
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type JobHandler struct {
+	service service.JobServiceInterface
+}
+
+func NewJobHandler(service service.JobServiceInterface) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// CreateJob queues a job and answers 202 with a Location to poll.
+func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("JobHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateJob-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var jobReq models.JobRequest
+	if err := json.Unmarshal(body, &jobReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return
+	}
+
+	createdJob, err := h.service.CreateJob(ctx, userName, role, &jobReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/jobs/%s", createdJob.ID))
+	writeJSON(w, http.StatusAccepted, createdJob)
+}
+
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("JobHandler")
+	ctx, span := tracer.Start(r.Context(), "GetJob-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+	id := mux.Vars(r)["id"]
+
+	job, err := h.service.GetJob(ctx, userName, role, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (h *JobHandler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("JobHandler")
+	ctx, span := tracer.Start(r.Context(), "CancelJob-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+	id := mux.Vars(r)["id"]
+
+	cancelledJob, err := h.service.CancelJob(ctx, userName, role, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cancelledJob)
+}
+
+// GetJobResult downloads the file a finished job produced.
+func (h *JobHandler) GetJobResult(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("JobHandler")
+	ctx, span := tracer.Start(r.Context(), "GetJobResult-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+	role, _ := r.Context().Value("role").(string)
+	id := mux.Vars(r)["id"]
+
+	path, err := h.service.JobResultPath(ctx, userName, role, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			problem.WriteStatus(w, r, http.StatusGone, "job result is no longer available")
+			return
+		}
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	name := filepath.Base(path)
+	if contentType, err := export.ContentType(strings.TrimPrefix(filepath.Ext(name), ".")); err == nil {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	http.ServeFile(w, r, path)
+}
+
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidJobRequest):
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrJobForbidden):
+		problem.WriteStatus(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrJobNotFound):
+		problem.WriteStatus(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrJobFinished), errors.Is(err, models.ErrJobResultUnavailable):
+		problem.WriteStatus(w, r, http.StatusConflict, err.Error())
+	default:
+		problem.Write(w, r, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("Error while marshalling : ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
@@ -0,0 +1,36 @@
+package jwks
+
+import (
+	"Car-Management-System/keys"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keySet *keys.KeySet
+}
+
+func NewJWKSHandler(keySet *keys.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keySet: keySet,
+	}
+}
+
+func (h *JWKSHandler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	body, err := json.Marshal(h.keySet.JWKS())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("Error while marshalling : ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
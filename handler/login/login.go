@@ -2,55 +2,119 @@ package login
 
 import (
 	"Car-Management-System/models"
+	"Car-Management-System/service"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
-	"github.com/golang-jwt/jwt/v4"
+	"go.opentelemetry.io/otel"
 )
 
-func LoginHandler(w http.ResponseWriter, r *http.Request) {
+type LoginHandler struct {
+	service service.UserServiceInterface
+	tokens  service.TokenServiceInterface
+}
+
+func NewLoginHandler(service service.UserServiceInterface, tokens service.TokenServiceInterface) *LoginHandler {
+	return &LoginHandler{
+		service: service,
+		tokens:  tokens,
+	}
+}
+
+func (h *LoginHandler) Login(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("LoginHandler")
+	ctx, span := tracer.Start(r.Context(), "Login-Handler")
+	defer span.End()
+
 	var credentials models.Credentials
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
 		http.Error(w, "Invalid Request Body", http.StatusBadRequest)
 		return
 	}
 
-	valid := (credentials.UserName == "admin" && credentials.Password == "admin123")
-
-	if !valid {
-		http.Error(w, "Incorrect Username or Password", http.StatusUnauthorized)
+	user, err := h.service.Authenticate(ctx, credentials.UserName, credentials.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidCredentials):
+			http.Error(w, "Incorrect Username or Password", http.StatusUnauthorized)
+		case errors.Is(err, models.ErrUserDisabled):
+			http.Error(w, "User is disabled", http.StatusForbidden)
+		default:
+			http.Error(w, "Failed to authenticate", http.StatusInternalServerError)
+			log.Println("Error authenticating user: ", err)
+		}
 		return
 	}
 
-	tokenString, err := GenerateToken(credentials.UserName)
+	pair, err := h.tokens.IssueTokens(ctx, user)
 	if err != nil {
 		http.Error(w, "Failed to Generate token", http.StatusInternalServerError)
 		log.Println("Error Generating token: ", err)
 		return
 	}
 
-	response := map[string]string{"token": tokenString}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(pair)
 
 }
 
-func GenerateToken(userName string) (string, error) {
-	expiration := time.Now().Add(24 * time.Hour)
+func (h *LoginHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("LoginHandler")
+	ctx, span := tracer.Start(r.Context(), "Refresh-Handler")
+	defer span.End()
 
-	claims := &jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(expiration),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		Subject:   userName,
+	var refreshReq models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&refreshReq); err != nil || refreshReq.RefreshToken == "" {
+		http.Error(w, "Invalid Request Body", http.StatusBadRequest)
+		return
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte("some_value"))
+	pair, err := h.tokens.Refresh(ctx, refreshReq.RefreshToken)
 	if err != nil {
-		return "", err
+		switch {
+		case errors.Is(err, models.ErrInvalidRefreshToken), errors.Is(err, models.ErrRefreshTokenReused):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		case errors.Is(err, models.ErrUserDisabled):
+			http.Error(w, "User is disabled", http.StatusForbidden)
+		default:
+			http.Error(w, "Failed to refresh token", http.StatusInternalServerError)
+			log.Println("Error refreshing token: ", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(pair)
+}
+
+func (h *LoginHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("LoginHandler")
+	ctx, span := tracer.Start(r.Context(), "Logout-Handler")
+	defer span.End()
+
+	jti, _ := r.Context().Value("jti").(string)
+	expiresAt, _ := r.Context().Value("token_expires_at").(time.Time)
+	if jti == "" {
+		http.Error(w, "Logout requires a bearer token", http.StatusBadRequest)
+		return
+	}
+
+	var refreshReq models.RefreshRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&refreshReq); err != nil {
+			http.Error(w, "Invalid Request Body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.tokens.Logout(ctx, jti, expiresAt, refreshReq.RefreshToken); err != nil {
+		http.Error(w, "Failed to logout", http.StatusInternalServerError)
+		log.Println("Error logging out: ", err)
+		return
 	}
 
-	return signedToken, nil
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"Car-Management-System/handler/etag"
+	"Car-Management-System/handler/export"
+	"Car-Management-System/models"
+	"Car-Management-System/validation"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Errors lists the fields
+// at fault when a request fails validation.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	TraceID  string                 `json:"trace_id,omitempty"`
+	Errors   []validation.Violation `json:"errors,omitempty"`
+}
+
+// Write reports err by its kind. Errors of no known kind are logged and
+// reported as a 500 without their message.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	status, problemType := classify(err)
+
+	problem := newProblem(r, status, problemType, err.Error())
+	if status == http.StatusInternalServerError {
+		log.Println("Error : ", err)
+		problem.Detail = ""
+	}
+
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Errors
+	}
+
+	write(w, problem)
+}
+
+// WriteStatus reports a problem the handler has classified itself, such
+// as a body that is not JSON.
+func WriteStatus(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	write(w, newProblem(r, status, typeFor(status), detail))
+}
+
+// WriteValidation reports a request that failed validation outside the
+// domain layer, such as against the API specification.
+func WriteValidation(w http.ResponseWriter, r *http.Request, status int, detail string, fieldErrs []validation.Violation) {
+	problem := newProblem(r, status, "/problems/validation", detail)
+	problem.Errors = fieldErrs
+	write(w, problem)
+}
+
+// Status returns the HTTP status Write uses for err.
+func Status(err error) int {
+	status, _ := classify(err)
+	return status
+}
+
+func classify(err error) (int, string) {
+	switch {
+	case errors.Is(err, models.ErrVersionMismatch):
+		return http.StatusPreconditionFailed, typeFor(http.StatusPreconditionFailed)
+	case errors.Is(err, etag.ErrIfMatchRequired):
+		return http.StatusPreconditionRequired, typeFor(http.StatusPreconditionRequired)
+	case errors.Is(err, models.ErrValidation):
+		return http.StatusUnprocessableEntity, "/problems/validation"
+	case errors.Is(err, models.ErrForeignKey):
+		return http.StatusUnprocessableEntity, "/problems/foreign-key"
+	case errors.Is(err, models.ErrUnprocessablePatch):
+		return http.StatusUnprocessableEntity, "/problems/unprocessable-patch"
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound, typeFor(http.StatusNotFound)
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict, typeFor(http.StatusConflict)
+	case errors.Is(err, models.ErrUnsupportedPatchType), errors.Is(err, models.ErrUnsupportedImportFormat):
+		return http.StatusUnsupportedMediaType, typeFor(http.StatusUnsupportedMediaType)
+	case errors.Is(err, models.ErrInvalidSortField), errors.Is(err, models.ErrInvalidCursor),
+		errors.Is(err, models.ErrMalformedPatch), errors.Is(err, models.ErrInvalidImport),
+		errors.Is(err, etag.ErrInvalidIfMatch), errors.Is(err, export.ErrUnsupportedFormat):
+		return http.StatusBadRequest, typeFor(http.StatusBadRequest)
+	default:
+		return http.StatusInternalServerError, "about:blank"
+	}
+}
+
+var statusTypes = map[int]string{
+	http.StatusBadRequest:           "/problems/bad-request",
+	http.StatusNotFound:             "/problems/not-found",
+	http.StatusConflict:             "/problems/conflict",
+	http.StatusPreconditionFailed:   "/problems/precondition-failed",
+	http.StatusPreconditionRequired: "/problems/precondition-required",
+	http.StatusUnsupportedMediaType: "/problems/unsupported-media-type",
+	http.StatusUnprocessableEntity:  "/problems/unprocessable",
+}
+
+func typeFor(status int) string {
+	if problemType, ok := statusTypes[status]; ok {
+		return problemType
+	}
+	return "about:blank"
+}
+
+func newProblem(r *http.Request, status int, problemType string, detail string) Problem {
+	problem := Problem{
+		Type:     problemType,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+
+	if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.HasTraceID() {
+		problem.TraceID = spanContext.TraceID().String()
+	}
+
+	return problem
+}
+
+func write(w http.ResponseWriter, problem Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(problem.Status)
+	body, _ := json.Marshal(problem)
+	_, _ = w.Write(body)
+}
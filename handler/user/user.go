@@ -0,0 +1,205 @@
+package user
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type UserHandler struct {
+	service service.UserServiceInterface
+}
+
+func NewUserHandler(service service.UserServiceInterface) *UserHandler {
+	return &UserHandler{
+		service: service,
+	}
+}
+
+func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "ListUsers-Handler")
+	defer span.End()
+
+	users, err := h.service.ListUsers(ctx)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (h *UserHandler) RegisterUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "RegisterUser-Handler")
+	defer span.End()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var userReq models.UserRequest
+	if err := json.Unmarshal(body, &userReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return
+	}
+
+	createdUser, err := h.service.RegisterUser(ctx, &userReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdUser)
+}
+
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "ChangePassword-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var passwordReq models.PasswordChangeRequest
+	if err := json.Unmarshal(body, &passwordReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return
+	}
+
+	if err := h.service.ChangePassword(ctx, userName, &passwordReq); err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "DisableUser-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	user, err := h.service.DisableUser(ctx, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *UserHandler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "EnableUser-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	user, err := h.service.EnableUser(ctx, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *UserHandler) SetUserRole(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "SetUserRole-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var roleReq models.RoleRequest
+	if err := json.Unmarshal(body, &roleReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return
+	}
+
+	user, err := h.service.SetUserRole(ctx, id, &roleReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("UserHandler")
+	ctx, span := tracer.Start(r.Context(), "DeleteUser-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	deletedUser, err := h.service.DeleteUser(ctx, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deletedUser)
+}
+
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidUserRequest):
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrInvalidCredentials):
+		problem.WriteStatus(w, r, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, models.ErrUserDisabled):
+		problem.WriteStatus(w, r, http.StatusForbidden, err.Error())
+	case errors.Is(err, models.ErrUserNotFound):
+		problem.WriteStatus(w, r, http.StatusNotFound, err.Error())
+	case errors.Is(err, models.ErrUserExists):
+		problem.WriteStatus(w, r, http.StatusConflict, err.Error())
+	default:
+		problem.Write(w, r, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("Error while marshalling : ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
@@ -0,0 +1,59 @@
+package vin
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/vin"
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+)
+
+// VINHandler decodes VINs. Decoding is offline, so it needs no service.
+type VINHandler struct{}
+
+func NewVINHandler() *VINHandler {
+	return &VINHandler{}
+}
+
+type decodeRequest struct {
+	VIN string `json:"vin"`
+}
+
+// DecodeVIN validates the VIN in the body and returns what it says about
+// the vehicle, without looking for a car that has it.
+func (h *VINHandler) DecodeVIN(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("VINHandler")
+	_, span := tracer.Start(r.Context(), "DecodeVIN-Handler")
+	defer span.End()
+
+	var req decodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "request body is not valid JSON: "+err.Error())
+		return
+	}
+
+	v := vin.Normalize(req.VIN)
+	if err := models.ValidateVIN(v); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	info, err := vin.Decode(v)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	body, err := json.Marshal(info)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	_, _ = w.Write(body)
+}
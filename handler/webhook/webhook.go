@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+)
+
+type WebhookHandler struct {
+	service service.WebhookServiceInterface
+}
+
+func NewWebhookHandler(service service.WebhookServiceInterface) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "CreateWebhook-Handler")
+	defer span.End()
+
+	userName, _ := r.Context().Value("username").(string)
+
+	webhookReq, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	createdWebhook, err := h.service.CreateWebhook(ctx, userName, webhookReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createdWebhook)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "ListWebhooks-Handler")
+	defer span.End()
+
+	webhooks, err := h.service.ListWebhooks(ctx)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "GetWebhook-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	webhook, err := h.service.GetWebhook(ctx, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "UpdateWebhook-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	webhookReq, ok := readWebhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	updatedWebhook, err := h.service.UpdateWebhook(ctx, id, webhookReq)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updatedWebhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "DeleteWebhook-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.DeleteWebhook(ctx, id); err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "ListDeadLetters-Handler")
+	defer span.End()
+
+	deadLetters, err := h.service.ListDeadLetters(ctx, r.URL.Query().Get("webhook_id"))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deadLetters)
+}
+
+func (h *WebhookHandler) ReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	tracer := otel.Tracer("WebhookHandler")
+	ctx, span := tracer.Start(r.Context(), "ReplayDeadLetter-Handler")
+	defer span.End()
+
+	id := mux.Vars(r)["id"]
+
+	deadLetter, err := h.service.ReplayDeadLetter(ctx, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, deadLetter)
+}
+
+func readWebhookRequest(w http.ResponseWriter, r *http.Request) (*models.WebhookRequest, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error : ", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+
+	var webhookReq models.WebhookRequest
+	if err := json.Unmarshal(body, &webhookReq); err != nil {
+		problem.WriteStatus(w, r, http.StatusBadRequest, "Invalid Request Body")
+		return nil, false
+	}
+
+	return &webhookReq, true
+}
+
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidWebhookRequest):
+		problem.WriteStatus(w, r, http.StatusBadRequest, err.Error())
+	case errors.Is(err, models.ErrWebhookNotFound), errors.Is(err, models.ErrDeadLetterNotFound):
+		problem.WriteStatus(w, r, http.StatusNotFound, err.Error())
+	default:
+		problem.Write(w, r, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		log.Println("Error while marshalling : ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_, err = w.Write(body)
+	if err != nil {
+		log.Println("Error writing response : ", err)
+	}
+}
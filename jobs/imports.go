@@ -0,0 +1,165 @@
+package jobs
+
+import (
+	"Car-Management-System/handler/importer"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ImportCars    = "import_cars"
+	ImportEngines = "import_engines"
+)
+
+// ImportParams carry the file to import inline, as the jobs table is the
+// only storage every worker is guaranteed to share.
+type ImportParams struct {
+	Format string `json:"format"`
+	Data   string `json:"data"`
+	DryRun bool   `json:"dryRun"`
+}
+
+func importParams(raw json.RawMessage) (ImportParams, error) {
+	var params ImportParams
+	if err := decodeParams(raw, &params); err != nil {
+		return params, err
+	}
+
+	if params.Format == "" {
+		params.Format = importer.CSV
+	}
+	if params.Format != importer.CSV && params.Format != importer.NDJSON {
+		return params, fmt.Errorf("format must be %s or %s", importer.CSV, importer.NDJSON)
+	}
+	if len(params.Data) > importer.MaxBodyBytes {
+		return params, fmt.Errorf("data is larger than %d bytes", importer.MaxBodyBytes)
+	}
+
+	return params, nil
+}
+
+type importCarsRunner struct {
+	cars service.CarServiceInterface
+	dir  string
+}
+
+// NewImportCarsRunner imports cars like POST /cars/import. The result is
+// the import report, written to a file in dir.
+func NewImportCarsRunner(cars service.CarServiceInterface, dir string) Runner {
+	return importCarsRunner{cars: cars, dir: dir}
+}
+
+func (i importCarsRunner) Validate(params json.RawMessage) error {
+	_, _, err := i.rows(params)
+	return err
+}
+
+func (i importCarsRunner) rows(raw json.RawMessage) (ImportParams, []models.CarImportRow, error) {
+	params, err := importParams(raw)
+	if err != nil {
+		return params, nil, err
+	}
+
+	rows, err := importer.ReadCars(strings.NewReader(params.Data), params.Format)
+	return params, rows, err
+}
+
+func (i importCarsRunner) Run(ctx context.Context, job models.Job, progress *Progress) (string, error) {
+	params, rows, err := i.rows(job.Params)
+	if err != nil {
+		return "", err
+	}
+
+	if err := progress.Update(ctx, 0, int64(len(rows))); err != nil {
+		return "", err
+	}
+
+	report, err := i.cars.ImportCars(ctx, rows, params.DryRun)
+	if err != nil && !errors.Is(err, models.ErrImportRejected) {
+		return "", err
+	}
+
+	return finishImport(ctx, i.dir, job, report, progress)
+}
+
+type importEnginesRunner struct {
+	engines service.EngineServiceInterface
+	dir     string
+}
+
+// NewImportEnginesRunner imports engines like POST /engine/import. The
+// result is the import report, written to a file in dir.
+func NewImportEnginesRunner(engines service.EngineServiceInterface, dir string) Runner {
+	return importEnginesRunner{engines: engines, dir: dir}
+}
+
+func (i importEnginesRunner) Validate(params json.RawMessage) error {
+	_, _, err := i.rows(params)
+	return err
+}
+
+func (i importEnginesRunner) rows(raw json.RawMessage) (ImportParams, []models.EngineImportRow, error) {
+	params, err := importParams(raw)
+	if err != nil {
+		return params, nil, err
+	}
+
+	rows, err := importer.ReadEngines(strings.NewReader(params.Data), params.Format)
+	return params, rows, err
+}
+
+func (i importEnginesRunner) Run(ctx context.Context, job models.Job, progress *Progress) (string, error) {
+	params, rows, err := i.rows(job.Params)
+	if err != nil {
+		return "", err
+	}
+
+	if err := progress.Update(ctx, 0, int64(len(rows))); err != nil {
+		return "", err
+	}
+
+	report, err := i.engines.ImportEngines(ctx, rows, params.DryRun)
+	if err != nil && !errors.Is(err, models.ErrImportRejected) {
+		return "", err
+	}
+
+	return finishImport(ctx, i.dir, job, report, progress)
+}
+
+// finishImport writes report to dir and returns its file:// URL. A report
+// with invalid rows is still a result: like the HTTP import, nothing was
+// imported and the report says why.
+func finishImport(ctx context.Context, dir string, job models.Job, report *models.ImportReport, progress *Progress) (string, error) {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, job.ID.String()+".json")
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return "", err
+	}
+
+	if err := progress.Update(ctx, int64(report.Rows), int64(report.Rows)); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	return "file://" + absPath, nil
+}
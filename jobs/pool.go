@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	pollInterval      = 2 * time.Second
+	heartbeatInterval = 5 * time.Second
+
+	// staleAfter is how long a running job may go without a heartbeat
+	// before its worker is assumed dead and the job is claimed again.
+	staleAfter  = time.Minute
+	maxAttempts = 3
+)
+
+// Runner does the work of one job type. Validate checks a job's params
+// when it is submitted; Run does the work and returns where the result
+// can be found, if there is one. Run should return promptly once ctx is
+// cancelled, which happens when the job is cancelled.
+type Runner interface {
+	Validate(params json.RawMessage) error
+	Run(ctx context.Context, job models.Job, progress *Progress) (string, error)
+}
+
+type registration struct {
+	minRole string
+	runner  Runner
+}
+
+// Pool runs queued jobs on a fixed number of workers. Jobs live in the
+// jobs table, so any number of pools, in any number of processes, can
+// share the queue.
+type Pool struct {
+	store   store.JobStoreInterface
+	runners map[string]registration
+	types   []string
+}
+
+func NewPool(store store.JobStoreInterface) *Pool {
+	return &Pool{
+		store:   store,
+		runners: map[string]registration{},
+	}
+}
+
+// Register makes jobType runnable by runner. Only users with at least
+// minRole can submit it. Register must be called before Start.
+func (p *Pool) Register(jobType string, minRole string, runner Runner) {
+	if _, ok := p.runners[jobType]; !ok {
+		p.types = append(p.types, jobType)
+		sort.Strings(p.types)
+	}
+	p.runners[jobType] = registration{minRole: minRole, runner: runner}
+}
+
+// Validate checks that a user with role may submit a job of jobType with
+// params.
+func (p *Pool) Validate(jobType string, role string, params json.RawMessage) error {
+	registered, ok := p.runners[jobType]
+	if !ok {
+		return fmt.Errorf("%w: type must be one of: %v", models.ErrInvalidJobRequest, p.types)
+	}
+
+	if !models.RoleAtLeast(role, registered.minRole) {
+		return fmt.Errorf("%w: %s jobs need the %q role", models.ErrJobForbidden, jobType, registered.minRole)
+	}
+
+	if err := registered.runner.Validate(params); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidJobRequest, err)
+	}
+
+	return nil
+}
+
+// Start runs workers until ctx is cancelled. A job that is running when
+// ctx is cancelled is left running and is picked up again once it goes
+// stale.
+func (p *Pool) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go p.work(ctx)
+	}
+	go p.failStale(ctx)
+}
+
+func (p *Pool) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep claiming while there is work, then wait for the next poll.
+		for ctx.Err() == nil && p.runNext(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// failStale fails jobs that went stale after their last attempt, as no
+// worker will claim them again.
+func (p *Pool) failStale(ctx context.Context) {
+	ticker := time.NewTicker(staleAfter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		failed, err := p.store.FailStaleJobs(ctx, time.Now().Add(-staleAfter), maxAttempts)
+		if err != nil {
+			log.Println("Error failing stale jobs : ", err)
+		} else if failed > 0 {
+			log.Printf("Failed %d stale job(s)", failed)
+		}
+	}
+}
+
+// runNext claims and runs one job, reporting whether there was one.
+func (p *Pool) runNext(ctx context.Context) bool {
+	job, err := p.store.ClaimJob(ctx, p.types, time.Now().Add(-staleAfter), maxAttempts)
+	if err != nil {
+		if !errors.Is(err, models.ErrJobNotFound) && ctx.Err() == nil {
+			log.Println("Error claiming job : ", err)
+		}
+		return false
+	}
+
+	p.run(ctx, job)
+	return true
+}
+
+func (p *Pool) run(ctx context.Context, job models.Job) {
+	tracer := otel.Tracer("JobWorker")
+	ctx, span := tracer.Start(ctx, "RunJob-Worker", trace.WithAttributes(
+		attribute.String("job.id", job.ID.String()),
+		attribute.String("job.type", job.Type),
+		attribute.Int("job.attempt", job.Attempts),
+	))
+	defer span.End()
+
+	runningJobs.WithLabelValues(job.Type).Inc()
+	defer runningJobs.WithLabelValues(job.Type).Dec()
+
+	progress := newProgress(job, span)
+	defer progress.clear()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var cancelled atomic.Bool
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		p.heartbeat(ctx, job, progress, stop, func() {
+			cancelled.Store(true)
+			cancel()
+		})
+	}()
+
+	location, err := p.runners[job.Type].runner.Run(jobCtx, job, progress)
+
+	close(stop)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		// The pool is shutting down; leave the job for another worker.
+		return
+	}
+
+	status := models.JobSucceeded
+	errMsg := ""
+	switch {
+	case cancelled.Load():
+		status = models.JobCancelled
+	case err != nil:
+		status = models.JobFailed
+		errMsg = err.Error()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, errMsg)
+	}
+	span.SetAttributes(attribute.String("job.status", status))
+
+	if status != models.JobCancelled {
+		done, total := progress.values()
+		if _, err := p.store.TouchJob(ctx, job.ID.String(), done, total); err != nil {
+			log.Println("Error saving job progress : ", err)
+		}
+		if err := p.store.FinishJob(ctx, job.ID.String(), status, location, errMsg); err != nil {
+			log.Println("Error finishing job : ", err)
+		}
+	}
+
+	finishedJobs.WithLabelValues(job.Type, status).Inc()
+}
+
+// heartbeat saves the job's progress every heartbeatInterval until stop is
+// closed, and calls onCancel once the job is no longer running.
+func (p *Pool) heartbeat(ctx context.Context, job models.Job, progress *Progress, stop <-chan struct{}, onCancel func()) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		done, total := progress.values()
+		running, err := p.store.TouchJob(ctx, job.ID.String(), done, total)
+		if err != nil {
+			log.Println("Error saving job progress : ", err)
+			continue
+		}
+		if !running {
+			onCancel()
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package jobs
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	runningJobs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jobs_running",
+			Help: "Number of jobs being run by this process",
+		},
+		[]string{
+			"type",
+		},
+	)
+
+	jobProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "job_progress_ratio",
+			Help: "Fraction of a running job that is done, from 0 to 1",
+		},
+		[]string{
+			"type", "job_id",
+		},
+	)
+
+	finishedJobs = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jobs_finished_total",
+			Help: "Total number of jobs finished by this process by status",
+		},
+		[]string{
+			"type", "status",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(runningJobs, jobProgress, finishedJobs)
+}
+
+// Progress is how a Runner reports how far along its job is. Updates are
+// exported straight away as a gauge and a span event, and saved to the
+// jobs table at the next heartbeat.
+type Progress struct {
+	job   models.Job
+	span  trace.Span
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+func newProgress(job models.Job, span trace.Span) *Progress {
+	progress := &Progress{job: job, span: span}
+	jobProgress.WithLabelValues(job.Type, job.ID.String()).Set(0)
+	return progress
+}
+
+// Update records that done of total units of work are finished. total may
+// be 0 while it is unknown. It returns ctx's error, so a runner can stop
+// as soon as its job is cancelled.
+func (p *Progress) Update(ctx context.Context, done int64, total int64) error {
+	p.done.Store(done)
+	p.total.Store(total)
+
+	ratio := 0.0
+	if total > 0 {
+		ratio = float64(done) / float64(total)
+	}
+	jobProgress.WithLabelValues(p.job.Type, p.job.ID.String()).Set(ratio)
+
+	p.span.AddEvent("progress", trace.WithAttributes(
+		attribute.Int64("job.progress.done", done),
+		attribute.Int64("job.progress.total", total),
+	))
+
+	return ctx.Err()
+}
+
+func (p *Progress) values() (int64, int64) {
+	return p.done.Load(), p.total.Load()
+}
+
+// clear drops the job's gauge once it has finished, so finished jobs do
+// not pile up as series.
+func (p *Progress) clear() {
+	jobProgress.DeleteLabelValues(p.job.Type, p.job.ID.String())
+}
@@ -0,0 +1,221 @@
+package jobs
+
+import (
+	"Car-Management-System/handler/export"
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ExportCars   = "export_cars"
+	PurgeDeleted = "purge_deleted"
+
+	// exportProgressEvery is how many rows an export writes between
+	// progress updates.
+	exportProgressEvery = 500
+)
+
+type ExportCarsParams struct {
+	Format string           `json:"format"`
+	Filter models.CarFilter `json:"filter"`
+}
+
+type exportCarsRunner struct {
+	cars service.CarServiceInterface
+	dir  string
+}
+
+// NewExportCarsRunner exports cars to a file in dir, in the same formats as
+// GET /cars/export. The result location is a file:// URL.
+func NewExportCarsRunner(cars service.CarServiceInterface, dir string) Runner {
+	return exportCarsRunner{cars: cars, dir: dir}
+}
+
+func (e exportCarsRunner) Validate(params json.RawMessage) error {
+	_, err := e.params(params)
+	return err
+}
+
+func (e exportCarsRunner) params(raw json.RawMessage) (ExportCarsParams, error) {
+	var params ExportCarsParams
+	if err := decodeParams(raw, &params); err != nil {
+		return params, err
+	}
+
+	if params.Format == "" {
+		params.Format = export.CSV
+	}
+	if _, err := export.ContentType(params.Format); err != nil {
+		return params, err
+	}
+
+	params.Filter.Limit = 0
+	params.Filter.Cursor = ""
+	return params, nil
+}
+
+func (e exportCarsRunner) Run(ctx context.Context, job models.Job, progress *Progress) (string, error) {
+	params, err := e.params(job.Params)
+	if err != nil {
+		return "", err
+	}
+
+	countFilter := params.Filter
+	countFilter.Limit = 1
+	page, err := e.cars.ListCars(ctx, &countFilter)
+	if err != nil {
+		return "", err
+	}
+
+	total := int64(page.TotalCount)
+	if err := progress.Update(ctx, 0, total); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(e.dir, job.ID.String()+"."+params.Format)
+	partial := path + ".part"
+
+	done, err := e.write(ctx, partial, params, progress, total)
+	if err == nil {
+		err = os.Rename(partial, path)
+	}
+	if err != nil {
+		os.Remove(partial)
+		return "", err
+	}
+
+	// Cars written since the count was taken can push done past total.
+	if done > total {
+		total = done
+	}
+	if err := progress.Update(ctx, done, total); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	return "file://" + absPath, nil
+}
+
+func (e exportCarsRunner) write(ctx context.Context, path string, params ExportCarsParams, progress *Progress, total int64) (int64, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	writer, err := export.NewWriter(f, params.Format, "Cars", export.CarColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	var done int64
+	err = e.cars.ExportCars(ctx, &params.Filter, func(car models.Car) error {
+		if err := writer.Write(car, export.CarValues(car)); err != nil {
+			return err
+		}
+
+		done++
+		if done%exportProgressEvery == 0 {
+			return progress.Update(ctx, done, total)
+		}
+		return nil
+	})
+	if err != nil {
+		return done, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return done, err
+	}
+
+	return done, f.Close()
+}
+
+type PurgeDeletedParams struct {
+	Retention string `json:"retention"`
+}
+
+// Purger is the part of the purge service that purge_deleted jobs use.
+type Purger interface {
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, int64, error)
+}
+
+type purgeDeletedRunner struct {
+	purger Purger
+}
+
+// NewPurgeDeletedRunner runs the soft-delete purge on demand, with the
+// retention given as a Go duration such as "720h". A retention of "0s"
+// purges everything that is soft-deleted.
+func NewPurgeDeletedRunner(purger Purger) Runner {
+	return purgeDeletedRunner{purger: purger}
+}
+
+func (p purgeDeletedRunner) Validate(params json.RawMessage) error {
+	_, err := p.retention(params)
+	return err
+}
+
+func (p purgeDeletedRunner) retention(raw json.RawMessage) (time.Duration, error) {
+	var params PurgeDeletedParams
+	if err := decodeParams(raw, &params); err != nil {
+		return 0, err
+	}
+
+	retention, err := time.ParseDuration(params.Retention)
+	if err != nil || retention < 0 {
+		return 0, errors.New("retention must be a non-negative duration such as 720h")
+	}
+
+	return retention, nil
+}
+
+func (p purgeDeletedRunner) Run(ctx context.Context, job models.Job, progress *Progress) (string, error) {
+	retention, err := p.retention(job.Params)
+	if err != nil {
+		return "", err
+	}
+
+	if err := progress.Update(ctx, 0, 1); err != nil {
+		return "", err
+	}
+
+	if _, _, err := p.purger.PurgeDeleted(ctx, retention); err != nil {
+		return "", err
+	}
+
+	return "", progress.Update(ctx, 1, 1)
+}
+
+// decodeParams decodes a job's params into dst, rejecting unknown fields.
+// Missing params decode as an empty object.
+func decodeParams(raw json.RawMessage, dst interface{}) error {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("invalid params: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+package keys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownKeyID      = errors.New("unknown key id")
+	ErrAlgorithmMismatch = errors.New("token algorithm does not match key")
+)
+
+type Key struct {
+	ID         string
+	Algorithm  string
+	signingKey interface{}
+	verifyKey  interface{}
+}
+
+// KeySet holds the key used to sign new tokens and every key, current or
+// previous, that tokens may still be verified against.
+type KeySet struct {
+	current *Key
+	keys    map[string]*Key
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewKeySet(current *Key, previous ...*Key) (*KeySet, error) {
+	if current == nil || current.signingKey == nil {
+		return nil, errors.New("current key must be able to sign")
+	}
+
+	ks := &KeySet{
+		current: current,
+		keys:    map[string]*Key{current.ID: current},
+	}
+	for _, k := range previous {
+		if _, exists := ks.keys[k.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+
+	return ks, nil
+}
+
+// LoadFromEnv builds a KeySet from JWT_KEYS, a comma-separated list of
+// "kid:alg:source" entries where source is "file:<path>" or "env:<VAR>".
+// The first entry signs new tokens. Without JWT_KEYS, JWT_SECRET is used
+// as a single HS256 key.
+func LoadFromEnv() (*KeySet, error) {
+	specs := os.Getenv("JWT_KEYS")
+	if specs == "" {
+		secret := os.Getenv("JWT_SECRET")
+		if secret == "" {
+			return nil, errors.New("either JWT_KEYS or JWT_SECRET must be set")
+		}
+		key, err := ParseKey("default", AlgHS256, []byte(secret))
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySet(key)
+	}
+
+	var loaded []*Key
+	for _, spec := range strings.Split(specs, ",") {
+		key, err := loadKeySpec(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, key)
+	}
+
+	return NewKeySet(loaded[0], loaded[1:]...)
+}
+
+func loadKeySpec(spec string) (*Key, error) {
+	parts := strings.SplitN(spec, ":", 4)
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("invalid key spec %q: expected kid:alg:file:<path> or kid:alg:env:<VAR>", spec)
+	}
+	kid, alg, sourceType, source := parts[0], parts[1], parts[2], parts[3]
+
+	var material []byte
+	switch sourceType {
+	case "file":
+		raw, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("reading key %q: %w", kid, err)
+		}
+		material = raw
+	case "env":
+		value := os.Getenv(source)
+		if value == "" {
+			return nil, fmt.Errorf("key %q: environment variable %s is empty", kid, source)
+		}
+		material = []byte(value)
+	default:
+		return nil, fmt.Errorf("key %q: unknown source type %q", kid, sourceType)
+	}
+
+	return ParseKey(kid, alg, material)
+}
+
+// ParseKey builds a Key from raw material. HS256 takes the secret itself;
+// RS256 and EdDSA take a PEM private key, or a PEM public key for a
+// verify-only previous key.
+func ParseKey(kid string, alg string, material []byte) (*Key, error) {
+	if kid == "" {
+		return nil, errors.New("key id is required")
+	}
+
+	key := &Key{ID: kid, Algorithm: alg}
+
+	if alg == AlgHS256 {
+		secret := []byte(strings.TrimSpace(string(material)))
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("key %q: HS256 secret must be at least 32 bytes", kid)
+		}
+		key.signingKey = secret
+		key.verifyKey = secret
+		return key, nil
+	}
+
+	block, _ := pem.Decode(material)
+	if block == nil {
+		return nil, fmt.Errorf("key %q: no PEM block found", kid)
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("key %q: unsupported PEM block %q", kid, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", kid, err)
+	}
+
+	switch alg {
+	case AlgRS256:
+		switch k := parsed.(type) {
+		case *rsa.PrivateKey:
+			key.signingKey = k
+			key.verifyKey = &k.PublicKey
+		case *rsa.PublicKey:
+			key.verifyKey = k
+		default:
+			return nil, fmt.Errorf("key %q: RS256 requires an RSA key", kid)
+		}
+	case AlgEdDSA:
+		switch k := parsed.(type) {
+		case ed25519.PrivateKey:
+			key.signingKey = k
+			key.verifyKey = k.Public()
+		case ed25519.PublicKey:
+			key.verifyKey = k
+		default:
+			return nil, fmt.Errorf("key %q: EdDSA requires an Ed25519 key", kid)
+		}
+	default:
+		return nil, fmt.Errorf("key %q: unsupported algorithm %q", kid, alg)
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ks.current.Algorithm), claims)
+	token.Header["kid"] = ks.current.ID
+	return token.SignedString(ks.current.signingKey)
+}
+
+// Keyfunc resolves the verification key from the token's kid header and
+// rejects tokens whose alg header differs from the key's algorithm.
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, ErrAlgorithmMismatch
+	}
+	return key.verifyKey, nil
+}
+
+// JWKS returns the public part of every asymmetric key. HS256 secrets are
+// never published.
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		switch k := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.Algorithm,
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: key.Algorithm,
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(k),
+			})
+		}
+	}
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID
+	})
+	return jwks
+}
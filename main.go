@@ -2,26 +2,56 @@ package main
 
 import (
 	"Car-Management-System/driver"
+	"Car-Management-System/graphqlapi"
+	"Car-Management-System/grpcapi"
+	"Car-Management-System/jobs"
+	"Car-Management-System/keys"
 	"Car-Management-System/middleware"
+	"Car-Management-System/migrate"
+	"Car-Management-System/models"
+	"Car-Management-System/openapi"
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
+	apiKeyHandler "Car-Management-System/handler/apikey"
 	carHandler "Car-Management-System/handler/car"
 	engineHandler "Car-Management-System/handler/engine"
+	eventsHandler "Car-Management-System/handler/events"
+	jobHandler "Car-Management-System/handler/job"
+	jwksHandler "Car-Management-System/handler/jwks"
 	loginHandler "Car-Management-System/handler/login"
+	userHandler "Car-Management-System/handler/user"
+	vinHandler "Car-Management-System/handler/vin"
+	webhookHandler "Car-Management-System/handler/webhook"
+	apiKeyService "Car-Management-System/service/apikey"
 	carService "Car-Management-System/service/car"
 	engineService "Car-Management-System/service/engine"
+	eventsService "Car-Management-System/service/events"
+	jobService "Car-Management-System/service/job"
+	outboxService "Car-Management-System/service/outbox"
+	purgeService "Car-Management-System/service/purge"
+	tokenService "Car-Management-System/service/token"
+	userService "Car-Management-System/service/user"
+	webhookService "Car-Management-System/service/webhook"
+	apiKeyStore "Car-Management-System/store/apikey"
 	carStore "Car-Management-System/store/car"
 	engineStore "Car-Management-System/store/engine"
+	jobStore "Car-Management-System/store/job"
+	outboxStore "Car-Management-System/store/outbox"
+	tokenStore "Car-Management-System/store/token"
+	userStore "Car-Management-System/store/user"
+	webhookStore "Car-Management-System/store/webhook"
 
-	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -49,47 +79,113 @@ func main() {
 
 	otel.SetTracerProvider(traceProvider)
 
+	keySet, err := keys.LoadFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys : %v", err)
+	}
+
 	driver.InitDB()
 	defer driver.CloseDB()
 
 	db := driver.GetDB()
+	webhookStore := webhookStore.New(db)
+	webhookDispatcher := webhookService.NewDispatcher(webhookStore)
+	webhookService := webhookService.NewWebhookService(webhookStore)
+
 	carStore := carStore.New(db)
 	carService := carService.NewCarService(carStore)
 
 	engineStore := engineStore.New(db)
 	engineService := engineService.NewEngineService(engineStore)
 
+	outboxStore := outboxStore.New(db)
+	outboxRelay := outboxService.NewRelay(outboxStore, webhookService)
+	eventHub := eventsService.NewHub(outboxStore)
+
+	userStore := userStore.New(db)
+	userService := userService.NewUserService(userStore)
+
+	tokenStore := tokenStore.New(db)
+	tokenService := tokenService.NewTokenService(tokenStore, userStore, keySet)
+
+	purgeService := purgeService.NewPurgeService(carStore, engineStore)
+
+	apiKeyStore := apiKeyStore.New(db)
+	apiKeyService := apiKeyService.NewAPIKeyService(apiKeyStore, userStore)
+
+	jobStore := jobStore.New(db)
+	jobPool := jobs.NewPool(jobStore)
+	jobService := jobService.NewJobService(jobStore, jobPool)
+
 	carHandler := carHandler.NewCarHandler(carService)
 	engineHandler := engineHandler.NewEngineHandler(engineService)
+	loginHandler := loginHandler.NewLoginHandler(userService, tokenService)
+	userHandler := userHandler.NewUserHandler(userService)
+	jwksHandler := jwksHandler.NewJWKSHandler(keySet)
+	apiKeyHandler := apiKeyHandler.NewAPIKeyHandler(apiKeyService)
+	jobHandler := jobHandler.NewJobHandler(jobService)
+	webhookHandler := webhookHandler.NewWebhookHandler(webhookService)
+	eventsHandler := eventsHandler.NewEventsHandler(eventHub, func(ctx context.Context, r *http.Request) error {
+		_, err := middleware.Authenticate(ctx, keySet, tokenService, apiKeyService, r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+		return err
+	})
+	vinHandler := vinHandler.NewVINHandler()
 
-	router := mux.NewRouter()
+	graphqlHandler, err := newGraphQLHandler(carService, engineService)
+	if err != nil {
+		log.Fatal("Error while building the GraphQL schema : ", err)
+	}
 
-	router.Use(otelmux.Middleware("Car-Management-System"))
-	router.Use(middleware.MetricMiddleware)
+	if err := runMigrations(db); err != nil {
+		log.Fatal("Error while migrating the database : ", err)
+	}
 
-	schemaFile := "store/schema.sql"
-	if err := executeSchemaFile(db, schemaFile); err != nil {
-		log.Fatal("Error while executing the schema file : ", err)
+	if err := bootstrapAdmin(userService); err != nil {
+		log.Fatal("Error while creating the admin user : ", err)
 	}
 
-	router.HandleFunc("/login", loginHandler.LoginHandler).Methods("POST")
+	if err := startPurgeJob(purgeService); err != nil {
+		log.Fatal("Error while starting the purge job : ", err)
+	}
 
-	protected := router.PathPrefix("/").Subrouter()
+	if err := startJobPool(jobPool, carService, engineService, purgeService); err != nil {
+		log.Fatal("Error while starting the job workers : ", err)
+	}
 
-	protected.Use(middleware.AuthMiddleware)
+	if err := startWebhookDispatcher(webhookDispatcher); err != nil {
+		log.Fatal("Error while starting the webhook dispatcher : ", err)
+	}
 
-	protected.HandleFunc("/cars/{id}", carHandler.GetCarByID).Methods("GET")
-	protected.HandleFunc("/cars", carHandler.GetCarByBrand).Methods("GET")
-	protected.HandleFunc("/cars", carHandler.CreateCar).Methods("POST")
-	protected.HandleFunc("/cars/{id}", carHandler.UpdateCar).Methods("PUT")
-	protected.HandleFunc("/cars/{id}", carHandler.DeleteCar).Methods("DELETE")
+	if err := startOutboxRelay(outboxRelay); err != nil {
+		log.Fatal("Error while starting the outbox relay : ", err)
+	}
 
-	protected.HandleFunc("/engine/{id}", engineHandler.GetEngineById).Methods("GET")
-	protected.HandleFunc("/engine", engineHandler.CreateEngine).Methods("POST")
-	protected.HandleFunc("/engine/{id}", engineHandler.UpdateEngine).Methods("PUT")
-	protected.HandleFunc("/engine/{id}", engineHandler.DeleteEngine).Methods("DELETE")
+	if err := startEventHub(eventHub); err != nil {
+		log.Fatal("Error while starting the event stream : ", err)
+	}
 
-	router.Handle("/metrics", promhttp.Handler())
+	validator, err := openapi.NewValidator()
+	if err != nil {
+		log.Fatal("Error while loading the OpenAPI document : ", err)
+	}
+
+	router := newRouter(routeHandlers{
+		car:     carHandler,
+		engine:  engineHandler,
+		login:   loginHandler,
+		user:    userHandler,
+		jwks:    jwksHandler,
+		apiKey:  apiKeyHandler,
+		job:     jobHandler,
+		webhook: webhookHandler,
+		events:  eventsHandler,
+		vin:     vinHandler,
+		graphql: graphqlHandler,
+	}, middleware.AuthMiddleware(keySet, tokenService, apiKeyService), validator.Middleware)
+
+	if err := startGRPCServer(carService, engineService, keySet, tokenService, apiKeyService); err != nil {
+		log.Fatal("Error while starting the gRPC server : ", err)
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -102,18 +198,237 @@ func main() {
 
 }
 
-func executeSchemaFile(db *sql.DB, fileName string) error {
-	sqlFile, err := os.ReadFile(fileName)
+// runMigrations applies pending migrations unless MIGRATE_ON_START is
+// "false", and loads the sample data only when SEED_DATA is "true".
+func runMigrations(db *sql.DB) error {
+	if os.Getenv("MIGRATE_ON_START") == "false" {
+		return nil
+	}
+
+	migrator, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx); err != nil {
+		return err
+	}
+
+	if os.Getenv("SEED_DATA") == "true" {
+		_, err = migrator.Seed(ctx)
+		return err
+	}
+
+	return nil
+}
+
+// bootstrapAdmin creates the ADMIN_USERNAME account if it does not exist
+// yet, and makes it an admin if it does. Without ADMIN_PASSWORD a random password is generated and logged
+// once, when the account is created.
+func bootstrapAdmin(service *userService.UserService) error {
+	userName := os.Getenv("ADMIN_USERNAME")
+	if userName == "" {
+		return nil
+	}
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	generated := password == ""
+	if generated {
+		raw := make([]byte, 18)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		password = base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	created, err := service.EnsureUser(context.Background(), &models.UserRequest{
+		UserName: userName,
+		Password: password,
+		Role:     models.RoleAdmin,
+	})
 	if err != nil {
 		return err
 	}
 
-	_, err = db.Exec(string(sqlFile))
+	if created && generated {
+		log.Printf("Created user %q with the generated password %s. Change it after logging in.", userName, password)
+	}
+	return nil
+}
+
+// startPurgeJob hard-deletes soft-deleted cars and engines once they are
+// older than SOFT_DELETE_RETENTION (default 30 days), checking every
+// PURGE_INTERVAL (default 1 hour). A retention of 0 disables the job.
+func startPurgeJob(service *purgeService.PurgeService) error {
+	retention := 30 * 24 * time.Hour
+	if value := os.Getenv("SOFT_DELETE_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid SOFT_DELETE_RETENTION: %w", err)
+		}
+		retention = parsed
+	}
+
+	interval := time.Hour
+	if value := os.Getenv("PURGE_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid PURGE_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+
+	if retention <= 0 {
+		log.Println("Purge job disabled")
+		return nil
+	}
+
+	go service.Run(context.Background(), retention, interval)
+	return nil
+}
+
+// startJobPool registers the background job types and starts JOB_WORKERS
+// workers (default 2). Job results are written to JOB_RESULT_DIR (default
+// "job-results") and served from there by GET /jobs/{id}/result, so every
+// instance must see the same directory, e.g. a shared volume. Zero workers
+// leaves jobs queued for another instance.
+func startJobPool(pool *jobs.Pool, cars *carService.CarService, engines *engineService.EngineService, purge *purgeService.PurgeService) error {
+	workers := 2
+	if value := os.Getenv("JOB_WORKERS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid JOB_WORKERS %q", value)
+		}
+		workers = parsed
+	}
+
+	resultDir := os.Getenv("JOB_RESULT_DIR")
+	if resultDir == "" {
+		resultDir = "job-results"
+	}
+
+	pool.Register(jobs.ExportCars, models.RoleViewer, jobs.NewExportCarsRunner(cars, resultDir))
+	pool.Register(jobs.ImportCars, models.RoleEditor, jobs.NewImportCarsRunner(cars, resultDir))
+	pool.Register(jobs.ImportEngines, models.RoleEditor, jobs.NewImportEnginesRunner(engines, resultDir))
+	pool.Register(jobs.PurgeDeleted, models.RoleAdmin, jobs.NewPurgeDeletedRunner(purge))
+
+	if workers == 0 {
+		log.Println("Job workers disabled")
+		return nil
+	}
+
+	pool.Start(context.Background(), workers)
+	return nil
+}
+
+// startWebhookDispatcher sends queued webhook deliveries, checking for new
+// ones every WEBHOOK_POLL_INTERVAL (default 1 second).
+func startWebhookDispatcher(dispatcher *webhookService.Dispatcher) error {
+	interval := time.Second
+	if value := os.Getenv("WEBHOOK_POLL_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid WEBHOOK_POLL_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+
+	go dispatcher.Run(context.Background(), interval)
+	return nil
+}
+
+// startOutboxRelay publishes the events written to the outbox, checking
+// for new ones every OUTBOX_RELAY_INTERVAL (default 1s). Published events
+// are kept for OUTBOX_RETENTION (default 24h, 0 keeps them).
+func startOutboxRelay(relay *outboxService.Relay) error {
+	interval := time.Second
+	if value := os.Getenv("OUTBOX_RELAY_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid OUTBOX_RELAY_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+
+	retention := 24 * time.Hour
+	if value := os.Getenv("OUTBOX_RETENTION"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid OUTBOX_RETENTION %q", value)
+		}
+		retention = parsed
+	}
+
+	go relay.Run(context.Background(), interval, retention)
+	return nil
+}
+
+// startEventHub feeds the event stream, checking for newly published
+// events every EVENT_STREAM_POLL_INTERVAL (default 1s).
+func startEventHub(hub *eventsService.Hub) error {
+	interval := time.Second
+	if value := os.Getenv("EVENT_STREAM_POLL_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return fmt.Errorf("invalid EVENT_STREAM_POLL_INTERVAL %q", value)
+		}
+		interval = parsed
+	}
+
+	go hub.Run(context.Background(), interval)
+	return nil
+}
+
+// newGraphQLHandler builds the /graphql handler. Queries deeper than
+// GRAPHQL_MAX_DEPTH (default 6) or costlier than GRAPHQL_MAX_COMPLEXITY
+// (default 2000) are rejected before they run; 0 disables a limit.
+func newGraphQLHandler(cars *carService.CarService, engines *engineService.EngineService) (*graphqlapi.Handler, error) {
+	limits := graphqlapi.Limits{MaxDepth: 6, MaxComplexity: 2000}
+	if value := os.Getenv("GRAPHQL_MAX_DEPTH"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid GRAPHQL_MAX_DEPTH %q", value)
+		}
+		limits.MaxDepth = parsed
+	}
+	if value := os.Getenv("GRAPHQL_MAX_COMPLEXITY"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid GRAPHQL_MAX_COMPLEXITY %q", value)
+		}
+		limits.MaxComplexity = parsed
+	}
+
+	schema, err := graphqlapi.NewSchema(cars, engines)
+	if err != nil {
+		return nil, err
+	}
+
+	return graphqlapi.NewHandler(schema, engines, limits), nil
+}
+
+// startGRPCServer serves the gRPC API on GRPC_PORT (default 50051).
+func startGRPCServer(cars *carService.CarService, engines *engineService.EngineService, keySet *keys.KeySet, revoked middleware.RevocationChecker, apiKeys middleware.APIKeyAuthenticator) error {
+	port := os.Getenv("GRPC_PORT")
+	if port == "" {
+		port = "50051"
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
 	if err != nil {
 		return err
 	}
+
+	server := grpcapi.NewServer(cars, engines, keySet, revoked, apiKeys)
+
+	log.Printf("gRPC Server Listening on %s", listener.Addr())
+	go func() {
+		log.Fatal(server.Serve(listener))
+	}()
 	return nil
 }
+
 func startTracing() (*sdktrace.TracerProvider, error) {
 	header := map[string]string{
 		"Content-Type": "application/json",
@@ -1,42 +1,118 @@
 package middleware
 
 import (
+	"Car-Management-System/keys"
+	"Car-Management-System/models"
 	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var jwtKey = []byte("some_value")
-
 type Claims struct {
 	UserName string `json:"username"`
+	Role     string `json:"role"`
 	jwt.StandardClaims
 }
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
-			return
-		}
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error)
+}
+
+// AuthError is a failed authentication. Status is the HTTP status to
+// answer with and Message the text to answer with; Err, if set, is the
+// underlying failure, which should be logged rather than shown.
+type AuthError struct {
+	Status  int
+	Message string
+	Err     error
+}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer")
+func (e *AuthError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
 
-		claims := &Claims{}
+// AuthMiddleware accepts either "Authorization: Bearer <jwt>" or an
+// "X-API-Key" header. Both put the caller's username and role into the
+// request context under the same keys.
+func AuthMiddleware(keySet *keys.KeySet, revoked RevocationChecker, apiKeys APIKeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, err := Authenticate(r.Context(), keySet, revoked, apiKeys, r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+			if err != nil {
+				var authErr *AuthError
+				if !errors.As(err, &authErr) {
+					authErr = &AuthError{Status: http.StatusInternalServerError, Message: "Failed to authenticate", Err: err}
+				}
+				if authErr.Err != nil {
+					log.Println("Error authenticating request: ", authErr.Err)
+				}
+				http.Error(w, authErr.Message, authErr.Status)
+				return
+			}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
+	}
+}
 
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid Token", http.StatusUnauthorized)
-			return
+// Authenticate checks an API key, if one is given, or else the bearer
+// token in authorization, and returns ctx with the caller's username and
+// role. It is shared by the HTTP and gRPC servers; failures are returned
+// as *AuthError.
+func Authenticate(ctx context.Context, keySet *keys.KeySet, revoked RevocationChecker, apiKeys APIKeyAuthenticator, authorization string, rawKey string) (context.Context, error) {
+	if rawKey != "" {
+		key, err := apiKeys.AuthenticateAPIKey(ctx, rawKey)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidAPIKey) {
+				return nil, &AuthError{Status: http.StatusUnauthorized, Message: "Invalid API Key"}
+			}
+			return nil, &AuthError{Status: http.StatusInternalServerError, Message: "Failed to verify API key", Err: err}
 		}
 
-		ctx := context.WithValue(r.Context(), "username", claims.UserName)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+		ctx = context.WithValue(ctx, "username", key.UserName)
+		ctx = context.WithValue(ctx, "role", key.Role)
+		ctx = context.WithValue(ctx, "api_key_id", key.ID.String())
+		return ctx, nil
+	}
+
+	if authorization == "" {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "Authorization header required"}
+	}
+
+	tokenString := strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer"))
+
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, keySet.Keyfunc)
+
+	if err != nil || !token.Valid || claims.Id == "" {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "Invalid Token"}
+	}
+
+	isRevoked, err := revoked.IsRevoked(ctx, claims.Id)
+	if err != nil {
+		return nil, &AuthError{Status: http.StatusInternalServerError, Message: "Failed to verify token", Err: err}
+	}
+	if isRevoked {
+		return nil, &AuthError{Status: http.StatusUnauthorized, Message: "Token has been revoked"}
+	}
+
+	ctx = context.WithValue(ctx, "username", claims.UserName)
+	ctx = context.WithValue(ctx, "role", claims.Role)
+	ctx = context.WithValue(ctx, "jti", claims.Id)
+	ctx = context.WithValue(ctx, "token_expires_at", time.Unix(claims.ExpiresAt, 0))
+	return ctx, nil
 }
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"Car-Management-System/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// routePermissions maps "METHOD /path/template" to the minimum role allowed
+// to call it. Routes missing from the table are denied.
+var routePermissions = map[string]string{
+	"POST /logout": models.RoleViewer,
+
+	"GET /cars":               models.RoleViewer,
+	"GET /cars/export":        models.RoleViewer,
+	"GET /cars/vin/{vin}":     models.RoleViewer,
+	"GET /cars/{id}":          models.RoleViewer,
+	"GET /cars/{id}/history":  models.RoleViewer,
+	"POST /cars":              models.RoleEditor,
+	"POST /cars/import":       models.RoleEditor,
+	"PUT /cars/{id}":          models.RoleEditor,
+	"PATCH /cars/{id}":        models.RoleEditor,
+	"DELETE /cars/{id}":       models.RoleEditor,
+	"POST /cars/{id}/restore": models.RoleEditor,
+	"DELETE /cars/{id}/purge": models.RoleAdmin,
+
+	"GET /engine":               models.RoleViewer,
+	"GET /engine/{id}":          models.RoleViewer,
+	"POST /engine":              models.RoleEditor,
+	"POST /engine/import":       models.RoleEditor,
+	"PUT /engine/{id}":          models.RoleEditor,
+	"PATCH /engine/{id}":        models.RoleEditor,
+	"DELETE /engine/{id}":       models.RoleAdmin,
+	"POST /engine/{id}/restore": models.RoleAdmin,
+	"DELETE /engine/{id}/purge": models.RoleAdmin,
+
+	"POST /vin/decode": models.RoleViewer,
+
+	"PUT /users/me/password":   models.RoleViewer,
+	"GET /users":               models.RoleAdmin,
+	"POST /users":              models.RoleAdmin,
+	"POST /users/{id}/disable": models.RoleAdmin,
+	"POST /users/{id}/enable":  models.RoleAdmin,
+	"PUT /users/{id}/role":     models.RoleAdmin,
+	"DELETE /users/{id}":       models.RoleAdmin,
+
+	"GET /api-keys":         models.RoleViewer,
+	"POST /api-keys":        models.RoleViewer,
+	"DELETE /api-keys/{id}": models.RoleViewer,
+
+	"POST /jobs":            models.RoleViewer,
+	"GET /jobs/{id}":        models.RoleViewer,
+	"DELETE /jobs/{id}":     models.RoleViewer,
+	"GET /jobs/{id}/result": models.RoleViewer,
+
+	"GET /webhooks":                           models.RoleAdmin,
+	"POST /webhooks":                          models.RoleAdmin,
+	"GET /webhooks/{id}":                      models.RoleAdmin,
+	"PUT /webhooks/{id}":                      models.RoleAdmin,
+	"DELETE /webhooks/{id}":                   models.RoleAdmin,
+	"GET /webhooks/dead-letters":              models.RoleAdmin,
+	"POST /webhooks/dead-letters/{id}/replay": models.RoleAdmin,
+
+	"GET /events/stream": models.RoleViewer,
+
+	// Mutations check their own roles.
+	"GET /graphql":  models.RoleViewer,
+	"POST /graphql": models.RoleViewer,
+}
+
+type authorizationError struct {
+	Error        string `json:"error"`
+	Message      string `json:"message"`
+	Role         string `json:"role"`
+	RequiredRole string `json:"required_role,omitempty"`
+}
+
+func AuthorizationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, _ := r.Context().Value("role").(string)
+
+		var template string
+		if route := mux.CurrentRoute(r); route != nil {
+			template, _ = route.GetPathTemplate()
+		}
+		key := fmt.Sprintf("%s %s", r.Method, template)
+
+		required, ok := routePermissions[key]
+		if !ok {
+			writeForbidden(w, authorizationError{
+				Error:   "forbidden",
+				Message: fmt.Sprintf("no permission is defined for %s", key),
+				Role:    role,
+			})
+			return
+		}
+
+		if !models.RoleAtLeast(role, required) {
+			writeForbidden(w, authorizationError{
+				Error:        "forbidden",
+				Message:      fmt.Sprintf("role %q is not allowed to call %s", role, key),
+				Role:         role,
+				RequiredRole: required,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeForbidden(w http.ResponseWriter, body authorizationError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	jsonResponse, _ := json.Marshal(body)
+	_, _ = w.Write(jsonResponse)
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -28,6 +29,20 @@ var (
 		},
 	)
 
+	// streamDuration records how long streams stay open. They are kept out
+	// of requestDuration, whose buckets are for requests that last under a
+	// few seconds.
+	streamDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_stream_duration_seconds",
+			Help:    "Duration of streaming http responses in seconds",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+		},
+		[]string{
+			"path", "method",
+		},
+	)
+
 	statusCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "http_response_status_total",
@@ -45,14 +60,14 @@ type responseWriter struct {
 }
 
 func init() {
-	prometheus.MustRegister(requestCounter, requestDuration, statusCounter)
+	prometheus.MustRegister(requestCounter, requestDuration, streamDuration, statusCounter)
 }
 
 func MetricMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		ww := &responseWriter{ResponseWriter: w}
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
 		next.ServeHTTP(ww, r)
 
@@ -60,13 +75,38 @@ func MetricMiddleware(next http.Handler) http.Handler {
 
 		requestCounter.WithLabelValues(r.URL.Path, r.Method).Inc()
 
-		requestDuration.WithLabelValues(r.URL.Path, r.Method).Observe(duration)
+		if isStream(ww) {
+			streamDuration.WithLabelValues(r.URL.Path, r.Method).Observe(duration)
+		} else {
+			requestDuration.WithLabelValues(r.URL.Path, r.Method).Observe(duration)
+		}
 
 		statusCounter.WithLabelValues(r.URL.Path, r.Method, http.StatusText(ww.statusCode)).Inc()
 	})
 }
 
-func (rw *responseWriter) Writeheader(statusCode int) {
+// isStream reports whether the response was a stream, such as the
+// Server-Sent Events of /events/stream, which stays open for as long as the
+// client listens.
+func isStream(w http.ResponseWriter) bool {
+	mediaType, _, _ := strings.Cut(w.Header().Get("Content-Type"), ";")
+	return strings.TrimSpace(mediaType) == "text/event-stream"
+}
+
+func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.statusCode = statusCode
 	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Flush lets streaming handlers, such as the event stream, push what they
+// wrote so far through the middleware.
+func (rw *responseWriter) Flush() {
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
 }
\ No newline at end of file
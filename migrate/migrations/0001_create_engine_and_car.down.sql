@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS car;
+DROP TABLE IF EXISTS engine;
@@ -0,0 +1,35 @@
+-- Create car history table. Every create, update and delete of a car adds
+-- one row holding the full car state before and after the change.
+CREATE TABLE IF NOT EXISTS car_history (
+    id BIGSERIAL PRIMARY KEY,
+    car_id UUID NOT NULL,
+    operation VARCHAR(10) NOT NULL,
+    before_state JSONB,
+    after_state JSONB,
+    changed_by VARCHAR(64),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_car_history_car_id_changed_at ON car_history (car_id, changed_at);
+
+-- Record the current state of existing cars so point-in-time lookups work
+-- from their last update onwards.
+INSERT INTO car_history (car_id, operation, after_state, changed_at)
+SELECT
+    c.id,
+    'create',
+    jsonb_build_object(
+        'id', c.id,
+        'Name', c.name,
+        'year', c.year,
+        'brand', c.brand,
+        'fuel_type', c.fuel_type,
+        'engine', jsonb_build_object('enigne_id', e.id, 'displacement', e.displacement, 'noOfCylinders', e.no_of_cylinders, 'carRange', e.car_range),
+        'price', c.price,
+        'created_at', to_char(c.created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"'),
+        'updated_at', to_char(c.updated_at, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+    ),
+    COALESCE(c.updated_at, c.created_at, CURRENT_TIMESTAMP) AT TIME ZONE 'UTC'
+FROM car c
+JOIN engine e ON c.engine_id = e.id
+WHERE NOT EXISTS (SELECT 1 FROM car_history h WHERE h.car_id = c.id);
@@ -0,0 +1,10 @@
+-- Soft-deleted rows would reappear once the column is gone, so remove them
+-- for good first.
+DELETE FROM car WHERE deleted_at IS NOT NULL;
+DELETE FROM engine WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_car_deleted_at;
+DROP INDEX IF EXISTS idx_engine_deleted_at;
+
+ALTER TABLE car DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE engine DROP COLUMN IF EXISTS deleted_at;
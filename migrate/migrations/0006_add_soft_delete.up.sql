@@ -0,0 +1,8 @@
+-- Soft delete for cars and engines. Rows with deleted_at set are hidden
+-- from the API and hard-deleted once they are older than the retention
+-- period.
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_engine_deleted_at ON engine (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_car_deleted_at ON car (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE car DROP COLUMN IF EXISTS version;
+ALTER TABLE engine DROP COLUMN IF EXISTS version;
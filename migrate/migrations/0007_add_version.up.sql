@@ -0,0 +1,4 @@
+-- Row versions for optimistic concurrency control. Every write bumps the
+-- version; clients send it back in If-Match.
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE car ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,21 @@
+-- Create background job table. Workers claim queued jobs with
+-- FOR UPDATE SKIP LOCKED and keep heartbeat_at fresh while they run, so a
+-- job whose worker died can be claimed again.
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    params JSONB NOT NULL DEFAULT '{}',
+    progress_done BIGINT NOT NULL DEFAULT 0,
+    progress_total BIGINT NOT NULL DEFAULT 0,
+    result_location TEXT,
+    error TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ,
+    heartbeat_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_claimable ON jobs (created_at) WHERE status IN ('queued', 'running');
@@ -0,0 +1,4 @@
+-- The event stream reads published events in (published_at, id) order.
+DROP INDEX IF EXISTS idx_outbox_published_at;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_published ON outbox (published_at, id) WHERE published_at IS NOT NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE engine DROP COLUMN IF EXISTS motor_torque_nm;
+ALTER TABLE engine DROP COLUMN IF EXISTS motor_power_kw;
+ALTER TABLE engine DROP COLUMN IF EXISTS charge_power_kw;
+ALTER TABLE engine DROP COLUMN IF EXISTS battery_kwh;
+ALTER TABLE engine DROP COLUMN IF EXISTS powertrain;
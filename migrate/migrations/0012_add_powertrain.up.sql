@@ -0,0 +1,8 @@
+-- Powertrains. Existing engines are combustion engines; the electric
+-- columns are zero for them.
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS powertrain VARCHAR(4) NOT NULL DEFAULT 'ICE'
+    CHECK (powertrain IN ('ICE', 'BEV', 'PHEV', 'HEV'));
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS battery_kwh REAL NOT NULL DEFAULT 0;
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS charge_power_kw REAL NOT NULL DEFAULT 0;
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS motor_power_kw INT NOT NULL DEFAULT 0;
+ALTER TABLE engine ADD COLUMN IF NOT EXISTS motor_torque_nm INT NOT NULL DEFAULT 0;
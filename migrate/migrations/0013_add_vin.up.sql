@@ -0,0 +1,5 @@
+-- Vehicle identification numbers. Existing cars have none; the unique
+-- index covers deleted cars too, so a restored car keeps its VIN.
+ALTER TABLE car ADD COLUMN IF NOT EXISTS vin VARCHAR(17);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_car_vin ON car (vin);
@@ -0,0 +1,3 @@
+-- The filled engine details are kept; the placeholders they replaced
+-- carried no information.
+SELECT 1;
@@ -0,0 +1,34 @@
+-- Car history used to store the engine of updated and deleted cars as its
+-- id with zero details. Fill those snapshots from the engine as it is now,
+-- which is the closest record left of what it was at the change.
+UPDATE car_history h
+SET before_state = jsonb_set(h.before_state, '{engine}', h.before_state->'engine' || jsonb_build_object(
+        'displacement', e.displacement,
+        'noOfCylinders', e.no_of_cylinders,
+        'carRange', e.car_range,
+        'powertrain', e.powertrain,
+        'batteryKWh', e.battery_kwh,
+        'chargePowerKW', e.charge_power_kw,
+        'motorPowerKW', e.motor_power_kw,
+        'motorTorqueNm', e.motor_torque_nm))
+FROM engine e
+WHERE e.id::text = h.before_state->'engine'->>'enigne_id'
+    AND (h.before_state->'engine'->>'displacement')::numeric = 0
+    AND (h.before_state->'engine'->>'noOfCylinders')::numeric = 0
+    AND (h.before_state->'engine'->>'carRange')::numeric = 0;
+
+UPDATE car_history h
+SET after_state = jsonb_set(h.after_state, '{engine}', h.after_state->'engine' || jsonb_build_object(
+        'displacement', e.displacement,
+        'noOfCylinders', e.no_of_cylinders,
+        'carRange', e.car_range,
+        'powertrain', e.powertrain,
+        'batteryKWh', e.battery_kwh,
+        'chargePowerKW', e.charge_power_kw,
+        'motorPowerKW', e.motor_power_kw,
+        'motorTorqueNm', e.motor_torque_nm))
+FROM engine e
+WHERE e.id::text = h.after_state->'engine'->>'enigne_id'
+    AND (h.after_state->'engine'->>'displacement')::numeric = 0
+    AND (h.after_state->'engine'->>'noOfCylinders')::numeric = 0
+    AND (h.after_state->'engine'->>'carRange')::numeric = 0;
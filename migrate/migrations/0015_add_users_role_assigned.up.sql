@@ -0,0 +1,6 @@
+-- Whether a user's role was chosen, at registration or by an admin, as
+-- opposed to the viewer default given to accounts created before roles
+-- existed. Only the default may be replaced by the startup admin role.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role_assigned BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE users SET role_assigned = TRUE WHERE role <> 'viewer';
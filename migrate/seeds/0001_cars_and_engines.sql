@@ -0,0 +1,25 @@
+-- Insert dummy data into the engine table
+INSERT INTO engine (id, displacement, no_of_cylinders, car_range)
+VALUES
+    ('e1f86b1a-0873-4c19-bae2-fc60329d0140', 2000, 4, 600),
+    ('f4a9c66b-8e38-419b-93c4-215d5cefb318', 1600, 4, 550),
+    ('cc2c2a7d-2e21-4f59-b7b8-bd9e5e4cf04c', 3000, 6, 700),
+    ('9746be12-07b7-42a3-b8ab-7d1f209b63d7', 1800, 4, 500)
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO engine (id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm)
+VALUES
+    ('3b0c5d8e-6f1a-4e2b-9c47-2a8d1e5f7b90', 0, 0, 510, 'BEV', 75, 170, 208, 420),
+    ('8d2e4f6a-1b3c-4d5e-8f70-9a1b2c3d4e5f', 1800, 4, 1100, 'HEV', 1.3, 0, 53, 163)
+ON CONFLICT (id) DO NOTHING;
+
+-- Insert dummy data into the car table
+INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price)
+VALUES
+    ('c7c1a6d5-1ec4-4c64-a59a-8a2f6f3d2bf3', 'Honda Civic', '2023', 'Honda', 'Gasoline', 'e1f86b1a-0873-4c19-bae2-fc60329d0140', 25000.00),
+    ('9d6a56f8-79c3-4931-a5c0-6b290c84ba2f', 'Toyota Corolla', '2022', 'Toyota', 'Gasoline', 'f4a9c66b-8e38-419b-93c4-215d5cefb318', 22000.00),
+    ('9b9437c4-3ed1-45a5-b240-0fe3e24e0e4e', 'Ford Mustang', '2024', 'Ford', 'Gasoline', 'cc2c2a7d-2e21-4f59-b7b8-bd9e5e4cf04c', 40000.00),
+    ('5e9df51a-8d7a-4d84-9c58-4ccfe5c7db06', 'BMW 3 Series', '2023', 'BMW', 'Gasoline', '9746be12-07b7-42a3-b8ab-7d1f209b63d7', 35000.00),
+    ('2f7a9c1e-4b6d-4e8f-a013-5c7e9b2d4f60', 'Tesla Model 3', '2024', 'Tesla', 'Electric', '3b0c5d8e-6f1a-4e2b-9c47-2a8d1e5f7b90', 42000.00),
+    ('6e8b0d2f-5c7a-4f9b-b124-6d8f0a3e5b71', 'Toyota Prius', '2023', 'Toyota', 'Hybrid', '8d2e4f6a-1b3c-4d5e-8f70-9a1b2c3d4e5f', 28000.00)
+ON CONFLICT (id) DO NOTHING;
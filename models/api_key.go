@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+	ErrInvalidAPIKey        = errors.New("invalid, expired or revoked api key")
+	ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+	ErrAPIKeyForbidden      = errors.New("not allowed to manage this api key")
+)
+
+type APIKey struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	UserName   string     `json:"username"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Role       string     `json:"role"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APIKeyRequest struct {
+	Name          string `json:"name"`
+	Role          string `json:"role"`
+	ExpiresInDays int    `json:"expires_in_days"`
+}
+
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+func ValidateAPIKeyRequest(keyReq APIKeyRequest) error {
+	name := strings.TrimSpace(keyReq.Name)
+	if name == "" {
+		return errors.New("Name is required")
+	}
+	if len(name) > 100 {
+		return errors.New("Name must be at most 100 characters")
+	}
+	if err := ValidateRole(keyReq.Role); err != nil {
+		return err
+	}
+	if keyReq.ExpiresInDays < 0 || keyReq.ExpiresInDays > 3650 {
+		return errors.New("expires_in_days must be between 0 and 3650")
+	}
+	return nil
+}
@@ -1,124 +1,237 @@
 package models
 
 import (
+	"Car-Management-System/validation"
+	"Car-Management-System/vin"
 	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Car struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json: "Name"`
-	Year      string    `json: "year"`
-	Brand     string    `json:"brand"`
-	FuelType  string    `json:"fuel_type"`
-	Engine    Engine    `json:"engine"`
-	Price     float32   `json:"price"`
-	CreatedAt time.Time `json: "created_at"`
-	UpdatedAt time.Time `json: "updated_at"`
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"Name"`
+	Year      string     `json:"year"`
+	Brand     string     `json:"brand"`
+	FuelType  string     `json:"fuel_type"`
+	VIN       string     `json:"vin,omitempty"`
+	Engine    Engine     `json:"engine"`
+	Price     float32    `json:"price"`
+	Version   int64      `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CarRequest struct {
-	Name     string  `json: "Name"`
-	Year     string  `json: "year"`
+	Name     string  `json:"Name"`
+	Year     string  `json:"year"`
 	Brand    string  `json:"brand"`
 	FuelType string  `json:"fuel_type"`
+	VIN      string  `json:"vin,omitempty"`
 	Engine   Engine  `json:"engine"`
 	Price    float32 `json:"price"`
 }
 
-func ValidateRequest(carReq CarRequest) error {
-	if err := validateName(carReq.Name); err != nil {
-		return err
-	}
-	if err := validateYear(carReq.Year); err != nil {
-		return err
-	}
-	if err := validateBrand(carReq.Brand); err != nil {
-		return err
-	}
-	if err := validateFuelType(carReq.FuelType); err != nil {
-		return err
-	}
-	if err := validateEngine(carReq.Engine); err != nil {
-		return err
-	}
-	if err := validatePrice(carReq.Price); err != nil {
-		return err
-	}
-	return nil
+var (
+	ErrInvalidSortField = errors.New("invalid sort field")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrCarNotFound      = newError(ErrNotFound, "car not found")
+	ErrCarNotDeleted    = newError(ErrConflict, "car is not deleted")
+	ErrCarEngineMissing = newError(ErrForeignKey, "engine_id does not exists in the engine table")
+	ErrVersionMismatch  = newError(ErrConflict, "resource has been modified since the given version")
+	ErrCarVINTaken      = newError(ErrConflict, "another car already has this vin")
+)
+
+const (
+	CarChangeCreate  = "create"
+	CarChangeUpdate  = "update"
+	CarChangeDelete  = "delete"
+	CarChangeRestore = "restore"
+)
+
+// CarHistory is one recorded change of a car. Before is nil for a create
+// or restore and After is nil for a delete.
+type CarHistory struct {
+	ID        int64     `json:"id"`
+	CarID     uuid.UUID `json:"car_id"`
+	Operation string    `json:"operation"`
+	Before    *Car      `json:"before"`
+	After     *Car      `json:"after"`
+	ChangedBy string    `json:"changed_by,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
-func validateName(name string) error {
-	if name == "" {
-		return errors.New("Name is required")
-	}
-	return nil
+// CarFilter narrows a car listing. The JSON names match the query
+// parameters of GET /cars, so a filter can also be given as a job's params.
+type CarFilter struct {
+	Brand           string  `json:"brand,omitempty"`
+	FuelType        string  `json:"fuelType,omitempty"`
+	MinYear         int     `json:"minYear,omitempty"`
+	MaxYear         int     `json:"maxYear,omitempty"`
+	MinPrice        float64 `json:"minPrice,omitempty"`
+	MaxPrice        float64 `json:"maxPrice,omitempty"`
+	MinDisplacement int32   `json:"minDisplacement,omitempty"`
+	MaxDisplacement int32   `json:"maxDisplacement,omitempty"`
+	Cylinders       int32   `json:"cylinders,omitempty"`
+	SortBy          string  `json:"sortBy,omitempty"`
+	Order           string  `json:"order,omitempty"`
+	Limit           int     `json:"limit,omitempty"`
+	Cursor          string  `json:"cursor,omitempty"`
+	IsEngine        bool    `json:"isEngine,omitempty"`
+	IncludeDeleted  bool    `json:"includeDeleted,omitempty"`
 }
 
-func validateYear(year string) error {
-	if year == "" {
-		return errors.New("Year is required")
-	}
+type CarPage struct {
+	Cars       []Car  `json:"cars"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalCount int    `json:"total_count"`
+	Limit      int    `json:"limit"`
+}
 
-	_, err := strconv.Atoi(year)
-	if err != nil {
-		return errors.New("year must be a valid number")
-	}
+// FuelTypes are the values fuel_type may take.
+var FuelTypes = []string{"Petrol", "Diesel", "Electric", "Hybrid"}
 
-	currentYear := time.Now().Year()
-	yearInt, _ := strconv.Atoi(year)
+const firstCarYear = 1886
 
-	if yearInt < 1886 || yearInt > currentYear {
-		return errors.New("Year must be between 1886 and currentYear")
-	}
+var yearPattern = regexp.MustCompile(`^[0-9]{4}$`)
 
-	return nil
+// fuelPowertrains are the powertrains that can burn or store each fuel.
+var fuelPowertrains = map[string][]string{
+	"Petrol":   {PowertrainICE},
+	"Diesel":   {PowertrainICE},
+	"Electric": {PowertrainBEV},
+	"Hybrid":   {PowertrainHEV, PowertrainPHEV},
 }
 
+// carRules are the rules a car must follow. The engine details are those
+// of the stored engine, which was validated when it was saved, so only
+// its fit to the fuel type is checked here.
+var carRules = validation.Rules[CarRequest]{
+	validation.Required("Name", func(c CarRequest) string { return c.Name }),
+	validation.FirstOf(
+		validation.Required("year", func(c CarRequest) string { return c.Year }),
+		validation.Matches("year", func(c CarRequest) string { return c.Year }, yearPattern, "a four-digit year"),
+		validateYearRange,
+	),
+	validation.Required("brand", func(c CarRequest) string { return c.Brand }),
+	validation.OneOf("fuel_type", func(c CarRequest) string { return c.FuelType }, FuelTypes...),
+	validation.When(func(c CarRequest) bool { return c.VIN != "" },
+		validation.FirstOf(validateVIN, validation.Rules[CarRequest]{validateVINBrand, validateVINYear}.Validate),
+	),
+	validation.Required("engine.enigne_id", func(c CarRequest) uuid.UUID { return c.Engine.EngineID }),
+	validateFuelPowertrain,
+	validation.GreaterThan("price", func(c CarRequest) float32 { return c.Price }, 0),
+}
 
-func validateBrand(brand string) error {
-	if brand == "" {
-		return errors.New("Brand is required")
-	}
-	return nil
+// validateYearRange runs after the year is known to be four digits, with
+// next year's models as the upper bound, the same as for a VIN's model
+// year.
+func validateYearRange(carReq CarRequest) []validation.Violation {
+	year, _ := strconv.Atoi(carReq.Year)
+	return validation.Range("year", func(CarRequest) int { return year }, firstCarYear, vin.LatestModelYear())(carReq)
 }
 
-func validateFuelType(fuelType string) error {
-	validateFuelTypes := []string{"Petrol", "Diesel", "Electric", "Hybrid"}
-	for _, validType := range validateFuelTypes {
-		if fuelType == validType {
-			return nil
-		}
-	}
-	return errors.New("Fuel type must one of: Petrol, Diesel, Electric, Hybrid")
+// validateFuelPowertrain reports an engine whose powertrain cannot run on
+// the fuel type of the car, e.g. a BEV engine in a Petrol car.
+func validateFuelPowertrain(carReq CarRequest) []validation.Violation {
+	allowed, ok := fuelPowertrains[carReq.FuelType]
+	if !ok || carReq.Engine.Type == "" {
+		return nil
+	}
+	if slices.Contains(allowed, carReq.Engine.Type) {
+		return nil
+	}
+	return []validation.Violation{{
+		Field:   "engine.powertrain",
+		Code:    validation.CodeMismatch,
+		Message: fmt.Sprintf("a %s car needs an engine with powertrain %s, not %s", carReq.FuelType, strings.Join(allowed, " or "), carReq.Engine.Type),
+	}}
 }
 
-func validateEngine(engine Engine) error {
-	if engine.EngineID == uuid.Nil {
-		return errors.New("EngineID is required")
+// validateVIN reports a VIN that is malformed or has the wrong check digit.
+func validateVIN(carReq CarRequest) []validation.Violation {
+	err := vin.Validate(carReq.VIN)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, vin.ErrCheckDigit):
+		return []validation.Violation{{Field: "vin", Code: validation.CodeInvalid, Message: err.Error()}}
+	default:
+		return []validation.Violation{{Field: "vin", Code: validation.CodePattern, Message: err.Error()}}
 	}
+}
 
-	if engine.Displacement <= 0 {
-		return errors.New("Displacement must be greater than zero")
-	}
+// validateVINBrand reports a brand other than the one the VIN was issued
+// to, when the bundled table knows it.
+func validateVINBrand(carReq CarRequest) []validation.Violation {
+	info, _ := vin.Decode(carReq.VIN)
+	if info.Brand == "" || carReq.Brand == "" || strings.EqualFold(info.Brand, carReq.Brand) {
+		return nil
+	}
+	return []validation.Violation{{
+		Field:   "brand",
+		Code:    validation.CodeMismatch,
+		Message: fmt.Sprintf("brand does not match the vin, which belongs to %s", info.Brand),
+	}}
+}
 
-	if engine.NoOfCylinders <= 0 {
-		return errors.New("Displacment must be greater than zero")
+// validateVINYear reports a year that is none of the model years the VIN
+// can stand for.
+func validateVINYear(carReq CarRequest) []validation.Violation {
+	year, err := strconv.Atoi(carReq.Year)
+	info, _ := vin.Decode(carReq.VIN)
+	if err != nil || len(info.ModelYears) == 0 || slices.Contains(info.ModelYears, year) {
+		return nil
 	}
 
-	if engine.CarRange <= 0 {
-		return errors.New("carRange must be greater than zero")
+	message := fmt.Sprintf("year does not match the model year of the vin, %d", info.ModelYears[0])
+	if len(info.ModelYears) > 1 {
+		years := make([]string, len(info.ModelYears))
+		for i, y := range info.ModelYears {
+			years[i] = strconv.Itoa(y)
+		}
+		message = "year does not match the vin, whose model year is one of " + strings.Join(years, ", ")
 	}
+	return []validation.Violation{{
+		Field:   "year",
+		Code:    validation.CodeMismatch,
+		Message: message,
+	}}
+}
 
-	return nil
+// ValidateVIN checks v on its own, with the same violations a car with v
+// would get.
+func ValidateVIN(v string) error {
+	return newValidationError(validateVIN(CarRequest{VIN: v}))
 }
 
-func validatePrice(price float32) error {
-	if price <= 0 {
-		return errors.New("Price must be greater than zero")
+// ApplyVIN normalizes the VIN of carReq and fills in an empty brand or
+// year from it. The year is only filled in when the VIN settles on one
+// model year. The rules then cross-check whatever was given.
+func (carReq *CarRequest) ApplyVIN() {
+	carReq.VIN = vin.Normalize(carReq.VIN)
+
+	info, err := vin.Decode(carReq.VIN)
+	if err != nil {
+		return
+	}
+	if carReq.Brand == "" {
+		carReq.Brand = info.Brand
+	}
+	if carReq.Year == "" && info.ModelYear != 0 {
+		carReq.Year = strconv.Itoa(info.ModelYear)
 	}
-	return nil
-}
\ No newline at end of file
+}
+
+// ValidateRequest checks carReq against every rule and reports all the
+// violations as a *ValidationError.
+func ValidateRequest(carReq CarRequest) error {
+	return newValidationError(carRules.Validate(carReq))
+}
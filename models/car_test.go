@@ -0,0 +1,138 @@
+package models
+
+import (
+	"Car-Management-System/validation"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func validCar(fuelType string, powertrain string) CarRequest {
+	return CarRequest{
+		Name:     "Civic",
+		Year:     "2020",
+		Brand:    "Honda",
+		FuelType: fuelType,
+		Engine: Engine{
+			EngineID:   uuid.MustParse("e1f86b1a-0873-4c19-bae2-fc60329d0140"),
+			Powertrain: Powertrain{Type: powertrain},
+		},
+		Price: 25000,
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     func(c *CarRequest)
+		fuel       string
+		powertrain string
+		want       []string
+	}{
+		{name: "petrol ICE", fuel: "Petrol", powertrain: PowertrainICE},
+		{name: "diesel ICE", fuel: "Diesel", powertrain: PowertrainICE},
+		{name: "electric BEV", fuel: "Electric", powertrain: PowertrainBEV},
+		{name: "hybrid HEV", fuel: "Hybrid", powertrain: PowertrainHEV},
+		{name: "hybrid PHEV", fuel: "Hybrid", powertrain: PowertrainPHEV},
+		{name: "petrol BEV", fuel: "Petrol", powertrain: PowertrainBEV, want: []string{"engine.powertrain mismatch"}},
+		{name: "diesel PHEV", fuel: "Diesel", powertrain: PowertrainPHEV, want: []string{"engine.powertrain mismatch"}},
+		{name: "electric ICE", fuel: "Electric", powertrain: PowertrainICE, want: []string{"engine.powertrain mismatch"}},
+		{name: "hybrid ICE", fuel: "Hybrid", powertrain: PowertrainICE, want: []string{"engine.powertrain mismatch"}},
+		{name: "unknown fuel type", fuel: "Hydrogen", powertrain: PowertrainICE, want: []string{"fuel_type not_allowed"}},
+		{
+			name:       "year is not four digits",
+			change:     func(c *CarRequest) { c.Year = "20" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"year pattern"},
+		},
+		{
+			name:       "year before the first car",
+			change:     func(c *CarRequest) { c.Year = "1800" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"year out_of_range"},
+		},
+		{
+			name:       "price is not positive",
+			change:     func(c *CarRequest) { c.Price = 0 },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"price out_of_range"},
+		},
+		{
+			name:       "matching vin",
+			change:     func(c *CarRequest) { c.VIN, c.Year = "1HGCM82633A004352", "2003" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+		},
+		{
+			name:       "malformed vin",
+			change:     func(c *CarRequest) { c.VIN = "1HGCM82633A00435" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"vin pattern"},
+		},
+		{
+			name:       "vin with a wrong check digit",
+			change:     func(c *CarRequest) { c.VIN, c.Year = "1HGCM82643A004352", "2003" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"vin invalid"},
+		},
+		{
+			name:       "vin of another brand and year",
+			change:     func(c *CarRequest) { c.VIN, c.Brand = "1HGCM82633A004352", "Toyota" },
+			fuel:       "Petrol",
+			powertrain: PowertrainICE,
+			want:       []string{"brand mismatch", "year mismatch"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			car := validCar(tt.fuel, tt.powertrain)
+			if tt.change != nil {
+				tt.change(&car)
+			}
+
+			if got := violated(t, ValidateRequest(car)); !slices.Equal(got, tt.want) {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestValidateRequestViolations checks the violations of an empty car in
+// full, as they are sent in the errors list of the problem body.
+func TestValidateRequestViolations(t *testing.T) {
+	err := ValidateRequest(CarRequest{})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("ValidateRequest() error = %v, want a *ValidationError", err)
+	}
+
+	want := []validation.Violation{
+		{Field: "Name", Code: validation.CodeRequired, Message: "Name is required"},
+		{Field: "year", Code: validation.CodeRequired, Message: "year is required"},
+		{Field: "brand", Code: validation.CodeRequired, Message: "brand is required"},
+		{Field: "fuel_type", Code: validation.CodeNotAllowed, Message: "fuel_type must be one of: Petrol, Diesel, Electric, Hybrid"},
+		{Field: "engine.enigne_id", Code: validation.CodeRequired, Message: "engine.enigne_id is required"},
+		{Field: "price", Code: validation.CodeOutOfRange, Message: "price must be greater than 0"},
+	}
+	if !slices.Equal(validationErr.Errors, want) {
+		t.Fatalf("violations = %+v, want %+v", validationErr.Errors, want)
+	}
+
+	body, err := json.Marshal(validationErr.Errors[0])
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got := string(body); got != `{"field":"Name","code":"required","message":"Name is required"}` {
+		t.Fatalf("violation JSON = %s", got)
+	}
+}
@@ -1,57 +1,141 @@
 package models
 
 import (
-	"errors"
+	"Car-Management-System/validation"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	ErrEngineNotFound   = newError(ErrNotFound, "engine not found")
+	ErrEngineNotDeleted = newError(ErrConflict, "engine is not deleted")
+	ErrEngineDeleted    = newError(ErrConflict, "engine is deleted")
+)
+
+// The powertrain types. HEV is a hybrid that charges from its engine,
+// PHEV one that also plugs in.
+const (
+	PowertrainICE  = "ICE"
+	PowertrainBEV  = "BEV"
+	PowertrainPHEV = "PHEV"
+	PowertrainHEV  = "HEV"
+)
+
+var PowertrainTypes = []string{PowertrainICE, PowertrainBEV, PowertrainPHEV, PowertrainHEV}
+
+// Powertrain is what drives an engine. The electric fields are zero for
+// an ICE engine, and a BEV has no displacement or cylinders.
+type Powertrain struct {
+	Type          string  `json:"powertrain"`
+	BatteryKWh    float32 `json:"batteryKWh,omitempty"`
+	ChargePowerKW float32 `json:"chargePowerKW,omitempty"`
+	MotorPowerKW  int32   `json:"motorPowerKW,omitempty"`
+	MotorTorqueNm int32   `json:"motorTorqueNm,omitempty"`
+}
+
+// SetDefaultType makes a powertrain without a type ICE, which is what
+// every engine was before powertrains had types.
+func (p *Powertrain) SetDefaultType() {
+	if p.Type == "" {
+		p.Type = PowertrainICE
+	}
+}
+
+func (p Powertrain) hasCombustion() bool {
+	return p.Type != PowertrainBEV
+}
+
+func (p Powertrain) hasMotor() bool {
+	return p.Type != PowertrainICE
+}
+
+func (p Powertrain) pluggable() bool {
+	return p.Type == PowertrainBEV || p.Type == PowertrainPHEV
+}
+
 type Engine struct {
 	EngineID      uuid.UUID `json:"enigne_id"`
 	Displacement  int32     `json:"displacement"`
-	NoOfCylinders int32     `json:"noOfCylinders`
+	NoOfCylinders int32     `json:"noOfCylinders"`
 	CarRange      int32     `json:"carRange"`
+	Powertrain
+	Version   int64      `json:"version,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type EngineRequest struct {
 	Displacement  int32 `json:"displacement"`
-	NoOfCylinders int32 `json:"noOfCylinders`
+	NoOfCylinders int32 `json:"noOfCylinders"`
 	CarRange      int32 `json:"carRange"`
+	Powertrain
 }
 
-func ValidateEngineRequest(EngineReq EngineRequest) error {
-	if err := validateDisplacement(EngineReq.Displacement); err != nil {
-		return err
-	}
-
-	if err := validateNoOfCylinders(EngineReq.NoOfCylinders); err != nil {
-		return err
-	}
+type EngineFilter struct {
+	MinDisplacement int32
+	MaxDisplacement int32
+	Cylinders       int32
+	MinRange        int32
+	MaxRange        int32
+	WithCars        bool
+	Limit           int
+	Cursor          string
+	IncludeDeleted  bool
+}
 
-	if err := validateCarRange(EngineReq.CarRange); err != nil {
-		return err
-	}
-	
-	return nil
+type EngineListItem struct {
+	Engine
+	CarCount *int        `json:"car_count,omitempty"`
+	CarIDs   []uuid.UUID `json:"car_ids,omitempty"`
 }
 
-func validateDisplacement(displacement int32) error {
-	if displacement <= 0 {
-		return errors.New("Displacment must be greater than zero")
-	}
-	return nil
+type EnginePage struct {
+	Engines    []EngineListItem `json:"engines"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	TotalCount int              `json:"total_count"`
+	Limit      int              `json:"limit"`
 }
 
-func validateNoOfCylinders(noOfCylinder int32) error {
-	if noOfCylinder <= 0 {
-		return errors.New("noOfCylinder must be greater than zero")
-	}
-	return nil
+// maxCylinders bounds noOfCylinders.
+const maxCylinders = 16
+
+// engineRules check the combustion and electric parts an engine has by
+// its powertrain type and reject the fields of the parts it lacks. An
+// unknown type is reported on its own.
+var engineRules = validation.Rules[EngineRequest]{
+	validation.OneOf("powertrain", func(e EngineRequest) string { return e.Type }, PowertrainTypes...),
+	validation.When(func(e EngineRequest) bool { return slices.Contains(PowertrainTypes, e.Type) },
+		validation.When(func(e EngineRequest) bool { return e.hasCombustion() },
+			validation.GreaterThan("displacement", func(e EngineRequest) int32 { return e.Displacement }, 0),
+			validation.Range("noOfCylinders", func(e EngineRequest) int32 { return e.NoOfCylinders }, 1, maxCylinders),
+		),
+		validation.When(func(e EngineRequest) bool { return !e.hasCombustion() },
+			validation.Absent("displacement", func(e EngineRequest) int32 { return e.Displacement }),
+			validation.Absent("noOfCylinders", func(e EngineRequest) int32 { return e.NoOfCylinders }),
+		),
+		validation.When(func(e EngineRequest) bool { return e.hasMotor() },
+			validation.GreaterThan("batteryKWh", func(e EngineRequest) float32 { return e.BatteryKWh }, 0),
+			validation.GreaterThan("motorPowerKW", func(e EngineRequest) int32 { return e.MotorPowerKW }, 0),
+			validation.GreaterThan("motorTorqueNm", func(e EngineRequest) int32 { return e.MotorTorqueNm }, 0),
+		),
+		validation.When(func(e EngineRequest) bool { return !e.hasMotor() },
+			validation.Absent("batteryKWh", func(e EngineRequest) float32 { return e.BatteryKWh }),
+			validation.Absent("motorPowerKW", func(e EngineRequest) int32 { return e.MotorPowerKW }),
+			validation.Absent("motorTorqueNm", func(e EngineRequest) int32 { return e.MotorTorqueNm }),
+		),
+		validation.When(func(e EngineRequest) bool { return e.pluggable() },
+			validation.GreaterThan("chargePowerKW", func(e EngineRequest) float32 { return e.ChargePowerKW }, 0),
+		),
+		validation.When(func(e EngineRequest) bool { return !e.pluggable() },
+			validation.Absent("chargePowerKW", func(e EngineRequest) float32 { return e.ChargePowerKW }),
+		),
+	),
+	validation.GreaterThan("carRange", func(e EngineRequest) int32 { return e.CarRange }, 0),
 }
 
-func validateCarRange(carRange int32) error {
-	if carRange <= 0 {
-		return errors.New("noOfCylinder must be greater than zero")
-	}
-	return nil
+// ValidateEngineRequest checks EngineReq against every rule and reports
+// all the violations as a *ValidationError.
+func ValidateEngineRequest(EngineReq EngineRequest) error {
+	return newValidationError(engineRules.Validate(EngineReq))
 }
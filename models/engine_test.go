@@ -0,0 +1,131 @@
+package models
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// violated lists the field and code of every violation in err, in order.
+func violated(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want a *ValidationError", err)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("error = %v does not match ErrValidation", err)
+	}
+
+	fields := make([]string, len(validationErr.Errors))
+	for i, violation := range validationErr.Errors {
+		fields[i] = violation.Field + " " + violation.Code
+	}
+	return fields
+}
+
+func TestValidateEngineRequest(t *testing.T) {
+	electric := Powertrain{BatteryKWh: 75, ChargePowerKW: 150, MotorPowerKW: 200, MotorTorqueNm: 400}
+
+	tests := []struct {
+		name   string
+		engine EngineRequest
+		want   []string
+	}{
+		{
+			name:   "ICE",
+			engine: EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 600, Powertrain: Powertrain{Type: PowertrainICE}},
+		},
+		{
+			name:   "ICE without its required fields",
+			engine: EngineRequest{Powertrain: Powertrain{Type: PowertrainICE}},
+			want:   []string{"displacement out_of_range", "noOfCylinders out_of_range", "carRange out_of_range"},
+		},
+		{
+			name: "ICE with electric fields",
+			engine: EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 600, Powertrain: Powertrain{
+				Type: PowertrainICE, BatteryKWh: 1, ChargePowerKW: 7, MotorPowerKW: 50, MotorTorqueNm: 100,
+			}},
+			want: []string{"batteryKWh not_allowed", "motorPowerKW not_allowed", "motorTorqueNm not_allowed", "chargePowerKW not_allowed"},
+		},
+		{
+			name:   "ICE with too many cylinders",
+			engine: EngineRequest{Displacement: 8000, NoOfCylinders: 17, CarRange: 400, Powertrain: Powertrain{Type: PowertrainICE}},
+			want:   []string{"noOfCylinders out_of_range"},
+		},
+		{
+			name:   "BEV",
+			engine: EngineRequest{CarRange: 450, Powertrain: withType(electric, PowertrainBEV)},
+		},
+		{
+			name:   "BEV without its required fields",
+			engine: EngineRequest{Powertrain: Powertrain{Type: PowertrainBEV}},
+			want:   []string{"batteryKWh out_of_range", "motorPowerKW out_of_range", "motorTorqueNm out_of_range", "chargePowerKW out_of_range", "carRange out_of_range"},
+		},
+		{
+			name:   "BEV with combustion fields",
+			engine: EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 450, Powertrain: withType(electric, PowertrainBEV)},
+			want:   []string{"displacement not_allowed", "noOfCylinders not_allowed"},
+		},
+		{
+			name:   "PHEV",
+			engine: EngineRequest{Displacement: 2000, NoOfCylinders: 4, CarRange: 800, Powertrain: withType(electric, PowertrainPHEV)},
+		},
+		{
+			name:   "PHEV without its required fields",
+			engine: EngineRequest{Powertrain: Powertrain{Type: PowertrainPHEV}},
+			want: []string{
+				"displacement out_of_range", "noOfCylinders out_of_range",
+				"batteryKWh out_of_range", "motorPowerKW out_of_range", "motorTorqueNm out_of_range",
+				"chargePowerKW out_of_range", "carRange out_of_range",
+			},
+		},
+		{
+			name: "HEV",
+			engine: EngineRequest{Displacement: 1800, NoOfCylinders: 4, CarRange: 900, Powertrain: Powertrain{
+				Type: PowertrainHEV, BatteryKWh: 1.3, MotorPowerKW: 53, MotorTorqueNm: 163,
+			}},
+		},
+		{
+			name:   "HEV without its required fields",
+			engine: EngineRequest{Powertrain: Powertrain{Type: PowertrainHEV}},
+			want: []string{
+				"displacement out_of_range", "noOfCylinders out_of_range",
+				"batteryKWh out_of_range", "motorPowerKW out_of_range", "motorTorqueNm out_of_range",
+				"carRange out_of_range",
+			},
+		},
+		{
+			name:   "HEV with a charge port",
+			engine: EngineRequest{Displacement: 1800, NoOfCylinders: 4, CarRange: 900, Powertrain: withType(electric, PowertrainHEV)},
+			want:   []string{"chargePowerKW not_allowed"},
+		},
+		{
+			name:   "unknown powertrain",
+			engine: EngineRequest{Displacement: 1500, CarRange: 500, Powertrain: Powertrain{Type: "FCEV"}},
+			want:   []string{"powertrain not_allowed"},
+		},
+		{
+			name:   "missing powertrain",
+			engine: EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 600},
+			want:   []string{"powertrain not_allowed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := violated(t, ValidateEngineRequest(tt.engine)); !slices.Equal(got, tt.want) {
+				t.Fatalf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func withType(p Powertrain, powertrain string) Powertrain {
+	p.Type = powertrain
+	return p
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"Car-Management-System/validation"
+	"errors"
+	"strings"
+)
+
+// The kinds of domain error. The errors of the store and service layers
+// match one of them with errors.Is, which is what callers should check
+// when they only care about the kind.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+	ErrForeignKey = errors.New("referenced resource does not exist")
+)
+
+// kindError is a domain error with its own message that also matches its
+// kind.
+type kindError struct {
+	kind    error
+	message string
+}
+
+func newError(kind error, message string) error {
+	return &kindError{kind: kind, message: message}
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// ValidationError lists every rule a request breaks. It matches
+// ErrValidation.
+type ValidationError struct {
+	Errors []validation.Violation
+}
+
+// newValidationError returns nil when there are no violations, so that a
+// validator can return its result directly.
+func newValidationError(violations []validation.Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: violations}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, violation := range e.Errors {
+		messages = append(messages, violation.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
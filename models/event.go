@@ -0,0 +1,165 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventCarCreated  = "car.created"
+	EventCarUpdated  = "car.updated"
+	EventCarDeleted  = "car.deleted"
+	EventCarRestored = "car.restored"
+	EventCarPurged   = "car.purged"
+
+	EventEngineCreated  = "engine.created"
+	EventEngineUpdated  = "engine.updated"
+	EventEngineDeleted  = "engine.deleted"
+	EventEngineRestored = "engine.restored"
+	EventEnginePurged   = "engine.purged"
+)
+
+// EventStreamReset tells an event stream subscriber that events it
+// missed can no longer be replayed, so it should reload what it needs.
+const EventStreamReset = "stream.reset"
+
+var (
+	ErrEventNotFound      = errors.New("event not found")
+	ErrInvalidEventFilter = errors.New("invalid event filter")
+)
+
+var EventTypes = []string{
+	EventCarCreated, EventCarUpdated, EventCarDeleted, EventCarRestored, EventCarPurged,
+	EventEngineCreated, EventEngineUpdated, EventEngineDeleted, EventEngineRestored, EventEnginePurged,
+}
+
+// Event describes one committed change of a car or an engine. Data is the
+// car or engine after the change; purge events carry only its id. Sequence
+// is the event's position in the outbox, set once it has been written, and
+// PublishedAt is set once the relay has published it.
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	Sequence    int64           `json:"sequence,omitempty"`
+	Type        string          `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// EventCursor is a position in the order events were published in. The
+// relay publishes events in batches, one at a time, so later batches
+// always sort after earlier ones, unlike sequences which follow the order
+// events were written in.
+type EventCursor struct {
+	PublishedAt time.Time
+	Sequence    int64
+}
+
+// Cursor returns the position of a published event.
+func (e Event) Cursor() EventCursor {
+	cursor := EventCursor{Sequence: e.Sequence}
+	if e.PublishedAt != nil {
+		cursor.PublishedAt = *e.PublishedAt
+	}
+	return cursor
+}
+
+// After reports whether c comes after other.
+func (c EventCursor) After(other EventCursor) bool {
+	if !c.PublishedAt.Equal(other.PublishedAt) {
+		return c.PublishedAt.After(other.PublishedAt)
+	}
+	return c.Sequence > other.Sequence
+}
+
+func NewEvent(eventType string, aggregateID uuid.UUID, data interface{}) (Event, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:          uuid.New(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now().UTC(),
+		Data:        body,
+	}, nil
+}
+
+// EventMatches reports whether eventType is selected by filters. Filters
+// are event types, "car.*" or "engine.*" for every event of one resource,
+// or "*"; no filters select everything.
+func EventMatches(filters []string, eventType string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+
+	resource, _, _ := strings.Cut(eventType, ".")
+	for _, filter := range filters {
+		if filter == "*" || filter == eventType || filter == resource+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidEventFilter reports whether filter can select any event type.
+func ValidEventFilter(filter string) bool {
+	if filter == "*" || filter == "car.*" || filter == "engine.*" {
+		return true
+	}
+	for _, eventType := range EventTypes {
+		if filter == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EventStreamFilter selects the events sent to an event stream subscriber.
+// Types work like webhook event filters. Brand selects the car events of
+// cars of that brand; engine events and car purge events, which carry no
+// brand, are left out when it is set.
+type EventStreamFilter struct {
+	Types []string
+	Brand string
+}
+
+func NewEventStreamFilter(types []string, brand string) (EventStreamFilter, error) {
+	for _, eventType := range types {
+		if !ValidEventFilter(eventType) {
+			return EventStreamFilter{}, fmt.Errorf("%w: unknown event type %q", ErrInvalidEventFilter, eventType)
+		}
+	}
+
+	return EventStreamFilter{Types: types, Brand: brand}, nil
+}
+
+// Matches reports whether event is selected by f.
+func (f EventStreamFilter) Matches(event Event) bool {
+	if !EventMatches(f.Types, event.Type) {
+		return false
+	}
+	if f.Brand == "" {
+		return true
+	}
+
+	if !strings.HasPrefix(event.Type, "car.") {
+		return false
+	}
+
+	var car struct {
+		Brand string `json:"brand"`
+	}
+	if err := json.Unmarshal(event.Data, &car); err != nil {
+		return false
+	}
+	return car.Brand == f.Brand
+}
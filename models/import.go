@@ -0,0 +1,58 @@
+package models
+
+import (
+	"Car-Management-System/validation"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnsupportedImportFormat = errors.New("import must be text/csv or application/x-ndjson")
+	ErrInvalidImport           = errors.New("invalid import file")
+	ErrImportRejected          = errors.New("import has invalid rows, nothing was imported")
+)
+
+// CarImportRow is one decoded row of a car import. Err is set when the
+// row could not be decoded at all.
+type CarImportRow struct {
+	Line int
+	Car  CarRequest
+	Err  error
+}
+
+type EngineImportRow struct {
+	Line   int
+	Engine EngineRequest
+	Err    error
+}
+
+// ImportRowError is why a row was rejected. Fields lists the violations
+// when the row decoded but failed validation.
+type ImportRowError struct {
+	Line   int                    `json:"line"`
+	Error  string                 `json:"error"`
+	Fields []validation.Violation `json:"fields,omitempty"`
+}
+
+func NewImportRowError(line int, err error) ImportRowError {
+	rowErr := ImportRowError{Line: line, Error: err.Error()}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		rowErr.Fields = validationErr.Errors
+	}
+
+	return rowErr
+}
+
+// ImportReport describes an import. Imported stays 0 on a dry run and
+// whenever any row is invalid, as imports are all or nothing.
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Valid    int              `json:"valid"`
+	Imported int              `json:"imported"`
+	IDs      []uuid.UUID      `json:"ids,omitempty"`
+	Errors   []ImportRowError `json:"errors"`
+}
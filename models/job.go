@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+var (
+	ErrJobNotFound          = errors.New("job not found")
+	ErrInvalidJobRequest    = errors.New("invalid job request")
+	ErrJobForbidden         = errors.New("not allowed to access this job")
+	ErrJobFinished          = errors.New("job has already finished")
+	ErrJobResultUnavailable = errors.New("job has no result to download")
+)
+
+type Job struct {
+	ID             uuid.UUID       `json:"id"`
+	Type           string          `json:"type"`
+	Status         string          `json:"status"`
+	Params         json.RawMessage `json:"params"`
+	ProgressDone   int64           `json:"progress_done"`
+	ProgressTotal  int64           `json:"progress_total"`
+	ResultLocation string          `json:"result_location,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	Attempts       int             `json:"attempts"`
+	CreatedBy      string          `json:"created_by,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+}
+
+type JobRequest struct {
+	Type   string          `json:"type"`
+	Params json.RawMessage `json:"params"`
+}
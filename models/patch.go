@@ -0,0 +1,78 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+var (
+	ErrUnsupportedPatchType = errors.New("patch must be application/merge-patch+json or application/json-patch+json")
+	ErrMalformedPatch       = errors.New("malformed patch document")
+	ErrUnprocessablePatch   = errors.New("patch cannot be applied")
+)
+
+// Patch is a partial update as either a JSON Merge Patch (RFC 7386) or a
+// JSON Patch (RFC 6902) document.
+type Patch struct {
+	ContentType string
+	Body        []byte
+}
+
+func NewPatch(contentType string, body []byte) (*Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != MergePatchType && mediaType != JSONPatchType) {
+		return nil, ErrUnsupportedPatchType
+	}
+
+	return &Patch{ContentType: mediaType, Body: body}, nil
+}
+
+// Apply patches the JSON form of doc and decodes the result into dst.
+// Fields that dst does not have are rejected rather than dropped.
+func (p *Patch) Apply(doc interface{}, dst interface{}) error {
+	original, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	var patched []byte
+	switch p.ContentType {
+	case MergePatchType:
+		if !json.Valid(p.Body) {
+			return ErrMalformedPatch
+		}
+		patched, err = jsonpatch.MergePatch(original, p.Body)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnprocessablePatch, err)
+		}
+	case JSONPatchType:
+		operations, decodeErr := jsonpatch.DecodePatch(p.Body)
+		if decodeErr != nil {
+			return fmt.Errorf("%w: %v", ErrMalformedPatch, decodeErr)
+		}
+		patched, err = operations.Apply(original)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnprocessablePatch, err)
+		}
+	default:
+		return ErrUnsupportedPatchType
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnprocessablePatch, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewPatch(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+		err         error
+	}{
+		{contentType: "application/merge-patch+json", want: MergePatchType},
+		{contentType: "application/merge-patch+json; charset=utf-8", want: MergePatchType},
+		{contentType: "application/json-patch+json", want: JSONPatchType},
+		{contentType: "application/json", err: ErrUnsupportedPatchType},
+		{contentType: "", err: ErrUnsupportedPatchType},
+		{contentType: "not a media type;", err: ErrUnsupportedPatchType},
+	}
+
+	for _, tt := range tests {
+		patch, err := NewPatch(tt.contentType, []byte("{}"))
+		if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("NewPatch(%q) error = %v, want %v", tt.contentType, err, tt.err)
+			continue
+		}
+		if err == nil && patch.ContentType != tt.want {
+			t.Errorf("NewPatch(%q) content type = %q, want %q", tt.contentType, patch.ContentType, tt.want)
+		}
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	current := EngineRequest{
+		Displacement:  1500,
+		NoOfCylinders: 4,
+		CarRange:      600,
+		Powertrain:    Powertrain{Type: PowertrainICE},
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        EngineRequest
+		err         error
+	}{
+		{
+			name:        "merge patch changes a field",
+			contentType: MergePatchType,
+			body:        `{"carRange": 700}`,
+			want:        EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 700, Powertrain: Powertrain{Type: PowertrainICE}},
+		},
+		{
+			name:        "merge patch null resets a field",
+			contentType: MergePatchType,
+			body:        `{"displacement": null}`,
+			want:        EngineRequest{NoOfCylinders: 4, CarRange: 600, Powertrain: Powertrain{Type: PowertrainICE}},
+		},
+		{
+			name:        "empty merge patch keeps everything",
+			contentType: MergePatchType,
+			body:        `{}`,
+			want:        current,
+		},
+		{
+			name:        "merge patch with an unknown field",
+			contentType: MergePatchType,
+			body:        `{"turbo": true}`,
+			err:         ErrUnprocessablePatch,
+		},
+		{
+			name:        "merge patch with the wrong type",
+			contentType: MergePatchType,
+			body:        `{"carRange": "far"}`,
+			err:         ErrUnprocessablePatch,
+		},
+		{
+			name:        "malformed merge patch",
+			contentType: MergePatchType,
+			body:        `{"carRange": `,
+			err:         ErrMalformedPatch,
+		},
+		{
+			name:        "json patch replaces a field",
+			contentType: JSONPatchType,
+			body:        `[{"op": "replace", "path": "/noOfCylinders", "value": 6}]`,
+			want:        EngineRequest{Displacement: 1500, NoOfCylinders: 6, CarRange: 600, Powertrain: Powertrain{Type: PowertrainICE}},
+		},
+		{
+			name:        "json patch with a passing test",
+			contentType: JSONPatchType,
+			body:        `[{"op": "test", "path": "/carRange", "value": 600}, {"op": "replace", "path": "/carRange", "value": 650}]`,
+			want:        EngineRequest{Displacement: 1500, NoOfCylinders: 4, CarRange: 650, Powertrain: Powertrain{Type: PowertrainICE}},
+		},
+		{
+			name:        "json patch with a failing test",
+			contentType: JSONPatchType,
+			body:        `[{"op": "test", "path": "/carRange", "value": 1}, {"op": "replace", "path": "/carRange", "value": 650}]`,
+			err:         ErrUnprocessablePatch,
+		},
+		{
+			name:        "json patch on a missing path",
+			contentType: JSONPatchType,
+			body:        `[{"op": "replace", "path": "/engine/carRange", "value": 650}]`,
+			err:         ErrUnprocessablePatch,
+		},
+		{
+			name:        "json patch adding an unknown field",
+			contentType: JSONPatchType,
+			body:        `[{"op": "add", "path": "/turbo", "value": true}]`,
+			err:         ErrUnprocessablePatch,
+		},
+		{
+			name:        "json patch that is not a list",
+			contentType: JSONPatchType,
+			body:        `{"op": "replace", "path": "/carRange", "value": 650}`,
+			err:         ErrMalformedPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := NewPatch(tt.contentType, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("NewPatch() error = %v", err)
+			}
+
+			var got EngineRequest
+			err = patch.Apply(current, &got)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && got != tt.want {
+				t.Fatalf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
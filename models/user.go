@@ -0,0 +1,106 @@
+package models
+
+import (
+	"errors"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserExists         = errors.New("username already exists")
+	ErrInvalidCredentials = errors.New("incorrect username or password")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrInvalidUserRequest = errors.New("invalid user request")
+)
+
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	UserName     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	// RoleAssigned is false for an account created before roles existed,
+	// whose viewer role is a default no one chose.
+	RoleAssigned bool      `json:"-"`
+	Disabled     bool      `json:"disabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type UserRequest struct {
+	UserName string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role"`
+}
+
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func ValidateUserRequest(userReq UserRequest) error {
+	if err := validateUserName(userReq.UserName); err != nil {
+		return err
+	}
+	if err := ValidatePassword(userReq.Password); err != nil {
+		return err
+	}
+	if err := ValidateRole(userReq.Role); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ValidateRole(role string) error {
+	if _, ok := roleRanks[role]; !ok {
+		return errors.New("Role must be one of: viewer, editor, admin")
+	}
+	return nil
+}
+
+// RoleAtLeast reports whether role grants every permission of required.
+// Roles are ordered viewer < editor < admin.
+func RoleAtLeast(role string, required string) bool {
+	rank, ok := roleRanks[role]
+	return ok && rank >= roleRanks[required]
+}
+
+func validateUserName(userName string) error {
+	if len(userName) < 3 || len(userName) > 64 {
+		return errors.New("Username must be between 3 and 64 characters")
+	}
+	for _, r := range userName {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '_' && r != '-' {
+			return errors.New("Username may only contain letters, digits, '.', '_' and '-'")
+		}
+	}
+	return nil
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < 8 {
+		return errors.New("Password must be at least 8 characters")
+	}
+	if len(password) > 72 {
+		return errors.New("Password must be at most 72 characters")
+	}
+	return nil
+}
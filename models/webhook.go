@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrInvalidWebhookRequest = errors.New("invalid webhook request")
+	ErrDeadLetterNotFound    = errors.New("dead letter not found")
+)
+
+// Webhook is a subscription that receives the events matching Events at
+// URL. Secret signs every delivery and is only shown when it is created.
+type Webhook struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"-"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is an event waiting to be sent to one webhook.
+type WebhookDelivery struct {
+	ID            int64
+	WebhookID     uuid.UUID
+	URL           string
+	Secret        string
+	EventID       uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// DeadLetter is a delivery that failed on every attempt. Replaying it
+// queues it again; the dead letter is kept with its replay time.
+type DeadLetter struct {
+	ID         int64           `json:"id"`
+	WebhookID  uuid.UUID       `json:"webhook_id"`
+	EventID    uuid.UUID       `json:"event_id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error"`
+	FailedAt   time.Time       `json:"failed_at"`
+	ReplayedAt *time.Time      `json:"replayed_at,omitempty"`
+}
+
+func ValidateWebhookRequest(webhookReq WebhookRequest) error {
+	target, err := url.Parse(webhookReq.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if len(webhookReq.URL) > 2048 {
+		return errors.New("url must be at most 2048 characters")
+	}
+
+	for _, filter := range webhookReq.Events {
+		if !ValidEventFilter(filter) {
+			return fmt.Errorf("unknown event %q, events must be event types, \"car.*\", \"engine.*\" or \"*\"", filter)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package openapi serves the OpenAPI document of the REST API and checks
+// requests against it.
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// specURL names the document inside the schema compiler.
+const specURL = "urn:car-management-system:openapi.json"
+
+type parameter struct {
+	Ref      string                 `json:"$ref"`
+	Name     string                 `json:"name"`
+	In       string                 `json:"in"`
+	Required bool                   `json:"required"`
+	Schema   map[string]interface{} `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                       `json:"required"`
+	Content  map[string]json.RawMessage `json:"content"`
+}
+
+type operation struct {
+	Parameters  []parameter  `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+}
+
+type spec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Parameters map[string]parameter `json:"parameters"`
+	} `json:"components"`
+}
+
+// Document returns the OpenAPI document as JSON.
+func Document() []byte {
+	return document
+}
+
+// Operations returns every operation of the document as
+// "METHOD /path/template", sorted.
+func Operations() ([]string, error) {
+	var s spec
+	if err := json.Unmarshal(document, &s); err != nil {
+		return nil, err
+	}
+
+	var operations []string
+	for path, item := range s.Paths {
+		for method := range item {
+			if isMethod(method) {
+				operations = append(operations, fmt.Sprintf("%s %s", strings.ToUpper(method), path))
+			}
+		}
+	}
+	sort.Strings(operations)
+	return operations, nil
+}
+
+func isMethod(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "patch", "head", "options", "trace":
+		return true
+	}
+	return false
+}
+
+// pointer returns the JSON pointer to the given keys, escaped for use as
+// a URL fragment.
+func pointer(keys ...string) string {
+	var b strings.Builder
+	for _, key := range keys {
+		key = strings.ReplaceAll(key, "~", "~0")
+		key = strings.ReplaceAll(key, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(key)
+	}
+	return b.String()
+}
+
+func newCompiler() (*jsonschema.Compiler, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(document))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(specURL, doc); err != nil {
+		return nil, err
+	}
+	return compiler, nil
+}
+
+// compile compiles the schema at the JSON pointer ptr of the document.
+func compile(compiler *jsonschema.Compiler, ptr string) (*jsonschema.Schema, error) {
+	return compiler.Compile(specURL + "#" + ptr)
+}
+
+var methods = map[string]string{
+	"get":    http.MethodGet,
+	"put":    http.MethodPut,
+	"post":   http.MethodPost,
+	"delete": http.MethodDelete,
+	"patch":  http.MethodPatch,
+}
@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/validation"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// maxBodyBytes bounds the JSON bodies the validator reads. Larger bodies,
+// and bodies that are not JSON, such as imports, are left to the handler.
+const maxBodyBytes = 1 << 20
+
+type queryParam struct {
+	name     string
+	typ      string
+	required bool
+	schema   *jsonschema.Schema
+}
+
+type checkedOperation struct {
+	query        []queryParam
+	bodyRequired bool
+	// bodies maps the JSON media types of the request body to their
+	// schemas. Other media types are accepted without a check.
+	bodies    map[string]*jsonschema.Schema
+	mediaType []string
+}
+
+// Validator checks requests against the operations of the document.
+type Validator struct {
+	operations map[string]*checkedOperation
+}
+
+// NewValidator compiles the query parameter and request body schemas of
+// every operation in the document.
+func NewValidator() (*Validator, error) {
+	var s spec
+	if err := json.Unmarshal(document, &s); err != nil {
+		return nil, err
+	}
+
+	compiler, err := newCompiler()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Validator{operations: map[string]*checkedOperation{}}
+	for path, item := range s.Paths {
+		var shared []parameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+		}
+
+		for key, raw := range item {
+			method, ok := methods[key]
+			if !ok {
+				continue
+			}
+
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+
+			checked, err := compileOperation(compiler, s, path, key, shared, op)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			v.operations[method+" "+path] = checked
+		}
+	}
+
+	return v, nil
+}
+
+func compileOperation(compiler *jsonschema.Compiler, s spec, path string, method string, shared []parameter, op operation) (*checkedOperation, error) {
+	checked := &checkedOperation{bodies: map[string]*jsonschema.Schema{}}
+
+	params := map[string]parameter{}
+	ptrs := map[string]string{}
+	add := func(list []parameter, base ...string) error {
+		for i, p := range list {
+			ptr := pointer(append(base, "parameters", strconv.Itoa(i), "schema")...)
+			if p.Ref != "" {
+				name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+				resolved, ok := s.Components.Parameters[name]
+				if !ok {
+					return fmt.Errorf("unknown parameter %s", p.Ref)
+				}
+				p = resolved
+				ptr = pointer("components", "parameters", name, "schema")
+			}
+			params[p.In+" "+p.Name] = p
+			ptrs[p.In+" "+p.Name] = ptr
+		}
+		return nil
+	}
+	if err := add(shared, "paths", path); err != nil {
+		return nil, err
+	}
+	if err := add(op.Parameters, "paths", path, method); err != nil {
+		return nil, err
+	}
+
+	for key, p := range params {
+		if p.In != "query" {
+			continue
+		}
+		schema, err := compile(compiler, ptrs[key])
+		if err != nil {
+			return nil, err
+		}
+		typ, _ := p.Schema["type"].(string)
+		checked.query = append(checked.query, queryParam{name: p.Name, typ: typ, required: p.Required, schema: schema})
+	}
+	sort.Slice(checked.query, func(i, j int) bool { return checked.query[i].name < checked.query[j].name })
+
+	if op.RequestBody != nil {
+		checked.bodyRequired = op.RequestBody.Required
+		for mediaType := range op.RequestBody.Content {
+			checked.mediaType = append(checked.mediaType, mediaType)
+			if !isJSON(mediaType) {
+				continue
+			}
+			schema, err := compile(compiler, pointer("paths", path, method, "requestBody", "content", mediaType, "schema"))
+			if err != nil {
+				return nil, err
+			}
+			checked.bodies[mediaType] = schema
+		}
+		sort.Strings(checked.mediaType)
+	}
+
+	return checked, nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// Middleware rejects requests whose query parameters or JSON body do not
+// match the operation of their route with a 400 problem listing each
+// mismatch. The field of a mismatch is "query.<name>" or "body" followed
+// by a JSON pointer. It must run after the
+// router has matched the route; routes the document does not describe are
+// passed through.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		op, ok := v.operations[r.Method+" "+template]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		problems := op.checkQuery(r.URL.Query())
+
+		bodyProblems, err := op.checkBody(r)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+		problems = append(problems, bodyProblems...)
+
+		if len(problems) > 0 {
+			problem.WriteValidation(w, r, http.StatusBadRequest, "request does not match the API specification", problems)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (op *checkedOperation) checkQuery(query url.Values) []validation.Violation {
+	var problems []validation.Violation
+	for _, p := range op.query {
+		raw, ok := query[p.name]
+		if !ok || len(raw) == 0 || raw[0] == "" {
+			if p.required {
+				problems = append(problems, validation.Violation{Field: "query." + p.name, Code: validation.CodeRequired, Message: "is required"})
+			}
+			continue
+		}
+
+		value, err := coerce(raw[0], p.typ)
+		if err != nil {
+			problems = append(problems, validation.Violation{Field: "query." + p.name, Code: validation.CodeInvalid, Message: err.Error()})
+			continue
+		}
+
+		if err := p.schema.Validate(value); err != nil {
+			for _, v := range violations(err) {
+				problems = append(problems, validation.Violation{Field: "query." + p.name, Code: v.code, Message: v.text})
+			}
+		}
+	}
+	return problems
+}
+
+// coerce turns a query string value into the JSON type of its schema.
+func coerce(raw string, typ string) (interface{}, error) {
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return json.Number(strconv.FormatInt(n, 10)), nil
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, errors.New("must be a number")
+		}
+		return json.Number(strconv.FormatFloat(n, 'g', -1, 64)), nil
+	case "boolean":
+		switch raw {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, errors.New("must be true or false")
+	default:
+		return raw, nil
+	}
+}
+
+// checkBody validates a JSON body against the schema of its media type.
+// A missing Content-Type is taken to be application/json. The body is
+// put back for the handler.
+func (op *checkedOperation) checkBody(r *http.Request) ([]validation.Violation, error) {
+	if len(op.mediaType) == 0 || r.Body == nil {
+		return nil, nil
+	}
+
+	mediaType := "application/json"
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, nil
+		}
+		mediaType = parsed
+	}
+
+	schema, ok := op.bodies[mediaType]
+	if !ok || r.ContentLength > maxBodyBytes {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if len(body) > maxBodyBytes {
+		return nil, nil
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.bodyRequired {
+			return []validation.Violation{{Field: "body", Code: validation.CodeRequired, Message: "is required"}}, nil
+		}
+		return nil, nil
+	}
+
+	value, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return []validation.Violation{{Field: "body", Code: validation.CodeInvalid, Message: "must be valid JSON"}}, nil
+	}
+
+	if err := schema.Validate(value); err != nil {
+		var problems []validation.Violation
+		for _, v := range violations(err) {
+			problems = append(problems, validation.Violation{Field: "body" + v.location, Code: v.code, Message: v.text})
+		}
+		return problems, nil
+	}
+	return nil, nil
+}
+
+var printer = message.NewPrinter(language.English)
+
+type violation struct {
+	location string
+	code     string
+	text     string
+}
+
+// violations flattens a validation error into the messages of its leaves,
+// each with the JSON pointer of the value it is about and the schema
+// keyword it breaks as its code.
+func violations(err error) []violation {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []violation{{code: validation.CodeInvalid, text: err.Error()}}
+	}
+
+	var out []violation
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			location := ""
+			if len(e.InstanceLocation) > 0 {
+				location = pointer(e.InstanceLocation...)
+			}
+			code := validation.CodeInvalid
+			if keywords := e.ErrorKind.KeywordPath(); len(keywords) > 0 {
+				code = keywords[len(keywords)-1]
+			}
+			out = append(out, violation{location: location, code: code, text: e.ErrorKind.LocalizedString(printer)})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+	return out
+}
@@ -0,0 +1,1100 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: carmanagement/v1/car.proto
+
+package carmanagementv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Car struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Year      string                 `protobuf:"bytes,3,opt,name=year,proto3" json:"year,omitempty"`
+	Brand     string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	FuelType  string                 `protobuf:"bytes,5,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	Engine    *Engine                `protobuf:"bytes,6,opt,name=engine,proto3" json:"engine,omitempty"`
+	Price     float32                `protobuf:"fixed32,7,opt,name=price,proto3" json:"price,omitempty"`
+	Version   int64                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Set only for a deleted car.
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	Vin           string                 `protobuf:"bytes,12,opt,name=vin,proto3" json:"vin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Car) Reset() {
+	*x = Car{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Car) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Car) ProtoMessage() {}
+
+func (x *Car) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Car.ProtoReflect.Descriptor instead.
+func (*Car) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Car) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Car) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Car) GetYear() string {
+	if x != nil {
+		return x.Year
+	}
+	return ""
+}
+
+func (x *Car) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Car) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *Car) GetEngine() *Engine {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+func (x *Car) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Car) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Car) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Car) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Car) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Car) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+type CarInput struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Year     string                 `protobuf:"bytes,2,opt,name=year,proto3" json:"year,omitempty"`
+	Brand    string                 `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	FuelType string                 `protobuf:"bytes,4,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	EngineId string                 `protobuf:"bytes,5,opt,name=engine_id,json=engineId,proto3" json:"engine_id,omitempty"`
+	Price    float32                `protobuf:"fixed32,6,opt,name=price,proto3" json:"price,omitempty"`
+	// An empty brand or year is filled in from the VIN.
+	Vin           string `protobuf:"bytes,7,opt,name=vin,proto3" json:"vin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CarInput) Reset() {
+	*x = CarInput{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CarInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarInput) ProtoMessage() {}
+
+func (x *CarInput) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarInput.ProtoReflect.Descriptor instead.
+func (*CarInput) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CarInput) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CarInput) GetYear() string {
+	if x != nil {
+		return x.Year
+	}
+	return ""
+}
+
+func (x *CarInput) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *CarInput) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *CarInput) GetEngineId() string {
+	if x != nil {
+		return x.EngineId
+	}
+	return ""
+}
+
+func (x *CarInput) GetPrice() float32 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CarInput) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+type GetCarRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetCarRequest) Reset() {
+	*x = GetCarRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarRequest) ProtoMessage() {}
+
+func (x *GetCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarRequest.ProtoReflect.Descriptor instead.
+func (*GetCarRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetCarRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type GetCarByVinRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Vin            string                 `protobuf:"bytes,1,opt,name=vin,proto3" json:"vin,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetCarByVinRequest) Reset() {
+	*x = GetCarByVinRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCarByVinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarByVinRequest) ProtoMessage() {}
+
+func (x *GetCarByVinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarByVinRequest.ProtoReflect.Descriptor instead.
+func (*GetCarByVinRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCarByVinRequest) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *GetCarByVinRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// ListCarsRequest takes the query parameters of GET /cars. Zero values
+// leave a filter out.
+type ListCarsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Brand           string                 `protobuf:"bytes,1,opt,name=brand,proto3" json:"brand,omitempty"`
+	FuelType        string                 `protobuf:"bytes,2,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	MinYear         int32                  `protobuf:"varint,3,opt,name=min_year,json=minYear,proto3" json:"min_year,omitempty"`
+	MaxYear         int32                  `protobuf:"varint,4,opt,name=max_year,json=maxYear,proto3" json:"max_year,omitempty"`
+	MinPrice        float64                `protobuf:"fixed64,5,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
+	MaxPrice        float64                `protobuf:"fixed64,6,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
+	MinDisplacement int32                  `protobuf:"varint,7,opt,name=min_displacement,json=minDisplacement,proto3" json:"min_displacement,omitempty"`
+	MaxDisplacement int32                  `protobuf:"varint,8,opt,name=max_displacement,json=maxDisplacement,proto3" json:"max_displacement,omitempty"`
+	Cylinders       int32                  `protobuf:"varint,9,opt,name=cylinders,proto3" json:"cylinders,omitempty"`
+	SortBy          string                 `protobuf:"bytes,10,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	Order           string                 `protobuf:"bytes,11,opt,name=order,proto3" json:"order,omitempty"`
+	Limit           int32                  `protobuf:"varint,12,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor          string                 `protobuf:"bytes,13,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Fill in each car's engine.
+	IncludeEngine  bool `protobuf:"varint,14,opt,name=include_engine,json=includeEngine,proto3" json:"include_engine,omitempty"`
+	IncludeDeleted bool `protobuf:"varint,15,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCarsRequest) Reset() {
+	*x = ListCarsRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsRequest) ProtoMessage() {}
+
+func (x *ListCarsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsRequest.ProtoReflect.Descriptor instead.
+func (*ListCarsRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListCarsRequest) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetMinYear() int32 {
+	if x != nil {
+		return x.MinYear
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMaxYear() int32 {
+	if x != nil {
+		return x.MaxYear
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMinPrice() float64 {
+	if x != nil {
+		return x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMaxPrice() float64 {
+	if x != nil {
+		return x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMinDisplacement() int32 {
+	if x != nil {
+		return x.MinDisplacement
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetMaxDisplacement() int32 {
+	if x != nil {
+		return x.MaxDisplacement
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetCylinders() int32 {
+	if x != nil {
+		return x.Cylinders
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListCarsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListCarsRequest) GetIncludeEngine() bool {
+	if x != nil {
+		return x.IncludeEngine
+	}
+	return false
+}
+
+func (x *ListCarsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type ListCarsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cars          []*Car                 `protobuf:"bytes,1,rep,name=cars,proto3" json:"cars,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCarsResponse) Reset() {
+	*x = ListCarsResponse{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCarsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarsResponse) ProtoMessage() {}
+
+func (x *ListCarsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarsResponse.ProtoReflect.Descriptor instead.
+func (*ListCarsResponse) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListCarsResponse) GetCars() []*Car {
+	if x != nil {
+		return x.Cars
+	}
+	return nil
+}
+
+func (x *ListCarsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListCarsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListCarsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CreateCarRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Car           *CarInput              `protobuf:"bytes,1,opt,name=car,proto3" json:"car,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCarRequest) Reset() {
+	*x = CreateCarRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarRequest) ProtoMessage() {}
+
+func (x *CreateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+// UpdateCarRequest replaces a car. expected_version works like If-Match:
+// the update fails with FAILED_PRECONDITION if the car has changed since,
+// or if expected_version is missing. Send "if-match: *" metadata instead
+// to update unconditionally.
+type UpdateCarRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Car             *CarInput              `protobuf:"bytes,2,opt,name=car,proto3" json:"car,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateCarRequest) Reset() {
+	*x = UpdateCarRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarRequest) ProtoMessage() {}
+
+func (x *UpdateCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCarRequest) GetCar() *CarInput {
+	if x != nil {
+		return x.Car
+	}
+	return nil
+}
+
+func (x *UpdateCarRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// DeleteCarRequest soft-deletes a car. expected_version is required as in
+// UpdateCarRequest.
+type DeleteCarRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteCarRequest) Reset() {
+	*x = DeleteCarRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCarRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarRequest) ProtoMessage() {}
+
+func (x *DeleteCarRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteCarRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteCarRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DecodeVinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vin           string                 `protobuf:"bytes,1,opt,name=vin,proto3" json:"vin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecodeVinRequest) Reset() {
+	*x = DecodeVinRequest{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecodeVinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeVinRequest) ProtoMessage() {}
+
+func (x *DecodeVinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeVinRequest.ProtoReflect.Descriptor instead.
+func (*DecodeVinRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DecodeVinRequest) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+// VinInfo is what a VIN says about a vehicle, as returned by
+// POST /vin/decode.
+type VinInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vin           string                 `protobuf:"bytes,1,opt,name=vin,proto3" json:"vin,omitempty"`
+	Wmi           string                 `protobuf:"bytes,2,opt,name=wmi,proto3" json:"wmi,omitempty"`
+	Manufacturer  string                 `protobuf:"bytes,3,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	Brand         string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`
+	Country       string                 `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`
+	Vds           string                 `protobuf:"bytes,7,opt,name=vds,proto3" json:"vds,omitempty"`
+	ModelYear     int32                  `protobuf:"varint,8,opt,name=model_year,json=modelYear,proto3" json:"model_year,omitempty"`
+	ModelYears    []int32                `protobuf:"varint,9,rep,packed,name=model_years,json=modelYears,proto3" json:"model_years,omitempty"`
+	PlantCode     string                 `protobuf:"bytes,10,opt,name=plant_code,json=plantCode,proto3" json:"plant_code,omitempty"`
+	Plant         string                 `protobuf:"bytes,11,opt,name=plant,proto3" json:"plant,omitempty"`
+	Serial        string                 `protobuf:"bytes,12,opt,name=serial,proto3" json:"serial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VinInfo) Reset() {
+	*x = VinInfo{}
+	mi := &file_carmanagement_v1_car_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VinInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VinInfo) ProtoMessage() {}
+
+func (x *VinInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_car_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VinInfo.ProtoReflect.Descriptor instead.
+func (*VinInfo) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_car_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VinInfo) GetVin() string {
+	if x != nil {
+		return x.Vin
+	}
+	return ""
+}
+
+func (x *VinInfo) GetWmi() string {
+	if x != nil {
+		return x.Wmi
+	}
+	return ""
+}
+
+func (x *VinInfo) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *VinInfo) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *VinInfo) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *VinInfo) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *VinInfo) GetVds() string {
+	if x != nil {
+		return x.Vds
+	}
+	return ""
+}
+
+func (x *VinInfo) GetModelYear() int32 {
+	if x != nil {
+		return x.ModelYear
+	}
+	return 0
+}
+
+func (x *VinInfo) GetModelYears() []int32 {
+	if x != nil {
+		return x.ModelYears
+	}
+	return nil
+}
+
+func (x *VinInfo) GetPlantCode() string {
+	if x != nil {
+		return x.PlantCode
+	}
+	return ""
+}
+
+func (x *VinInfo) GetPlant() string {
+	if x != nil {
+		return x.Plant
+	}
+	return ""
+}
+
+func (x *VinInfo) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+var File_carmanagement_v1_car_proto protoreflect.FileDescriptor
+
+const file_carmanagement_v1_car_proto_rawDesc = "" +
+	"\n" +
+	"\x1acarmanagement/v1/car.proto\x12\x10carmanagement.v1\x1a\x1dcarmanagement/v1/engine.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x95\x03\n" +
+	"\x03Car\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\tR\x04year\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12\x1b\n" +
+	"\tfuel_type\x18\x05 \x01(\tR\bfuelType\x120\n" +
+	"\x06engine\x18\x06 \x01(\v2\x18.carmanagement.v1.EngineR\x06engine\x12\x14\n" +
+	"\x05price\x18\a \x01(\x02R\x05price\x12\x18\n" +
+	"\aversion\x18\b \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x10\n" +
+	"\x03vin\x18\f \x01(\tR\x03vin\"\xaa\x01\n" +
+	"\bCarInput\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04year\x18\x02 \x01(\tR\x04year\x12\x14\n" +
+	"\x05brand\x18\x03 \x01(\tR\x05brand\x12\x1b\n" +
+	"\tfuel_type\x18\x04 \x01(\tR\bfuelType\x12\x1b\n" +
+	"\tengine_id\x18\x05 \x01(\tR\bengineId\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x02R\x05price\x12\x10\n" +
+	"\x03vin\x18\a \x01(\tR\x03vin\"H\n" +
+	"\rGetCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"O\n" +
+	"\x12GetCarByVinRequest\x12\x10\n" +
+	"\x03vin\x18\x01 \x01(\tR\x03vin\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xd5\x03\n" +
+	"\x0fListCarsRequest\x12\x14\n" +
+	"\x05brand\x18\x01 \x01(\tR\x05brand\x12\x1b\n" +
+	"\tfuel_type\x18\x02 \x01(\tR\bfuelType\x12\x19\n" +
+	"\bmin_year\x18\x03 \x01(\x05R\aminYear\x12\x19\n" +
+	"\bmax_year\x18\x04 \x01(\x05R\amaxYear\x12\x1b\n" +
+	"\tmin_price\x18\x05 \x01(\x01R\bminPrice\x12\x1b\n" +
+	"\tmax_price\x18\x06 \x01(\x01R\bmaxPrice\x12)\n" +
+	"\x10min_displacement\x18\a \x01(\x05R\x0fminDisplacement\x12)\n" +
+	"\x10max_displacement\x18\b \x01(\x05R\x0fmaxDisplacement\x12\x1c\n" +
+	"\tcylinders\x18\t \x01(\x05R\tcylinders\x12\x17\n" +
+	"\asort_by\x18\n" +
+	" \x01(\tR\x06sortBy\x12\x14\n" +
+	"\x05order\x18\v \x01(\tR\x05order\x12\x14\n" +
+	"\x05limit\x18\f \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\r \x01(\tR\x06cursor\x12%\n" +
+	"\x0einclude_engine\x18\x0e \x01(\bR\rincludeEngine\x12'\n" +
+	"\x0finclude_deleted\x18\x0f \x01(\bR\x0eincludeDeleted\"\x95\x01\n" +
+	"\x10ListCarsResponse\x12)\n" +
+	"\x04cars\x18\x01 \x03(\v2\x15.carmanagement.v1.CarR\x04cars\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x05R\n" +
+	"totalCount\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"@\n" +
+	"\x10CreateCarRequest\x12,\n" +
+	"\x03car\x18\x01 \x01(\v2\x1a.carmanagement.v1.CarInputR\x03car\"{\n" +
+	"\x10UpdateCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12,\n" +
+	"\x03car\x18\x02 \x01(\v2\x1a.carmanagement.v1.CarInputR\x03car\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"M\n" +
+	"\x10DeleteCarRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"$\n" +
+	"\x10DecodeVinRequest\x12\x10\n" +
+	"\x03vin\x18\x01 \x01(\tR\x03vin\"\xb8\x02\n" +
+	"\aVinInfo\x12\x10\n" +
+	"\x03vin\x18\x01 \x01(\tR\x03vin\x12\x10\n" +
+	"\x03wmi\x18\x02 \x01(\tR\x03wmi\x12\"\n" +
+	"\fmanufacturer\x18\x03 \x01(\tR\fmanufacturer\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12\x18\n" +
+	"\acountry\x18\x05 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x06 \x01(\tR\x06region\x12\x10\n" +
+	"\x03vds\x18\a \x01(\tR\x03vds\x12\x1d\n" +
+	"\n" +
+	"model_year\x18\b \x01(\x05R\tmodelYear\x12\x1f\n" +
+	"\vmodel_years\x18\t \x03(\x05R\n" +
+	"modelYears\x12\x1d\n" +
+	"\n" +
+	"plant_code\x18\n" +
+	" \x01(\tR\tplantCode\x12\x14\n" +
+	"\x05plant\x18\v \x01(\tR\x05plant\x12\x16\n" +
+	"\x06serial\x18\f \x01(\tR\x06serial2\x91\x04\n" +
+	"\n" +
+	"CarService\x12@\n" +
+	"\x06GetCar\x12\x1f.carmanagement.v1.GetCarRequest\x1a\x15.carmanagement.v1.Car\x12J\n" +
+	"\vGetCarByVin\x12$.carmanagement.v1.GetCarByVinRequest\x1a\x15.carmanagement.v1.Car\x12Q\n" +
+	"\bListCars\x12!.carmanagement.v1.ListCarsRequest\x1a\".carmanagement.v1.ListCarsResponse\x12F\n" +
+	"\tCreateCar\x12\".carmanagement.v1.CreateCarRequest\x1a\x15.carmanagement.v1.Car\x12F\n" +
+	"\tUpdateCar\x12\".carmanagement.v1.UpdateCarRequest\x1a\x15.carmanagement.v1.Car\x12F\n" +
+	"\tDeleteCar\x12\".carmanagement.v1.DeleteCarRequest\x1a\x15.carmanagement.v1.Car\x12J\n" +
+	"\tDecodeVin\x12\".carmanagement.v1.DecodeVinRequest\x1a\x19.carmanagement.v1.VinInfoB>Z<Car-Management-System/proto/carmanagement/v1;carmanagementv1b\x06proto3"
+
+var (
+	file_carmanagement_v1_car_proto_rawDescOnce sync.Once
+	file_carmanagement_v1_car_proto_rawDescData []byte
+)
+
+func file_carmanagement_v1_car_proto_rawDescGZIP() []byte {
+	file_carmanagement_v1_car_proto_rawDescOnce.Do(func() {
+		file_carmanagement_v1_car_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_carmanagement_v1_car_proto_rawDesc), len(file_carmanagement_v1_car_proto_rawDesc)))
+	})
+	return file_carmanagement_v1_car_proto_rawDescData
+}
+
+var file_carmanagement_v1_car_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_carmanagement_v1_car_proto_goTypes = []any{
+	(*Car)(nil),                   // 0: carmanagement.v1.Car
+	(*CarInput)(nil),              // 1: carmanagement.v1.CarInput
+	(*GetCarRequest)(nil),         // 2: carmanagement.v1.GetCarRequest
+	(*GetCarByVinRequest)(nil),    // 3: carmanagement.v1.GetCarByVinRequest
+	(*ListCarsRequest)(nil),       // 4: carmanagement.v1.ListCarsRequest
+	(*ListCarsResponse)(nil),      // 5: carmanagement.v1.ListCarsResponse
+	(*CreateCarRequest)(nil),      // 6: carmanagement.v1.CreateCarRequest
+	(*UpdateCarRequest)(nil),      // 7: carmanagement.v1.UpdateCarRequest
+	(*DeleteCarRequest)(nil),      // 8: carmanagement.v1.DeleteCarRequest
+	(*DecodeVinRequest)(nil),      // 9: carmanagement.v1.DecodeVinRequest
+	(*VinInfo)(nil),               // 10: carmanagement.v1.VinInfo
+	(*Engine)(nil),                // 11: carmanagement.v1.Engine
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
+}
+var file_carmanagement_v1_car_proto_depIdxs = []int32{
+	11, // 0: carmanagement.v1.Car.engine:type_name -> carmanagement.v1.Engine
+	12, // 1: carmanagement.v1.Car.created_at:type_name -> google.protobuf.Timestamp
+	12, // 2: carmanagement.v1.Car.updated_at:type_name -> google.protobuf.Timestamp
+	12, // 3: carmanagement.v1.Car.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: carmanagement.v1.ListCarsResponse.cars:type_name -> carmanagement.v1.Car
+	1,  // 5: carmanagement.v1.CreateCarRequest.car:type_name -> carmanagement.v1.CarInput
+	1,  // 6: carmanagement.v1.UpdateCarRequest.car:type_name -> carmanagement.v1.CarInput
+	2,  // 7: carmanagement.v1.CarService.GetCar:input_type -> carmanagement.v1.GetCarRequest
+	3,  // 8: carmanagement.v1.CarService.GetCarByVin:input_type -> carmanagement.v1.GetCarByVinRequest
+	4,  // 9: carmanagement.v1.CarService.ListCars:input_type -> carmanagement.v1.ListCarsRequest
+	6,  // 10: carmanagement.v1.CarService.CreateCar:input_type -> carmanagement.v1.CreateCarRequest
+	7,  // 11: carmanagement.v1.CarService.UpdateCar:input_type -> carmanagement.v1.UpdateCarRequest
+	8,  // 12: carmanagement.v1.CarService.DeleteCar:input_type -> carmanagement.v1.DeleteCarRequest
+	9,  // 13: carmanagement.v1.CarService.DecodeVin:input_type -> carmanagement.v1.DecodeVinRequest
+	0,  // 14: carmanagement.v1.CarService.GetCar:output_type -> carmanagement.v1.Car
+	0,  // 15: carmanagement.v1.CarService.GetCarByVin:output_type -> carmanagement.v1.Car
+	5,  // 16: carmanagement.v1.CarService.ListCars:output_type -> carmanagement.v1.ListCarsResponse
+	0,  // 17: carmanagement.v1.CarService.CreateCar:output_type -> carmanagement.v1.Car
+	0,  // 18: carmanagement.v1.CarService.UpdateCar:output_type -> carmanagement.v1.Car
+	0,  // 19: carmanagement.v1.CarService.DeleteCar:output_type -> carmanagement.v1.Car
+	10, // 20: carmanagement.v1.CarService.DecodeVin:output_type -> carmanagement.v1.VinInfo
+	14, // [14:21] is the sub-list for method output_type
+	7,  // [7:14] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_carmanagement_v1_car_proto_init() }
+func file_carmanagement_v1_car_proto_init() {
+	if File_carmanagement_v1_car_proto != nil {
+		return
+	}
+	file_carmanagement_v1_engine_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_carmanagement_v1_car_proto_rawDesc), len(file_carmanagement_v1_car_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_carmanagement_v1_car_proto_goTypes,
+		DependencyIndexes: file_carmanagement_v1_car_proto_depIdxs,
+		MessageInfos:      file_carmanagement_v1_car_proto_msgTypes,
+	}.Build()
+	File_carmanagement_v1_car_proto = out.File
+	file_carmanagement_v1_car_proto_goTypes = nil
+	file_carmanagement_v1_car_proto_depIdxs = nil
+}
@@ -0,0 +1,853 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: carmanagement/v1/engine.proto
+
+package carmanagementv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Engine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Displacement  int32                  `protobuf:"varint,2,opt,name=displacement,proto3" json:"displacement,omitempty"`
+	NoOfCylinders int32                  `protobuf:"varint,3,opt,name=no_of_cylinders,json=noOfCylinders,proto3" json:"no_of_cylinders,omitempty"`
+	CarRange      int32                  `protobuf:"varint,4,opt,name=car_range,json=carRange,proto3" json:"car_range,omitempty"`
+	Version       int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// Set only for a deleted engine.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// One of ICE, BEV, PHEV or HEV. The electric fields are zero for ICE.
+	Powertrain    string  `protobuf:"bytes,7,opt,name=powertrain,proto3" json:"powertrain,omitempty"`
+	BatteryKwh    float32 `protobuf:"fixed32,8,opt,name=battery_kwh,json=batteryKwh,proto3" json:"battery_kwh,omitempty"`
+	ChargePowerKw float32 `protobuf:"fixed32,9,opt,name=charge_power_kw,json=chargePowerKw,proto3" json:"charge_power_kw,omitempty"`
+	MotorPowerKw  int32   `protobuf:"varint,10,opt,name=motor_power_kw,json=motorPowerKw,proto3" json:"motor_power_kw,omitempty"`
+	MotorTorqueNm int32   `protobuf:"varint,11,opt,name=motor_torque_nm,json=motorTorqueNm,proto3" json:"motor_torque_nm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Engine) Reset() {
+	*x = Engine{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Engine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Engine) ProtoMessage() {}
+
+func (x *Engine) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Engine.ProtoReflect.Descriptor instead.
+func (*Engine) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Engine) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Engine) GetDisplacement() int32 {
+	if x != nil {
+		return x.Displacement
+	}
+	return 0
+}
+
+func (x *Engine) GetNoOfCylinders() int32 {
+	if x != nil {
+		return x.NoOfCylinders
+	}
+	return 0
+}
+
+func (x *Engine) GetCarRange() int32 {
+	if x != nil {
+		return x.CarRange
+	}
+	return 0
+}
+
+func (x *Engine) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Engine) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Engine) GetPowertrain() string {
+	if x != nil {
+		return x.Powertrain
+	}
+	return ""
+}
+
+func (x *Engine) GetBatteryKwh() float32 {
+	if x != nil {
+		return x.BatteryKwh
+	}
+	return 0
+}
+
+func (x *Engine) GetChargePowerKw() float32 {
+	if x != nil {
+		return x.ChargePowerKw
+	}
+	return 0
+}
+
+func (x *Engine) GetMotorPowerKw() int32 {
+	if x != nil {
+		return x.MotorPowerKw
+	}
+	return 0
+}
+
+func (x *Engine) GetMotorTorqueNm() int32 {
+	if x != nil {
+		return x.MotorTorqueNm
+	}
+	return 0
+}
+
+// EngineInput is validated per powertrain like the body of POST /engine.
+// An empty powertrain means ICE.
+type EngineInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Displacement  int32                  `protobuf:"varint,1,opt,name=displacement,proto3" json:"displacement,omitempty"`
+	NoOfCylinders int32                  `protobuf:"varint,2,opt,name=no_of_cylinders,json=noOfCylinders,proto3" json:"no_of_cylinders,omitempty"`
+	CarRange      int32                  `protobuf:"varint,3,opt,name=car_range,json=carRange,proto3" json:"car_range,omitempty"`
+	Powertrain    string                 `protobuf:"bytes,4,opt,name=powertrain,proto3" json:"powertrain,omitempty"`
+	BatteryKwh    float32                `protobuf:"fixed32,5,opt,name=battery_kwh,json=batteryKwh,proto3" json:"battery_kwh,omitempty"`
+	ChargePowerKw float32                `protobuf:"fixed32,6,opt,name=charge_power_kw,json=chargePowerKw,proto3" json:"charge_power_kw,omitempty"`
+	MotorPowerKw  int32                  `protobuf:"varint,7,opt,name=motor_power_kw,json=motorPowerKw,proto3" json:"motor_power_kw,omitempty"`
+	MotorTorqueNm int32                  `protobuf:"varint,8,opt,name=motor_torque_nm,json=motorTorqueNm,proto3" json:"motor_torque_nm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EngineInput) Reset() {
+	*x = EngineInput{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EngineInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EngineInput) ProtoMessage() {}
+
+func (x *EngineInput) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EngineInput.ProtoReflect.Descriptor instead.
+func (*EngineInput) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EngineInput) GetDisplacement() int32 {
+	if x != nil {
+		return x.Displacement
+	}
+	return 0
+}
+
+func (x *EngineInput) GetNoOfCylinders() int32 {
+	if x != nil {
+		return x.NoOfCylinders
+	}
+	return 0
+}
+
+func (x *EngineInput) GetCarRange() int32 {
+	if x != nil {
+		return x.CarRange
+	}
+	return 0
+}
+
+func (x *EngineInput) GetPowertrain() string {
+	if x != nil {
+		return x.Powertrain
+	}
+	return ""
+}
+
+func (x *EngineInput) GetBatteryKwh() float32 {
+	if x != nil {
+		return x.BatteryKwh
+	}
+	return 0
+}
+
+func (x *EngineInput) GetChargePowerKw() float32 {
+	if x != nil {
+		return x.ChargePowerKw
+	}
+	return 0
+}
+
+func (x *EngineInput) GetMotorPowerKw() int32 {
+	if x != nil {
+		return x.MotorPowerKw
+	}
+	return 0
+}
+
+func (x *EngineInput) GetMotorTorqueNm() int32 {
+	if x != nil {
+		return x.MotorTorqueNm
+	}
+	return 0
+}
+
+type GetEngineRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetEngineRequest) Reset() {
+	*x = GetEngineRequest{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEngineRequest) ProtoMessage() {}
+
+func (x *GetEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEngineRequest.ProtoReflect.Descriptor instead.
+func (*GetEngineRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetEngineRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// ListEnginesRequest takes the query parameters of GET /engine. Zero
+// values leave a filter out.
+type ListEnginesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MinDisplacement int32                  `protobuf:"varint,1,opt,name=min_displacement,json=minDisplacement,proto3" json:"min_displacement,omitempty"`
+	MaxDisplacement int32                  `protobuf:"varint,2,opt,name=max_displacement,json=maxDisplacement,proto3" json:"max_displacement,omitempty"`
+	Cylinders       int32                  `protobuf:"varint,3,opt,name=cylinders,proto3" json:"cylinders,omitempty"`
+	MinRange        int32                  `protobuf:"varint,4,opt,name=min_range,json=minRange,proto3" json:"min_range,omitempty"`
+	MaxRange        int32                  `protobuf:"varint,5,opt,name=max_range,json=maxRange,proto3" json:"max_range,omitempty"`
+	WithCars        bool                   `protobuf:"varint,6,opt,name=with_cars,json=withCars,proto3" json:"with_cars,omitempty"`
+	Limit           int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor          string                 `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	IncludeDeleted  bool                   `protobuf:"varint,9,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListEnginesRequest) Reset() {
+	*x = ListEnginesRequest{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnginesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnginesRequest) ProtoMessage() {}
+
+func (x *ListEnginesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnginesRequest.ProtoReflect.Descriptor instead.
+func (*ListEnginesRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListEnginesRequest) GetMinDisplacement() int32 {
+	if x != nil {
+		return x.MinDisplacement
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetMaxDisplacement() int32 {
+	if x != nil {
+		return x.MaxDisplacement
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetCylinders() int32 {
+	if x != nil {
+		return x.Cylinders
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetMinRange() int32 {
+	if x != nil {
+		return x.MinRange
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetMaxRange() int32 {
+	if x != nil {
+		return x.MaxRange
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetWithCars() bool {
+	if x != nil {
+		return x.WithCars
+	}
+	return false
+}
+
+func (x *ListEnginesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListEnginesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListEnginesRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type EngineListItem struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Engine *Engine                `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	// Set only when with_cars was requested.
+	CarCount      *int32   `protobuf:"varint,2,opt,name=car_count,json=carCount,proto3,oneof" json:"car_count,omitempty"`
+	CarIds        []string `protobuf:"bytes,3,rep,name=car_ids,json=carIds,proto3" json:"car_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EngineListItem) Reset() {
+	*x = EngineListItem{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EngineListItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EngineListItem) ProtoMessage() {}
+
+func (x *EngineListItem) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EngineListItem.ProtoReflect.Descriptor instead.
+func (*EngineListItem) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EngineListItem) GetEngine() *Engine {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+func (x *EngineListItem) GetCarCount() int32 {
+	if x != nil && x.CarCount != nil {
+		return *x.CarCount
+	}
+	return 0
+}
+
+func (x *EngineListItem) GetCarIds() []string {
+	if x != nil {
+		return x.CarIds
+	}
+	return nil
+}
+
+type ListEnginesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engines       []*EngineListItem      `protobuf:"bytes,1,rep,name=engines,proto3" json:"engines,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEnginesResponse) Reset() {
+	*x = ListEnginesResponse{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEnginesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEnginesResponse) ProtoMessage() {}
+
+func (x *ListEnginesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEnginesResponse.ProtoReflect.Descriptor instead.
+func (*ListEnginesResponse) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListEnginesResponse) GetEngines() []*EngineListItem {
+	if x != nil {
+		return x.Engines
+	}
+	return nil
+}
+
+func (x *ListEnginesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *ListEnginesResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListEnginesResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type CreateEngineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Engine        *EngineInput           `protobuf:"bytes,1,opt,name=engine,proto3" json:"engine,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEngineRequest) Reset() {
+	*x = CreateEngineRequest{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEngineRequest) ProtoMessage() {}
+
+func (x *CreateEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEngineRequest.ProtoReflect.Descriptor instead.
+func (*CreateEngineRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateEngineRequest) GetEngine() *EngineInput {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+// UpdateEngineRequest replaces an engine. expected_version works like
+// If-Match: the update fails with FAILED_PRECONDITION if the engine has
+// changed since, or if expected_version is missing. Send "if-match: *"
+// metadata instead to update unconditionally.
+type UpdateEngineRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Engine          *EngineInput           `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateEngineRequest) Reset() {
+	*x = UpdateEngineRequest{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEngineRequest) ProtoMessage() {}
+
+func (x *UpdateEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEngineRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEngineRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateEngineRequest) GetEngine() *EngineInput {
+	if x != nil {
+		return x.Engine
+	}
+	return nil
+}
+
+func (x *UpdateEngineRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// DeleteEngineRequest soft-deletes an engine. expected_version is
+// required as in UpdateEngineRequest.
+type DeleteEngineRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteEngineRequest) Reset() {
+	*x = DeleteEngineRequest{}
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEngineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEngineRequest) ProtoMessage() {}
+
+func (x *DeleteEngineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carmanagement_v1_engine_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEngineRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEngineRequest) Descriptor() ([]byte, []int) {
+	return file_carmanagement_v1_engine_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteEngineRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteEngineRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+var File_carmanagement_v1_engine_proto protoreflect.FileDescriptor
+
+const file_carmanagement_v1_engine_proto_rawDesc = "" +
+	"\n" +
+	"\x1dcarmanagement/v1/engine.proto\x12\x10carmanagement.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\x03\n" +
+	"\x06Engine\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\"\n" +
+	"\fdisplacement\x18\x02 \x01(\x05R\fdisplacement\x12&\n" +
+	"\x0fno_of_cylinders\x18\x03 \x01(\x05R\rnoOfCylinders\x12\x1b\n" +
+	"\tcar_range\x18\x04 \x01(\x05R\bcarRange\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12\x1e\n" +
+	"\n" +
+	"powertrain\x18\a \x01(\tR\n" +
+	"powertrain\x12\x1f\n" +
+	"\vbattery_kwh\x18\b \x01(\x02R\n" +
+	"batteryKwh\x12&\n" +
+	"\x0fcharge_power_kw\x18\t \x01(\x02R\rchargePowerKw\x12$\n" +
+	"\x0emotor_power_kw\x18\n" +
+	" \x01(\x05R\fmotorPowerKw\x12&\n" +
+	"\x0fmotor_torque_nm\x18\v \x01(\x05R\rmotorTorqueNm\"\xad\x02\n" +
+	"\vEngineInput\x12\"\n" +
+	"\fdisplacement\x18\x01 \x01(\x05R\fdisplacement\x12&\n" +
+	"\x0fno_of_cylinders\x18\x02 \x01(\x05R\rnoOfCylinders\x12\x1b\n" +
+	"\tcar_range\x18\x03 \x01(\x05R\bcarRange\x12\x1e\n" +
+	"\n" +
+	"powertrain\x18\x04 \x01(\tR\n" +
+	"powertrain\x12\x1f\n" +
+	"\vbattery_kwh\x18\x05 \x01(\x02R\n" +
+	"batteryKwh\x12&\n" +
+	"\x0fcharge_power_kw\x18\x06 \x01(\x02R\rchargePowerKw\x12$\n" +
+	"\x0emotor_power_kw\x18\a \x01(\x05R\fmotorPowerKw\x12&\n" +
+	"\x0fmotor_torque_nm\x18\b \x01(\x05R\rmotorTorqueNm\"K\n" +
+	"\x10GetEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xb6\x02\n" +
+	"\x12ListEnginesRequest\x12)\n" +
+	"\x10min_displacement\x18\x01 \x01(\x05R\x0fminDisplacement\x12)\n" +
+	"\x10max_displacement\x18\x02 \x01(\x05R\x0fmaxDisplacement\x12\x1c\n" +
+	"\tcylinders\x18\x03 \x01(\x05R\tcylinders\x12\x1b\n" +
+	"\tmin_range\x18\x04 \x01(\x05R\bminRange\x12\x1b\n" +
+	"\tmax_range\x18\x05 \x01(\x05R\bmaxRange\x12\x1b\n" +
+	"\twith_cars\x18\x06 \x01(\bR\bwithCars\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\b \x01(\tR\x06cursor\x12'\n" +
+	"\x0finclude_deleted\x18\t \x01(\bR\x0eincludeDeleted\"\x8b\x01\n" +
+	"\x0eEngineListItem\x120\n" +
+	"\x06engine\x18\x01 \x01(\v2\x18.carmanagement.v1.EngineR\x06engine\x12 \n" +
+	"\tcar_count\x18\x02 \x01(\x05H\x00R\bcarCount\x88\x01\x01\x12\x17\n" +
+	"\acar_ids\x18\x03 \x03(\tR\x06carIdsB\f\n" +
+	"\n" +
+	"_car_count\"\xa9\x01\n" +
+	"\x13ListEnginesResponse\x12:\n" +
+	"\aengines\x18\x01 \x03(\v2 .carmanagement.v1.EngineListItemR\aengines\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x05R\n" +
+	"totalCount\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"L\n" +
+	"\x13CreateEngineRequest\x125\n" +
+	"\x06engine\x18\x01 \x01(\v2\x1d.carmanagement.v1.EngineInputR\x06engine\"\x87\x01\n" +
+	"\x13UpdateEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x125\n" +
+	"\x06engine\x18\x02 \x01(\v2\x1d.carmanagement.v1.EngineInputR\x06engine\x12)\n" +
+	"\x10expected_version\x18\x03 \x01(\x03R\x0fexpectedVersion\"P\n" +
+	"\x13DeleteEngineRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion2\xa9\x03\n" +
+	"\rEngineService\x12I\n" +
+	"\tGetEngine\x12\".carmanagement.v1.GetEngineRequest\x1a\x18.carmanagement.v1.Engine\x12Z\n" +
+	"\vListEngines\x12$.carmanagement.v1.ListEnginesRequest\x1a%.carmanagement.v1.ListEnginesResponse\x12O\n" +
+	"\fCreateEngine\x12%.carmanagement.v1.CreateEngineRequest\x1a\x18.carmanagement.v1.Engine\x12O\n" +
+	"\fUpdateEngine\x12%.carmanagement.v1.UpdateEngineRequest\x1a\x18.carmanagement.v1.Engine\x12O\n" +
+	"\fDeleteEngine\x12%.carmanagement.v1.DeleteEngineRequest\x1a\x18.carmanagement.v1.EngineB>Z<Car-Management-System/proto/carmanagement/v1;carmanagementv1b\x06proto3"
+
+var (
+	file_carmanagement_v1_engine_proto_rawDescOnce sync.Once
+	file_carmanagement_v1_engine_proto_rawDescData []byte
+)
+
+func file_carmanagement_v1_engine_proto_rawDescGZIP() []byte {
+	file_carmanagement_v1_engine_proto_rawDescOnce.Do(func() {
+		file_carmanagement_v1_engine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_carmanagement_v1_engine_proto_rawDesc), len(file_carmanagement_v1_engine_proto_rawDesc)))
+	})
+	return file_carmanagement_v1_engine_proto_rawDescData
+}
+
+var file_carmanagement_v1_engine_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_carmanagement_v1_engine_proto_goTypes = []any{
+	(*Engine)(nil),                // 0: carmanagement.v1.Engine
+	(*EngineInput)(nil),           // 1: carmanagement.v1.EngineInput
+	(*GetEngineRequest)(nil),      // 2: carmanagement.v1.GetEngineRequest
+	(*ListEnginesRequest)(nil),    // 3: carmanagement.v1.ListEnginesRequest
+	(*EngineListItem)(nil),        // 4: carmanagement.v1.EngineListItem
+	(*ListEnginesResponse)(nil),   // 5: carmanagement.v1.ListEnginesResponse
+	(*CreateEngineRequest)(nil),   // 6: carmanagement.v1.CreateEngineRequest
+	(*UpdateEngineRequest)(nil),   // 7: carmanagement.v1.UpdateEngineRequest
+	(*DeleteEngineRequest)(nil),   // 8: carmanagement.v1.DeleteEngineRequest
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_carmanagement_v1_engine_proto_depIdxs = []int32{
+	9,  // 0: carmanagement.v1.Engine.deleted_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: carmanagement.v1.EngineListItem.engine:type_name -> carmanagement.v1.Engine
+	4,  // 2: carmanagement.v1.ListEnginesResponse.engines:type_name -> carmanagement.v1.EngineListItem
+	1,  // 3: carmanagement.v1.CreateEngineRequest.engine:type_name -> carmanagement.v1.EngineInput
+	1,  // 4: carmanagement.v1.UpdateEngineRequest.engine:type_name -> carmanagement.v1.EngineInput
+	2,  // 5: carmanagement.v1.EngineService.GetEngine:input_type -> carmanagement.v1.GetEngineRequest
+	3,  // 6: carmanagement.v1.EngineService.ListEngines:input_type -> carmanagement.v1.ListEnginesRequest
+	6,  // 7: carmanagement.v1.EngineService.CreateEngine:input_type -> carmanagement.v1.CreateEngineRequest
+	7,  // 8: carmanagement.v1.EngineService.UpdateEngine:input_type -> carmanagement.v1.UpdateEngineRequest
+	8,  // 9: carmanagement.v1.EngineService.DeleteEngine:input_type -> carmanagement.v1.DeleteEngineRequest
+	0,  // 10: carmanagement.v1.EngineService.GetEngine:output_type -> carmanagement.v1.Engine
+	5,  // 11: carmanagement.v1.EngineService.ListEngines:output_type -> carmanagement.v1.ListEnginesResponse
+	0,  // 12: carmanagement.v1.EngineService.CreateEngine:output_type -> carmanagement.v1.Engine
+	0,  // 13: carmanagement.v1.EngineService.UpdateEngine:output_type -> carmanagement.v1.Engine
+	0,  // 14: carmanagement.v1.EngineService.DeleteEngine:output_type -> carmanagement.v1.Engine
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_carmanagement_v1_engine_proto_init() }
+func file_carmanagement_v1_engine_proto_init() {
+	if File_carmanagement_v1_engine_proto != nil {
+		return
+	}
+	file_carmanagement_v1_engine_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_carmanagement_v1_engine_proto_rawDesc), len(file_carmanagement_v1_engine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_carmanagement_v1_engine_proto_goTypes,
+		DependencyIndexes: file_carmanagement_v1_engine_proto_depIdxs,
+		MessageInfos:      file_carmanagement_v1_engine_proto_msgTypes,
+	}.Build()
+	File_carmanagement_v1_engine_proto = out.File
+	file_carmanagement_v1_engine_proto_goTypes = nil
+	file_carmanagement_v1_engine_proto_depIdxs = nil
+}
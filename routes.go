@@ -0,0 +1,119 @@
+package main
+
+import (
+	"Car-Management-System/graphqlapi"
+	"Car-Management-System/middleware"
+	"Car-Management-System/openapi"
+	"net/http"
+
+	apiKeyHandler "Car-Management-System/handler/apikey"
+	carHandler "Car-Management-System/handler/car"
+	engineHandler "Car-Management-System/handler/engine"
+	eventsHandler "Car-Management-System/handler/events"
+	jobHandler "Car-Management-System/handler/job"
+	jwksHandler "Car-Management-System/handler/jwks"
+	loginHandler "Car-Management-System/handler/login"
+	userHandler "Car-Management-System/handler/user"
+	vinHandler "Car-Management-System/handler/vin"
+	webhookHandler "Car-Management-System/handler/webhook"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+)
+
+type routeHandlers struct {
+	car     *carHandler.CarHandler
+	engine  *engineHandler.EngineHandler
+	login   *loginHandler.LoginHandler
+	user    *userHandler.UserHandler
+	jwks    *jwksHandler.JWKSHandler
+	apiKey  *apiKeyHandler.APIKeyHandler
+	job     *jobHandler.JobHandler
+	webhook *webhookHandler.WebhookHandler
+	events  *eventsHandler.EventsHandler
+	vin     *vinHandler.VINHandler
+	graphql *graphqlapi.Handler
+}
+
+// newRouter registers every route. auth authenticates the protected
+// routes and validate checks requests against the OpenAPI document once
+// the caller is known to be allowed to make them.
+func newRouter(h routeHandlers, auth func(http.Handler) http.Handler, validate func(http.Handler) http.Handler) *mux.Router {
+	router := mux.NewRouter()
+
+	router.Use(otelmux.Middleware("Car-Management-System"))
+	router.Use(middleware.MetricMiddleware)
+
+	router.Handle("/login", validate(http.HandlerFunc(h.login.Login))).Methods("POST")
+	router.HandleFunc("/token/refresh", h.login.Refresh).Methods("POST")
+	router.HandleFunc("/.well-known/jwks.json", h.jwks.GetJWKS).Methods("GET")
+	router.HandleFunc("/openapi.json", openapi.ServeSpec).Methods("GET")
+	router.HandleFunc("/docs", openapi.ServeDocs).Methods("GET")
+
+	protected := router.PathPrefix("/").Subrouter()
+
+	protected.Use(auth)
+	protected.Use(middleware.AuthorizationMiddleware)
+	protected.Use(validate)
+
+	protected.HandleFunc("/logout", h.login.Logout).Methods("POST")
+
+	protected.HandleFunc("/cars/export", h.car.ExportCars).Methods("GET")
+	protected.HandleFunc("/cars/vin/{vin}", h.car.GetCarByVIN).Methods("GET")
+	protected.HandleFunc("/cars/{id}", h.car.GetCarByID).Methods("GET")
+	protected.HandleFunc("/cars/{id}/history", h.car.GetCarHistory).Methods("GET")
+	protected.HandleFunc("/cars", h.car.ListCars).Methods("GET")
+	protected.HandleFunc("/cars", h.car.CreateCar).Methods("POST")
+	protected.HandleFunc("/cars/import", h.car.ImportCars).Methods("POST")
+	protected.HandleFunc("/cars/{id}", h.car.UpdateCar).Methods("PUT")
+	protected.HandleFunc("/cars/{id}", h.car.PatchCar).Methods("PATCH")
+	protected.HandleFunc("/cars/{id}", h.car.DeleteCar).Methods("DELETE")
+	protected.HandleFunc("/cars/{id}/restore", h.car.RestoreCar).Methods("POST")
+	protected.HandleFunc("/cars/{id}/purge", h.car.PurgeCar).Methods("DELETE")
+
+	protected.HandleFunc("/engine", h.engine.ListEngines).Methods("GET")
+	protected.HandleFunc("/engine/{id}", h.engine.GetEngineById).Methods("GET")
+	protected.HandleFunc("/engine", h.engine.CreateEngine).Methods("POST")
+	protected.HandleFunc("/engine/import", h.engine.ImportEngines).Methods("POST")
+	protected.HandleFunc("/engine/{id}", h.engine.UpdateEngine).Methods("PUT")
+	protected.HandleFunc("/engine/{id}", h.engine.PatchEngine).Methods("PATCH")
+	protected.HandleFunc("/engine/{id}", h.engine.DeleteEngine).Methods("DELETE")
+	protected.HandleFunc("/engine/{id}/restore", h.engine.RestoreEngine).Methods("POST")
+	protected.HandleFunc("/engine/{id}/purge", h.engine.PurgeEngine).Methods("DELETE")
+
+	protected.HandleFunc("/vin/decode", h.vin.DecodeVIN).Methods("POST")
+
+	protected.HandleFunc("/users", h.user.ListUsers).Methods("GET")
+	protected.HandleFunc("/users", h.user.RegisterUser).Methods("POST")
+	protected.HandleFunc("/users/me/password", h.user.ChangePassword).Methods("PUT")
+	protected.HandleFunc("/users/{id}/disable", h.user.DisableUser).Methods("POST")
+	protected.HandleFunc("/users/{id}/enable", h.user.EnableUser).Methods("POST")
+	protected.HandleFunc("/users/{id}/role", h.user.SetUserRole).Methods("PUT")
+	protected.HandleFunc("/users/{id}", h.user.DeleteUser).Methods("DELETE")
+
+	protected.HandleFunc("/api-keys", h.apiKey.ListAPIKeys).Methods("GET")
+	protected.HandleFunc("/api-keys", h.apiKey.CreateAPIKey).Methods("POST")
+	protected.HandleFunc("/api-keys/{id}", h.apiKey.RevokeAPIKey).Methods("DELETE")
+
+	protected.HandleFunc("/jobs", h.job.CreateJob).Methods("POST")
+	protected.HandleFunc("/jobs/{id}", h.job.GetJob).Methods("GET")
+	protected.HandleFunc("/jobs/{id}", h.job.CancelJob).Methods("DELETE")
+	protected.HandleFunc("/jobs/{id}/result", h.job.GetJobResult).Methods("GET")
+
+	protected.HandleFunc("/webhooks/dead-letters", h.webhook.ListDeadLetters).Methods("GET")
+	protected.HandleFunc("/webhooks/dead-letters/{id}/replay", h.webhook.ReplayDeadLetter).Methods("POST")
+	protected.HandleFunc("/webhooks", h.webhook.ListWebhooks).Methods("GET")
+	protected.HandleFunc("/webhooks", h.webhook.CreateWebhook).Methods("POST")
+	protected.HandleFunc("/webhooks/{id}", h.webhook.GetWebhook).Methods("GET")
+	protected.HandleFunc("/webhooks/{id}", h.webhook.UpdateWebhook).Methods("PUT")
+	protected.HandleFunc("/webhooks/{id}", h.webhook.DeleteWebhook).Methods("DELETE")
+
+	protected.HandleFunc("/events/stream", h.events.StreamEvents).Methods("GET")
+
+	protected.HandleFunc("/graphql", h.graphql.ServeGraphQL).Methods("GET", "POST")
+
+	router.Handle("/metrics", promhttp.Handler())
+
+	return router
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"Car-Management-System/handler/problem"
+	"Car-Management-System/models"
+	"Car-Management-System/openapi"
+	"Car-Management-System/store"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	carHandler "Car-Management-System/handler/car"
+	engineHandler "Car-Management-System/handler/engine"
+	carService "Car-Management-System/service/car"
+	engineService "Car-Management-System/service/engine"
+
+	"github.com/gorilla/mux"
+)
+
+// documentedPrefixes are the paths the OpenAPI document must describe
+// completely.
+var documentedPrefixes = []string{"/login", "/cars", "/engine", "/vin", "/metrics"}
+
+func passThrough(next http.Handler) http.Handler {
+	return next
+}
+
+func TestOpenAPIDocumentMatchesRoutes(t *testing.T) {
+	router := newRouter(routeHandlers{}, passThrough, passThrough)
+
+	var registered []string
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !documented(template) {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Routes without a method, such as /metrics, are documented
+			// as GET.
+			methods = []string{http.MethodGet}
+		}
+		for _, method := range methods {
+			registered = append(registered, fmt.Sprintf("%s %s", method, template))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+	sort.Strings(registered)
+
+	operations, err := openapi.Operations()
+	if err != nil {
+		t.Fatalf("reading the OpenAPI document: %v", err)
+	}
+
+	for _, route := range registered {
+		if !contains(operations, route) {
+			t.Errorf("route %s is missing from the OpenAPI document", route)
+		}
+	}
+	for _, operation := range operations {
+		if !contains(registered, operation) {
+			t.Errorf("operation %s of the OpenAPI document has no route", operation)
+		}
+	}
+}
+
+func TestOpenAPIDocumentCompiles(t *testing.T) {
+	if _, err := openapi.NewValidator(); err != nil {
+		t.Fatalf("compiling the OpenAPI document: %v", err)
+	}
+}
+
+func documented(template string) bool {
+	for _, prefix := range documentedPrefixes {
+		if template == prefix || strings.HasPrefix(template, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	i := sort.SearchStrings(list, value)
+	return i < len(list) && list[i] == value
+}
+
+// unreachableCarStore and unreachableEngineStore fail the test by
+// panicking if a request gets as far as the store.
+type unreachableCarStore struct{ store.CarStoreInterface }
+
+type unreachableEngineStore struct{ store.EngineStoreInterface }
+
+func asAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "username", "admin")
+		ctx = context.WithValue(ctx, "role", models.RoleAdmin)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func TestMalformedIDsAreNotFound(t *testing.T) {
+	router := newRouter(routeHandlers{
+		car:    carHandler.NewCarHandler(carService.NewCarService(unreachableCarStore{})),
+		engine: engineHandler.NewEngineHandler(engineService.NewEngineService(unreachableEngineStore{})),
+	}, asAdmin, passThrough)
+
+	var routes []string
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || !strings.Contains(template, "{id}") || !(strings.HasPrefix(template, "/cars/") || strings.HasPrefix(template, "/engine/")) {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+template)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking routes: %v", err)
+	}
+	if len(routes) == 0 {
+		t.Fatal("found no {id} routes")
+	}
+
+	for _, route := range routes {
+		t.Run(route, func(t *testing.T) {
+			method, template, _ := strings.Cut(route, " ")
+			path := strings.Replace(template, "{id}", "not-a-uuid", 1)
+
+			r := httptest.NewRequest(method, path, strings.NewReader("{}"))
+			r.Header.Set("If-Match", "*")
+			if method == http.MethodPatch {
+				r.Header.Set("Content-Type", "application/merge-patch+json")
+			} else {
+				r.Header.Set("Content-Type", "application/json")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, http.StatusNotFound, w.Body)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Fatalf("Content-Type = %q, want application/problem+json", got)
+			}
+
+			var body problem.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding the problem: %v", err)
+			}
+			if body.Type != "/problems/not-found" || body.Status != http.StatusNotFound || body.Instance != path {
+				t.Fatalf("problem = %+v, want a not-found problem for %s", body, path)
+			}
+		})
+	}
+}
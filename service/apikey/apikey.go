@@ -0,0 +1,174 @@
+package apikey
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	keyPrefix    = "cms_"
+	prefixLength = 12
+)
+
+type APIKeyService struct {
+	store store.APIKeyStoreInterface
+	users store.UserStoreInterface
+}
+
+func NewAPIKeyService(store store.APIKeyStoreInterface, users store.UserStoreInterface) *APIKeyService {
+	return &APIKeyService{
+		store: store,
+		users: users,
+	}
+}
+
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userName string, role string, keyReq *models.APIKeyRequest) (*models.CreatedAPIKey, error) {
+	tracer := otel.Tracer("APIKeyService")
+	ctx, span := tracer.Start(ctx, "CreateAPIKey-Service")
+	defer span.End()
+
+	if keyReq.Role == "" {
+		keyReq.Role = models.RoleViewer
+	}
+
+	if err := models.ValidateAPIKeyRequest(*keyReq); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidAPIKeyRequest, err)
+	}
+
+	if !models.RoleAtLeast(role, keyReq.Role) {
+		return nil, fmt.Errorf("%w: role %q exceeds your own role %q", models.ErrInvalidAPIKeyRequest, keyReq.Role, role)
+	}
+
+	owner, err := s.users.GetUserByUsername(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	rawKey, err := generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := models.APIKey{
+		ID:        uuid.New(),
+		UserID:    owner.ID,
+		UserName:  owner.UserName,
+		Name:      strings.TrimSpace(keyReq.Name),
+		Prefix:    rawKey[:prefixLength],
+		KeyHash:   hashKey(rawKey),
+		Role:      keyReq.Role,
+		CreatedAt: now,
+	}
+	if keyReq.ExpiresInDays > 0 {
+		expiresAt := now.Add(time.Duration(keyReq.ExpiresInDays) * 24 * time.Hour)
+		key.ExpiresAt = &expiresAt
+	}
+
+	if err := s.store.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKey{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userName string) ([]models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyService")
+	ctx, span := tracer.Start(ctx, "ListAPIKeys-Service")
+	defer span.End()
+
+	owner, err := s.users.GetUserByUsername(ctx, userName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store.ListAPIKeys(ctx, owner.ID.String())
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userName string, role string, id string) (*models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyService")
+	ctx, span := tracer.Start(ctx, "RevokeAPIKey-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrAPIKeyNotFound
+	}
+
+	key, err := s.store.GetAPIKeyById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.UserName != userName && role != models.RoleAdmin {
+		return nil, models.ErrAPIKeyForbidden
+	}
+
+	revokedKey, err := s.store.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revokedKey, nil
+}
+
+// AuthenticateAPIKey resolves a raw key to its record. The returned key's
+// Role is capped at the owner's current role, so demoting a user also
+// demotes every key they created.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyService")
+	ctx, span := tracer.Start(ctx, "AuthenticateAPIKey-Service")
+	defer span.End()
+
+	if !strings.HasPrefix(rawKey, keyPrefix) {
+		return nil, models.ErrInvalidAPIKey
+	}
+
+	key, owner, err := s.store.GetAPIKeyByHash(ctx, hashKey(rawKey))
+	if err != nil {
+		if errors.Is(err, models.ErrAPIKeyNotFound) {
+			return nil, models.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || owner.Disabled || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		return nil, models.ErrInvalidAPIKey
+	}
+
+	if !models.RoleAtLeast(owner.Role, key.Role) {
+		key.Role = owner.Role
+	}
+
+	if err := s.store.TouchAPIKey(ctx, key.ID.String(), now); err != nil {
+		log.Println("Error updating api key last use: ", err)
+	}
+
+	return &key, nil
+}
+
+func generateKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -3,10 +3,20 @@ package car
 import (
 	"Car-Management-System/models"
 	"Car-Management-System/store"
+	"Car-Management-System/vin"
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 )
 
+// maxPatchAttempts bounds how often an unconditional patch is re-applied
+// after a concurrent write.
+const maxPatchAttempts = 3
+
 type CarService struct {
 	store store.CarStoreInterface
 }
@@ -17,28 +27,39 @@ func NewCarService(store store.CarStoreInterface) *CarService {
 	}
 }
 
-func (s *CarService) GetCarById(ctx context.Context, id string) (*models.Car, error) {
+func (s *CarService) GetCarById(ctx context.Context, id string, includeDeleted bool) (*models.Car, error) {
 	tracer := otel.Tracer("CarService")
 	ctx, span := tracer.Start(ctx, "GetCarByID-Service")
 	defer span.End()
 
-	car, err := s.store.GetCarById(ctx, id)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	car, err := s.store.GetCarById(ctx, id, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
 	return &car, nil
 }
 
-func (s *CarService) GetCarsByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
+// GetCarByVIN looks a car up by its VIN, in any case. A malformed VIN
+// cannot belong to a car, so it is reported as not found.
+func (s *CarService) GetCarByVIN(ctx context.Context, v string, includeDeleted bool) (*models.Car, error) {
 	tracer := otel.Tracer("CarService")
-	ctx, span := tracer.Start(ctx, "GetCarsByBrand-Service")
+	ctx, span := tracer.Start(ctx, "GetCarByVIN-Service")
 	defer span.End()
 
-	cars, err := s.store.GetCarByBrand(ctx, brand, isEngine)
+	v = vin.Normalize(v)
+	if err := vin.Validate(v); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	car, err := s.store.GetCarByVIN(ctx, v, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
-	return cars, nil
+	return &car, nil
 }
 
 func (s *CarService) CreateCar(ctx context.Context, car *models.CarRequest) (*models.Car, error) {
@@ -46,6 +67,12 @@ func (s *CarService) CreateCar(ctx context.Context, car *models.CarRequest) (*mo
 	ctx, span := tracer.Start(ctx, "CreateCar-Service")
 	defer span.End()
 
+	if err := s.loadEngine(ctx, car); err != nil {
+		return nil, err
+	}
+
+	car.ApplyVIN()
+
 	if err := models.ValidateRequest(*car); err != nil {
 		return nil, err
 	}
@@ -58,16 +85,25 @@ func (s *CarService) CreateCar(ctx context.Context, car *models.CarRequest) (*mo
 	return &createdCar, nil
 }
 
-func (s *CarService) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (*models.Car, error) {
+func (s *CarService) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (*models.Car, error) {
 	tracer := otel.Tracer("CarService")
 	ctx, span := tracer.Start(ctx, "Update-Service")
 	defer span.End()
 
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	if err := s.loadEngine(ctx, carReq); err != nil {
+		return nil, err
+	}
+
+	carReq.ApplyVIN()
 	if err := models.ValidateRequest(*carReq); err != nil {
 		return nil, err
 	}
 
-	updatedCar, err := s.store.UpdateCar(ctx, id, carReq)
+	updatedCar, err := s.store.UpdateCar(ctx, id, carReq, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -75,14 +111,300 @@ func (s *CarService) UpdateCar(ctx context.Context, id string, carReq *models.Ca
 	return &updatedCar, nil
 }
 
-func (s *CarService) DeleteCar(ctx context.Context, id string) (*models.Car, error) {
+// PatchCar applies patch to the current car and writes the result. Only
+// the engine's id can be changed inside engine; the other engine fields
+// may be left out but not changed. PATCH /cars/{id} is the only caller,
+// and it passes no expected version only for If-Match: *. Such a write
+// asked not to be checked, so when it loses a race with another one it is
+// retried against the newer car instead of failing.
+func (s *CarService) PatchCar(ctx context.Context, id string, patch *models.Patch, expectedVersion int64) (*models.Car, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "PatchCar-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, err := s.store.GetCarById(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return nil, models.ErrVersionMismatch
+		}
+
+		carReq := models.CarRequest{
+			Name:     current.Name,
+			Year:     current.Year,
+			Brand:    current.Brand,
+			FuelType: current.FuelType,
+			VIN:      current.VIN,
+			Engine: models.Engine{
+				EngineID:      current.Engine.EngineID,
+				Displacement:  current.Engine.Displacement,
+				NoOfCylinders: current.Engine.NoOfCylinders,
+				CarRange:      current.Engine.CarRange,
+				Powertrain:    current.Engine.Powertrain,
+			},
+			Price: current.Price,
+		}
+
+		var patched models.CarRequest
+		if err := patch.Apply(carReq, &patched); err != nil {
+			return nil, err
+		}
+
+		if err := mergeEngineDetails(&patched.Engine, carReq.Engine); err != nil {
+			return nil, err
+		}
+
+		if err := s.loadEngine(ctx, &patched); err != nil {
+			return nil, err
+		}
+
+		patched.ApplyVIN()
+
+		if err := models.ValidateRequest(patched); err != nil {
+			return nil, err
+		}
+
+		patchedCar, err := s.store.PatchCar(ctx, id, &patched, current.Version)
+		if errors.Is(err, models.ErrVersionMismatch) && expectedVersion == 0 && attempt < maxPatchAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &patchedCar, nil
+	}
+}
+
+// mergeEngineDetails fills engine fields the patch left out from the
+// current engine and rejects any it changed, as those belong to the engine
+// resource rather than the car.
+func mergeEngineDetails(patched *models.Engine, current models.Engine) error {
+	details := []struct {
+		patched *int32
+		current int32
+	}{
+		{&patched.Displacement, current.Displacement},
+		{&patched.NoOfCylinders, current.NoOfCylinders},
+		{&patched.CarRange, current.CarRange},
+	}
+
+	for _, detail := range details {
+		if *detail.patched == 0 {
+			*detail.patched = detail.current
+			continue
+		}
+		if *detail.patched != detail.current {
+			return errEngineDetailChanged
+		}
+	}
+
+	if patched.Powertrain == (models.Powertrain{}) {
+		patched.Powertrain = current.Powertrain
+	} else if patched.Powertrain != current.Powertrain {
+		return errEngineDetailChanged
+	}
+
+	return nil
+}
+
+var errEngineDetailChanged = fmt.Errorf("%w: only enigne_id can be changed on a car's engine, use PATCH /engine/{id} for the rest", models.ErrUnprocessablePatch)
+
+// loadEngine replaces the engine details of carReq with those of the
+// stored engine it names, so that the car is validated against the real
+// powertrain rather than whatever the client sent.
+func (s *CarService) loadEngine(ctx context.Context, carReq *models.CarRequest) error {
+	if carReq.Engine.EngineID == uuid.Nil {
+		return nil
+	}
+
+	engines, err := s.store.GetEnginesByIds(ctx, []uuid.UUID{carReq.Engine.EngineID})
+	if err != nil {
+		return err
+	}
+
+	engine, ok := engines[carReq.Engine.EngineID]
+	if !ok {
+		return models.ErrCarEngineMissing
+	}
+	carReq.Engine = engine
+	return nil
+}
+
+func (s *CarService) DeleteCar(ctx context.Context, id string, expectedVersion int64) (*models.Car, error) {
 	tracer := otel.Tracer("CarService")
 	ctx, span := tracer.Start(ctx, "Delete-Service")
 	defer span.End()
 
-	deletedCar, err := s.store.DeleteCar(ctx, id)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	deletedCar, err := s.store.DeleteCar(ctx, id, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
 	return &deletedCar, nil
-}
\ No newline at end of file
+}
+
+func (s *CarService) ListCars(ctx context.Context, filter *models.CarFilter) (*models.CarPage, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "ListCars-Service")
+	defer span.End()
+
+	page, err := s.store.ListCars(ctx, *filter)
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+func (s *CarService) ExportCars(ctx context.Context, filter *models.CarFilter, fn func(models.Car) error) error {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "ExportCars-Service")
+	defer span.End()
+
+	return s.store.ExportCars(ctx, *filter, fn)
+}
+
+func (s *CarService) GetCarHistory(ctx context.Context, id string) ([]models.CarHistory, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "GetCarHistory-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	history, err := s.store.GetCarHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, models.ErrCarNotFound
+	}
+	return history, nil
+}
+
+func (s *CarService) GetCarAsOf(ctx context.Context, id string, asOf time.Time) (*models.Car, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "GetCarAsOf-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	car, err := s.store.GetCarAsOf(ctx, id, asOf)
+	if err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
+
+func (s *CarService) RestoreCar(ctx context.Context, id string) (*models.Car, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "RestoreCar-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrCarNotFound
+	}
+
+	restoredCar, err := s.store.RestoreCar(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &restoredCar, nil
+}
+
+func (s *CarService) PurgeCar(ctx context.Context, id string) error {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "PurgeCar-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return models.ErrCarNotFound
+	}
+
+	return s.store.PurgeCar(ctx, id)
+}
+
+// ImportCars validates every row and, unless dryRun is set or any row is
+// invalid, inserts them all in one transaction. Engine references are
+// resolved with a single lookup rather than one per row.
+func (s *CarService) ImportCars(ctx context.Context, rows []models.CarImportRow, dryRun bool) (*models.ImportReport, error) {
+	tracer := otel.Tracer("CarService")
+	ctx, span := tracer.Start(ctx, "ImportCars-Service")
+	defer span.End()
+
+	report := &models.ImportReport{DryRun: dryRun, Rows: len(rows), Errors: []models.ImportRowError{}}
+
+	var engineIDs []uuid.UUID
+	for _, row := range rows {
+		if row.Err == nil {
+			engineIDs = append(engineIDs, row.Car.Engine.EngineID)
+		}
+	}
+
+	engines, err := s.store.GetEnginesByIds(ctx, engineIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	valid := make([]models.CarRequest, 0, len(rows))
+	vinLines := map[string]int{}
+	for _, row := range rows {
+		err := row.Err
+		if err == nil {
+			engine, ok := engines[row.Car.Engine.EngineID]
+			if ok {
+				row.Car.Engine = engine
+				row.Car.ApplyVIN()
+				err = models.ValidateRequest(row.Car)
+			} else {
+				err = models.ErrCarEngineMissing
+			}
+		}
+		if err == nil && row.Car.VIN != "" {
+			if line, ok := vinLines[row.Car.VIN]; ok {
+				err = fmt.Errorf("%w: line %d has the same vin", models.ErrCarVINTaken, line)
+			} else {
+				vinLines[row.Car.VIN] = row.Line
+			}
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, models.NewImportRowError(row.Line, err))
+			continue
+		}
+		valid = append(valid, row.Car)
+	}
+	report.Valid = len(valid)
+
+	if len(report.Errors) > 0 && !dryRun {
+		return report, models.ErrImportRejected
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	createdCars, err := s.store.CreateCars(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Imported = len(createdCars)
+	for _, car := range createdCars {
+		report.IDs = append(report.IDs, car.ID)
+	}
+
+	return report, nil
+}
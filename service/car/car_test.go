@@ -0,0 +1,267 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var (
+	petrolEngine = models.Engine{
+		EngineID:      uuid.MustParse("e1f86b1a-0873-4c19-bae2-fc60329d0140"),
+		Displacement:  1500,
+		NoOfCylinders: 4,
+		CarRange:      600,
+		Powertrain:    models.Powertrain{Type: models.PowertrainICE},
+		Version:       1,
+	}
+	otherPetrolEngine = models.Engine{
+		EngineID:      uuid.MustParse("3b2a1c0d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"),
+		Displacement:  2000,
+		NoOfCylinders: 6,
+		CarRange:      500,
+		Powertrain:    models.Powertrain{Type: models.PowertrainICE},
+		Version:       1,
+	}
+	electricEngine = models.Engine{
+		EngineID:   uuid.MustParse("9c8b7a6d-5e4f-4a3b-9c1d-0e2f3a4b5c6d"),
+		CarRange:   450,
+		Powertrain: models.Powertrain{Type: models.PowertrainBEV, BatteryKWh: 75, ChargePowerKW: 150, MotorPowerKW: 200, MotorTorqueNm: 400},
+		Version:    1,
+	}
+)
+
+// patchStore holds one car and the engines it may be patched to. The
+// first conflicts writes fail as if another write got there first.
+type patchStore struct {
+	store.CarStoreInterface
+	car       models.Car
+	conflicts int
+	writes    int
+	written   models.CarRequest
+}
+
+func (p *patchStore) GetCarById(ctx context.Context, id string, includeDeleted bool) (models.Car, error) {
+	if id != p.car.ID.String() {
+		return models.Car{}, models.ErrCarNotFound
+	}
+	return p.car, nil
+}
+
+func (p *patchStore) GetEnginesByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Engine, error) {
+	engines := map[uuid.UUID]models.Engine{}
+	for _, engine := range []models.Engine{petrolEngine, otherPetrolEngine, electricEngine} {
+		for _, id := range ids {
+			if engine.EngineID == id {
+				engines[id] = engine
+			}
+		}
+	}
+	return engines, nil
+}
+
+func (p *patchStore) PatchCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (models.Car, error) {
+	p.writes++
+	if expectedVersion != p.car.Version {
+		return models.Car{}, models.ErrVersionMismatch
+	}
+	if p.conflicts > 0 {
+		p.conflicts--
+		p.car.Version++
+		return models.Car{}, models.ErrVersionMismatch
+	}
+
+	p.written = *carReq
+	p.car.Name = carReq.Name
+	p.car.Year = carReq.Year
+	p.car.Brand = carReq.Brand
+	p.car.FuelType = carReq.FuelType
+	p.car.VIN = carReq.VIN
+	p.car.Engine = carReq.Engine
+	p.car.Price = carReq.Price
+	p.car.Version++
+	return p.car, nil
+}
+
+func newPatchStore() *patchStore {
+	return &patchStore{car: models.Car{
+		ID:       uuid.MustParse("6a1f3e1c-8d1e-4b0a-9b8f-2c3d4e5f6a7b"),
+		Name:     "Civic",
+		Year:     "2020",
+		Brand:    "Honda",
+		FuelType: "Petrol",
+		Engine:   petrolEngine,
+		Price:    25000,
+		Version:  3,
+	}}
+}
+
+func TestPatchCar(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		engine      uuid.UUID
+		carName     string
+		err         error
+	}{
+		{
+			name:        "merge patch changes a car field",
+			contentType: models.MergePatchType,
+			body:        `{"Name": "Civic Type R"}`,
+			engine:      petrolEngine.EngineID,
+			carName:     "Civic Type R",
+		},
+		{
+			name:        "merge patch changes the engine id",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"enigne_id": "3b2a1c0d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"}}`,
+			engine:      otherPetrolEngine.EngineID,
+			carName:     "Civic",
+		},
+		{
+			name:        "merge patch repeats an engine field unchanged",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"displacement": 1500}}`,
+			engine:      petrolEngine.EngineID,
+			carName:     "Civic",
+		},
+		{
+			name:        "merge patch changes an engine field",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"displacement": 1800}}`,
+			err:         models.ErrUnprocessablePatch,
+		},
+		{
+			name:        "merge patch changes the powertrain",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"powertrain": "HEV"}}`,
+			err:         models.ErrUnprocessablePatch,
+		},
+		{
+			name:        "merge patch names a missing engine",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"enigne_id": "00000000-0000-4000-8000-000000000001"}}`,
+			err:         models.ErrCarEngineMissing,
+		},
+		{
+			name:        "merge patch leaves an invalid car",
+			contentType: models.MergePatchType,
+			body:        `{"year": "20"}`,
+			err:         models.ErrValidation,
+		},
+		{
+			name:        "merge patch moves to an engine that does not fit the fuel type",
+			contentType: models.MergePatchType,
+			body:        `{"engine": {"enigne_id": "9c8b7a6d-5e4f-4a3b-9c1d-0e2f3a4b5c6d"}}`,
+			err:         models.ErrValidation,
+		},
+		{
+			name:        "merge patch with an unknown field",
+			contentType: models.MergePatchType,
+			body:        `{"colour": "red"}`,
+			err:         models.ErrUnprocessablePatch,
+		},
+		{
+			name:        "json patch changes the engine id",
+			contentType: models.JSONPatchType,
+			body:        `[{"op": "replace", "path": "/engine/enigne_id", "value": "3b2a1c0d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"}]`,
+			engine:      otherPetrolEngine.EngineID,
+			carName:     "Civic",
+		},
+		{
+			name:        "json patch removes an engine field",
+			contentType: models.JSONPatchType,
+			body:        `[{"op": "remove", "path": "/engine/carRange"}]`,
+			engine:      petrolEngine.EngineID,
+			carName:     "Civic",
+		},
+		{
+			name:        "json patch changes an engine field",
+			contentType: models.JSONPatchType,
+			body:        `[{"op": "replace", "path": "/engine/noOfCylinders", "value": 6}]`,
+			err:         models.ErrUnprocessablePatch,
+		},
+		{
+			name:        "json patch with a failing test",
+			contentType: models.JSONPatchType,
+			body:        `[{"op": "test", "path": "/Name", "value": "Accord"}, {"op": "replace", "path": "/Name", "value": "Civic Si"}]`,
+			err:         models.ErrUnprocessablePatch,
+		},
+		{
+			name:        "malformed json patch",
+			contentType: models.JSONPatchType,
+			body:        `[{"op": "replace"`,
+			err:         models.ErrMalformedPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := newPatchStore()
+			service := NewCarService(cars)
+
+			patch, err := models.NewPatch(tt.contentType, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("NewPatch() error = %v", err)
+			}
+
+			car, err := service.PatchCar(context.Background(), cars.car.ID.String(), patch, cars.car.Version)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Fatalf("PatchCar() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				if cars.writes != 0 {
+					t.Fatalf("rejected patch was written %d times", cars.writes)
+				}
+				return
+			}
+
+			if car.Name != tt.carName {
+				t.Errorf("Name = %q, want %q", car.Name, tt.carName)
+			}
+			if cars.written.Engine.EngineID != tt.engine {
+				t.Errorf("engine = %s, want %s", cars.written.Engine.EngineID, tt.engine)
+			}
+			if want := cars.written.Engine; car.Engine != want {
+				t.Errorf("engine details = %+v, want the stored engine %+v", car.Engine, want)
+			}
+		})
+	}
+}
+
+func TestPatchCarRetries(t *testing.T) {
+	tests := []struct {
+		name            string
+		expectedVersion int64
+		conflicts       int
+		writes          int
+		err             error
+	}{
+		{name: "unconditional patch is retried", conflicts: 2, writes: 3},
+		{name: "unconditional patch gives up", conflicts: maxPatchAttempts, writes: maxPatchAttempts, err: models.ErrVersionMismatch},
+		{name: "conditional patch is not retried", expectedVersion: 3, conflicts: 1, writes: 1, err: models.ErrVersionMismatch},
+		{name: "conditional patch on an old version", expectedVersion: 2, writes: 0, err: models.ErrVersionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := newPatchStore()
+			cars.conflicts = tt.conflicts
+			service := NewCarService(cars)
+
+			patch, _ := models.NewPatch(models.MergePatchType, []byte(`{"price": 24000}`))
+			_, err := service.PatchCar(context.Background(), cars.car.ID.String(), patch, tt.expectedVersion)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Fatalf("PatchCar() error = %v, want %v", err, tt.err)
+			}
+			if cars.writes != tt.writes {
+				t.Fatalf("writes = %d, want %d", cars.writes, tt.writes)
+			}
+		})
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"Car-Management-System/models"
 	"Car-Management-System/store"
 	"context"
+	"errors"
 
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 )
 
+// maxPatchAttempts bounds how often an unconditional patch is re-applied
+// after a concurrent write.
+const maxPatchAttempts = 3
+
 type EngineService struct {
 	store store.EngineStoreInterface
 }
@@ -18,23 +24,37 @@ func NewEngineService(store store.EngineStoreInterface) *EngineService {
 	}
 }
 
-func (s *EngineService) GetEngineById(ctx context.Context, id string) (*models.Engine, error) {
+func (s *EngineService) GetEngineById(ctx context.Context, id string, includeDeleted bool) (*models.Engine, error) {
 	tracer := otel.Tracer("EngineService")
 	ctx, span := tracer.Start(ctx, "GetEngineById-Service")
 	defer span.End()
 
-	engine, err := s.store.EngineById(ctx, id)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrEngineNotFound
+	}
+
+	engine, err := s.store.EngineById(ctx, id, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
 	return &engine, nil
 }
 
+// GetEnginesByIds looks up many engines in one query, keyed by id.
+func (s *EngineService) GetEnginesByIds(ctx context.Context, ids []uuid.UUID, includeDeleted bool) (map[uuid.UUID]models.Engine, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-Service")
+	defer span.End()
+
+	return s.store.EnginesByIds(ctx, ids, includeDeleted)
+}
+
 func (s *EngineService) CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (*models.Engine, error) {
 	tracer := otel.Tracer("EngineService")
 	ctx, span := tracer.Start(ctx, "CreateEngine-Service")
 	defer span.End()
 	
+	engineReq.SetDefaultType()
 	if err := models.ValidateEngineRequest(*engineReq); err != nil {
 		return nil, err
 	}
@@ -46,16 +66,21 @@ func (s *EngineService) CreateEngine(ctx context.Context, engineReq *models.Engi
 	return &createdEngine, nil
 }
 
-func (s *EngineService) UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (*models.Engine, error) {
+func (s *EngineService) UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (*models.Engine, error) {
 	tracer := otel.Tracer("EngineService")
 	ctx, span := tracer.Start(ctx, "UpdateEngine-Service")
 	defer span.End()
 
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrEngineNotFound
+	}
+
+	engineReq.SetDefaultType()
 	if err := models.ValidateEngineRequest(*engineReq); err != nil {
 		return nil, err
 	}
 
-	updatedEngine, err := s.store.EngineUpdate(ctx, id, engineReq)
+	updatedEngine, err := s.store.EngineUpdate(ctx, id, engineReq, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -63,15 +88,159 @@ func (s *EngineService) UpdateEngine(ctx context.Context, id string, engineReq *
 	return &updatedEngine, nil
 }
 
-func (s *EngineService) DeleteEngine(ctx context.Context, id string) (*models.Engine, error) {
+// PatchEngine applies patch to the current engine and writes the result.
+// PATCH /engine/{id} is the only caller, and it passes no expected
+// version only for If-Match: *. Such a write asked not to be checked, so
+// when it loses a race with another one it is retried against the newer
+// engine instead of failing.
+func (s *EngineService) PatchEngine(ctx context.Context, id string, patch *models.Patch, expectedVersion int64) (*models.Engine, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "PatchEngine-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrEngineNotFound
+	}
+
+	for attempt := 1; ; attempt++ {
+		current, err := s.store.EngineById(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return nil, models.ErrVersionMismatch
+		}
+
+		engineReq := models.EngineRequest{
+			Displacement:  current.Displacement,
+			NoOfCylinders: current.NoOfCylinders,
+			CarRange:      current.CarRange,
+			Powertrain:    current.Powertrain,
+		}
+
+		var patched models.EngineRequest
+		if err := patch.Apply(engineReq, &patched); err != nil {
+			return nil, err
+		}
+
+		patched.SetDefaultType()
+		if err := models.ValidateEngineRequest(patched); err != nil {
+			return nil, err
+		}
+
+		patchedEngine, err := s.store.EnginePatch(ctx, id, &patched, current.Version)
+		if errors.Is(err, models.ErrVersionMismatch) && expectedVersion == 0 && attempt < maxPatchAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return &patchedEngine, nil
+	}
+}
+
+func (s *EngineService) DeleteEngine(ctx context.Context, id string, expectedVersion int64) (*models.Engine, error) {
 	tracer := otel.Tracer("EngineService")
 	ctx, span := tracer.Start(ctx, "DeleteEngine-Service")
 	defer span.End()
 
-	deletedEngine, err := s.store.EngineDelete(ctx, id)
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrEngineNotFound
+	}
+
+	deletedEngine, err := s.store.EngineDelete(ctx, id, expectedVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	return &deletedEngine, nil
 }
+
+func (s *EngineService) ListEngines(ctx context.Context, filter *models.EngineFilter) (*models.EnginePage, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "ListEngines-Service")
+	defer span.End()
+
+	page, err := s.store.ListEngines(ctx, *filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+func (s *EngineService) RestoreEngine(ctx context.Context, id string) (*models.Engine, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "RestoreEngine-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrEngineNotFound
+	}
+
+	restoredEngine, err := s.store.RestoreEngine(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &restoredEngine, nil
+}
+
+func (s *EngineService) PurgeEngine(ctx context.Context, id string) error {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "PurgeEngine-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return models.ErrEngineNotFound
+	}
+
+	return s.store.PurgeEngine(ctx, id)
+}
+
+// ImportEngines validates every row and, unless dryRun is set or any row
+// is invalid, inserts them all in one transaction.
+func (s *EngineService) ImportEngines(ctx context.Context, rows []models.EngineImportRow, dryRun bool) (*models.ImportReport, error) {
+	tracer := otel.Tracer("EngineService")
+	ctx, span := tracer.Start(ctx, "ImportEngines-Service")
+	defer span.End()
+
+	report := &models.ImportReport{DryRun: dryRun, Rows: len(rows), Errors: []models.ImportRowError{}}
+
+	valid := make([]models.EngineRequest, 0, len(rows))
+	for _, row := range rows {
+		err := row.Err
+		if err == nil {
+			row.Engine.SetDefaultType()
+			err = models.ValidateEngineRequest(row.Engine)
+		}
+
+		if err != nil {
+			report.Errors = append(report.Errors, models.NewImportRowError(row.Line, err))
+			continue
+		}
+		valid = append(valid, row.Engine)
+	}
+	report.Valid = len(valid)
+
+	if len(report.Errors) > 0 && !dryRun {
+		return report, models.ErrImportRejected
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	createdEngines, err := s.store.EngineCreateMany(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Imported = len(createdEngines)
+	for _, engine := range createdEngines {
+		report.IDs = append(report.IDs, engine.EngineID)
+	}
+
+	return report, nil
+}
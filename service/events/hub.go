@@ -0,0 +1,270 @@
+package events
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	pollBatch = 100
+
+	// MaxReplay bounds how many missed events are replayed to a
+	// resuming subscriber. A subscriber that missed more, or whose last
+	// event is no longer in the outbox, is sent a reset instead.
+	MaxReplay = 1000
+
+	// subscriberBuffer is how many events a subscriber can fall behind
+	// before it is switched back to replaying from the outbox.
+	subscriberBuffer = 256
+)
+
+var subscribers = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "event_stream_subscribers",
+		Help: "Number of clients connected to the event stream of this process",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(subscribers)
+}
+
+// Hub fans published events out to event stream subscribers. It reads
+// them back from the outbox rather than being fed by the relay, so every
+// instance sees every event, whichever instance relayed it, and resuming
+// subscribers can be replayed the events they missed.
+type Hub struct {
+	store store.OutboxStoreInterface
+
+	mu          sync.Mutex
+	subscribers map[*subscription]struct{}
+}
+
+// subscription receives live events. events is closed when the
+// subscriber falls behind.
+type subscription struct {
+	events chan models.Event
+}
+
+func NewHub(store store.OutboxStoreInterface) *Hub {
+	return &Hub{
+		store:       store,
+		subscribers: map[*subscription]struct{}{},
+	}
+}
+
+// Run passes newly published events to subscribers until ctx is
+// cancelled, checking for new ones every interval.
+func (h *Hub) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var cursor *models.EventCursor
+	for {
+		if cursor == nil {
+			latest, err := h.store.LatestCursor(ctx)
+			if err != nil {
+				log.Println("Error reading the event stream position : ", err)
+			} else {
+				cursor = &latest
+			}
+		}
+
+		for cursor != nil && ctx.Err() == nil {
+			events, err := h.store.ListPublished(ctx, *cursor, pollBatch)
+			if err != nil {
+				log.Println("Error reading published events : ", err)
+				break
+			}
+
+			for _, event := range events {
+				h.broadcast(event)
+				*cursor = event.Cursor()
+			}
+			if len(events) < pollBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) broadcast(event models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+func (h *Hub) subscribe() *subscription {
+	sub := &subscription{events: make(chan models.Event, subscriberBuffer)}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+func (h *Hub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Stream passes the events selected by filter to send until ctx is done or
+// send fails. With a lastEventID, the events published after that one are
+// replayed first; without one, only new events are sent. Events are sent
+// in the order they were published, so each car's and engine's events
+// arrive in order.
+func (h *Hub) Stream(ctx context.Context, filter models.EventStreamFilter, lastEventID string, send func(models.Event) error) error {
+	subscribers.Inc()
+	defer subscribers.Dec()
+
+	// cursor is the last event seen, sent or not. replay is set while
+	// events after it have to be read from the outbox.
+	var cursor models.EventCursor
+	replay := false
+
+	if lastEventID != "" {
+		resumed, err := h.resumeCursor(ctx, lastEventID)
+		switch {
+		case err == nil:
+			cursor = resumed
+			replay = true
+		case errors.Is(err, models.ErrEventNotFound):
+			if err := send(models.Event{Type: models.EventStreamReset}); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+
+	for {
+		sub := h.subscribe()
+
+		if replay {
+			var err error
+			cursor, err = h.replay(ctx, filter, cursor, send)
+			if err != nil {
+				h.unsubscribe(sub)
+				return err
+			}
+		}
+
+		err := h.forward(ctx, sub, filter, &cursor, send)
+		h.unsubscribe(sub)
+		if err != nil {
+			return err
+		}
+
+		// The subscriber fell behind; catch up from the outbox, unless it
+		// has not seen any event to catch up from.
+		replay = cursor.Sequence != 0
+		if !replay {
+			if err := send(models.Event{Type: models.EventStreamReset}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (h *Hub) resumeCursor(ctx context.Context, lastEventID string) (models.EventCursor, error) {
+	sequence, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil {
+		return models.EventCursor{}, models.ErrEventNotFound
+	}
+	return h.store.PublishedCursor(ctx, sequence)
+}
+
+// replay sends the events published after cursor and returns the cursor
+// of the last one. If more than MaxReplay are waiting, a reset is sent and
+// the returned cursor is zero, so every live event is sent from then on.
+func (h *Hub) replay(ctx context.Context, filter models.EventStreamFilter, cursor models.EventCursor, send func(models.Event) error) (models.EventCursor, error) {
+	tracer := otel.Tracer("EventHub")
+	ctx, span := tracer.Start(ctx, "ReplayEvents-Hub")
+	defer span.End()
+
+	replayed := 0
+	for {
+		events, err := h.store.ListPublished(ctx, cursor, pollBatch)
+		if err != nil {
+			return cursor, err
+		}
+
+		if replayed+len(events) > MaxReplay {
+			span.SetAttributes(attribute.Bool("events.reset", true))
+			return models.EventCursor{}, send(models.Event{Type: models.EventStreamReset})
+		}
+
+		for _, event := range events {
+			cursor = event.Cursor()
+			if !filter.Matches(event) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return cursor, err
+			}
+		}
+
+		replayed += len(events)
+		span.SetAttributes(attribute.Int("events.replayed", replayed))
+
+		if len(events) < pollBatch {
+			return cursor, nil
+		}
+	}
+}
+
+// forward sends live events after cursor until ctx is done, send fails or
+// the subscription is dropped for falling behind, which returns nil.
+func (h *Hub) forward(ctx context.Context, sub *subscription, filter models.EventStreamFilter, cursor *models.EventCursor, send func(models.Event) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-sub.events:
+			if !ok {
+				return nil
+			}
+			// Events already replayed can also arrive live.
+			if !event.Cursor().After(*cursor) {
+				continue
+			}
+			*cursor = event.Cursor()
+			if !filter.Matches(event) {
+				continue
+			}
+			if err := send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
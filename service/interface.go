@@ -3,19 +3,88 @@ package service
 import (
 	"Car-Management-System/models"
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type CarServiceInterface interface {
-	GetCarById(ctx context.Context, id string) (*models.Car, error)
-	GetCarsByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error)
+	GetCarById(ctx context.Context, id string, includeDeleted bool) (*models.Car, error)
+	GetCarByVIN(ctx context.Context, vin string, includeDeleted bool) (*models.Car, error)
 	CreateCar(ctx context.Context, car *models.CarRequest) (*models.Car, error)
-	UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (*models.Car, error)
-	DeleteCar(ctx context.Context, id string) (*models.Car, error)
+	ImportCars(ctx context.Context, rows []models.CarImportRow, dryRun bool) (*models.ImportReport, error)
+	UpdateCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (*models.Car, error)
+	PatchCar(ctx context.Context, id string, patch *models.Patch, expectedVersion int64) (*models.Car, error)
+	DeleteCar(ctx context.Context, id string, expectedVersion int64) (*models.Car, error)
+	ListCars(ctx context.Context, filter *models.CarFilter) (*models.CarPage, error)
+	ExportCars(ctx context.Context, filter *models.CarFilter, fn func(models.Car) error) error
+	GetCarHistory(ctx context.Context, id string) ([]models.CarHistory, error)
+	GetCarAsOf(ctx context.Context, id string, asOf time.Time) (*models.Car, error)
+	RestoreCar(ctx context.Context, id string) (*models.Car, error)
+	PurgeCar(ctx context.Context, id string) error
 }
 
 type EngineServiceInterface interface {
-	GetEngineById(ctx context.Context, id string) (*models.Engine, error)
+	GetEngineById(ctx context.Context, id string, includeDeleted bool) (*models.Engine, error)
+	GetEnginesByIds(ctx context.Context, ids []uuid.UUID, includeDeleted bool) (map[uuid.UUID]models.Engine, error)
 	CreateEngine(ctx context.Context, engineReq *models.EngineRequest) (*models.Engine, error)
-	UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest) (*models.Engine, error)
-	DeleteEngine(ctx context.Context, id string) (*models.Engine, error)
+	ImportEngines(ctx context.Context, rows []models.EngineImportRow, dryRun bool) (*models.ImportReport, error)
+	UpdateEngine(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (*models.Engine, error)
+	PatchEngine(ctx context.Context, id string, patch *models.Patch, expectedVersion int64) (*models.Engine, error)
+	DeleteEngine(ctx context.Context, id string, expectedVersion int64) (*models.Engine, error)
+	ListEngines(ctx context.Context, filter *models.EngineFilter) (*models.EnginePage, error)
+	RestoreEngine(ctx context.Context, id string) (*models.Engine, error)
+	PurgeEngine(ctx context.Context, id string) error
+}
+
+type UserServiceInterface interface {
+	Authenticate(ctx context.Context, userName string, password string) (*models.User, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+	RegisterUser(ctx context.Context, userReq *models.UserRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, userName string, passwordReq *models.PasswordChangeRequest) error
+	DisableUser(ctx context.Context, id string) (*models.User, error)
+	EnableUser(ctx context.Context, id string) (*models.User, error)
+	SetUserRole(ctx context.Context, id string, roleReq *models.RoleRequest) (*models.User, error)
+	DeleteUser(ctx context.Context, id string) (*models.User, error)
+}
+
+type TokenServiceInterface interface {
+	IssueTokens(ctx context.Context, user *models.User) (*models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	Logout(ctx context.Context, jti string, expiresAt time.Time, refreshToken string) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type APIKeyServiceInterface interface {
+	CreateAPIKey(ctx context.Context, userName string, role string, keyReq *models.APIKeyRequest) (*models.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context, userName string) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userName string, role string, id string) (*models.APIKey, error)
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error)
+}
+
+type JobServiceInterface interface {
+	CreateJob(ctx context.Context, userName string, role string, jobReq *models.JobRequest) (*models.Job, error)
+	GetJob(ctx context.Context, userName string, role string, id string) (*models.Job, error)
+	CancelJob(ctx context.Context, userName string, role string, id string) (*models.Job, error)
+	JobResultPath(ctx context.Context, userName string, role string, id string) (string, error)
+}
+
+// EventPublisher is told about every committed change of a car or an
+// engine.
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
+
+type WebhookServiceInterface interface {
+	CreateWebhook(ctx context.Context, userName string, webhookReq *models.WebhookRequest) (*models.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, webhookReq *models.WebhookRequest) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	ListDeadLetters(ctx context.Context, webhookID string) ([]models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+}
+
+type EventStreamServiceInterface interface {
+	Stream(ctx context.Context, filter models.EventStreamFilter, lastEventID string, send func(models.Event) error) error
 }
@@ -0,0 +1,118 @@
+package job
+
+import (
+	"Car-Management-System/jobs"
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const fileLocationPrefix = "file://"
+
+type JobService struct {
+	store store.JobStoreInterface
+	pool  *jobs.Pool
+}
+
+func NewJobService(store store.JobStoreInterface, pool *jobs.Pool) *JobService {
+	return &JobService{
+		store: store,
+		pool:  pool,
+	}
+}
+
+// CreateJob queues a job for the pool's workers to pick up.
+func (s *JobService) CreateJob(ctx context.Context, userName string, role string, jobReq *models.JobRequest) (*models.Job, error) {
+	tracer := otel.Tracer("JobService")
+	ctx, span := tracer.Start(ctx, "CreateJob-Service")
+	defer span.End()
+
+	jobType := strings.TrimSpace(jobReq.Type)
+	params := jobReq.Params
+	if len(bytes.TrimSpace(params)) == 0 || bytes.Equal(bytes.TrimSpace(params), []byte("null")) {
+		params = json.RawMessage("{}")
+	}
+
+	if err := s.pool.Validate(jobType, role, params); err != nil {
+		return nil, err
+	}
+
+	job := models.Job{
+		ID:        uuid.New(),
+		Type:      jobType,
+		Status:    models.JobQueued,
+		Params:    params,
+		CreatedBy: userName,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.store.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetJob returns a job to the user who created it, or to an admin.
+func (s *JobService) GetJob(ctx context.Context, userName string, role string, id string) (*models.Job, error) {
+	tracer := otel.Tracer("JobService")
+	ctx, span := tracer.Start(ctx, "GetJob-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrJobNotFound
+	}
+
+	job, err := s.store.GetJobById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.CreatedBy != userName && role != models.RoleAdmin {
+		return nil, models.ErrJobForbidden
+	}
+
+	return &job, nil
+}
+
+func (s *JobService) CancelJob(ctx context.Context, userName string, role string, id string) (*models.Job, error) {
+	tracer := otel.Tracer("JobService")
+	ctx, span := tracer.Start(ctx, "CancelJob-Service")
+	defer span.End()
+
+	if _, err := s.GetJob(ctx, userName, role, id); err != nil {
+		return nil, err
+	}
+
+	job, err := s.store.CancelJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// JobResultPath returns the local file holding a finished job's result.
+func (s *JobService) JobResultPath(ctx context.Context, userName string, role string, id string) (string, error) {
+	tracer := otel.Tracer("JobService")
+	ctx, span := tracer.Start(ctx, "JobResultPath-Service")
+	defer span.End()
+
+	job, err := s.GetJob(ctx, userName, role, id)
+	if err != nil {
+		return "", err
+	}
+
+	if job.Status != models.JobSucceeded || !strings.HasPrefix(job.ResultLocation, fileLocationPrefix) {
+		return "", models.ErrJobResultUnavailable
+	}
+
+	return strings.TrimPrefix(job.ResultLocation, fileLocationPrefix), nil
+}
@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/service"
+	"Car-Management-System/store"
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	relayBatch      = 100
+	cleanupInterval = time.Hour
+)
+
+var (
+	relayLag = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "outbox_relay_lag_seconds",
+			Help: "Age of the oldest outbox event that has not been published yet, 0 when none are waiting",
+		},
+	)
+
+	pendingEvents = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "outbox_pending_events",
+			Help: "Number of outbox events that have not been published yet",
+		},
+	)
+
+	publishedEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_events_published_total",
+			Help: "Total number of outbox events published by this process by type",
+		},
+		[]string{
+			"type",
+		},
+	)
+
+	publishFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "outbox_publish_failures_total",
+			Help: "Total number of failed attempts to publish an outbox event",
+		},
+	)
+
+	publishDelay = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "outbox_publish_delay_seconds",
+			Help:    "Time from an event happening to it being published",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30, 60, 300},
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(relayLag, pendingEvents, publishedEvents, publishFailures, publishDelay)
+}
+
+// Relay publishes the events written to the outbox. Every event goes to
+// each of its publishers in turn and is only marked as published once all
+// of them accepted it, so delivery is at least once: after a failure, the
+// publishers that already had the event get it again.
+type Relay struct {
+	store      store.OutboxStoreInterface
+	publishers []service.EventPublisher
+}
+
+func NewRelay(store store.OutboxStoreInterface, publishers ...service.EventPublisher) *Relay {
+	return &Relay{
+		store:      store,
+		publishers: publishers,
+	}
+}
+
+// RelayPending publishes the oldest waiting events and returns how many
+// were published.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	tracer := otel.Tracer("OutboxRelay")
+	ctx, span := tracer.Start(ctx, "RelayPending-Relay")
+	defer span.End()
+
+	published, err := r.store.RelayPending(ctx, relayBatch, func(event models.Event) error {
+		for _, publisher := range r.publishers {
+			if err := publisher.Publish(ctx, event); err != nil {
+				publishFailures.Inc()
+				log.Printf("Error publishing %s event %s : %v", event.Type, event.ID, err)
+				return err
+			}
+		}
+
+		publishedEvents.WithLabelValues(event.Type).Inc()
+		publishDelay.Observe(time.Since(event.OccurredAt).Seconds())
+		return nil
+	})
+
+	span.SetAttributes(attribute.Int("outbox.published", published))
+	return published, err
+}
+
+// Run relays waiting events until ctx is cancelled, checking for new ones
+// every interval. Published events are kept for retention before they are
+// removed; a retention of 0 keeps them.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		for ctx.Err() == nil {
+			published, err := r.RelayPending(ctx)
+			if err != nil {
+				log.Println("Error relaying outbox events : ", err)
+			}
+			if err != nil || published < relayBatch {
+				break
+			}
+		}
+
+		r.updateLag(ctx)
+
+		if retention > 0 && time.Since(lastCleanup) >= cleanupInterval {
+			lastCleanup = time.Now()
+			if _, err := r.store.DeletePublished(ctx, time.Now().Add(-retention)); err != nil {
+				log.Println("Error removing published outbox events : ", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) updateLag(ctx context.Context) {
+	pending, oldest, err := r.store.PendingStats(ctx)
+	if err != nil {
+		log.Println("Error reading outbox lag : ", err)
+		return
+	}
+
+	pendingEvents.Set(float64(pending))
+	if oldest == nil {
+		relayLag.Set(0)
+		return
+	}
+	relayLag.Set(time.Since(*oldest).Seconds())
+}
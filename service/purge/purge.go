@@ -0,0 +1,65 @@
+package purge
+
+import (
+	"Car-Management-System/store"
+	"context"
+	"log"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+type PurgeService struct {
+	cars    store.CarStoreInterface
+	engines store.EngineStoreInterface
+}
+
+func NewPurgeService(cars store.CarStoreInterface, engines store.EngineStoreInterface) *PurgeService {
+	return &PurgeService{
+		cars:    cars,
+		engines: engines,
+	}
+}
+
+// PurgeDeleted hard-deletes cars and engines that were soft-deleted more
+// than retention ago and returns how many of each were removed.
+func (s *PurgeService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, int64, error) {
+	tracer := otel.Tracer("PurgeService")
+	ctx, span := tracer.Start(ctx, "PurgeDeleted-Service")
+	defer span.End()
+
+	olderThan := time.Now().Add(-retention)
+
+	cars, err := s.cars.PurgeDeletedCars(ctx, olderThan)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	engines, err := s.engines.PurgeDeletedEngines(ctx, olderThan)
+	if err != nil {
+		return cars, 0, err
+	}
+
+	return cars, engines, nil
+}
+
+// Run calls PurgeDeleted every interval until ctx is cancelled.
+func (s *PurgeService) Run(ctx context.Context, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cars, engines, err := s.PurgeDeleted(ctx, retention)
+		if err != nil {
+			log.Println("Error purging deleted rows: ", err)
+		} else if cars > 0 || engines > 0 {
+			log.Printf("Purged %d deleted car(s) and %d deleted engine(s)", cars, engines)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package token
+
+import (
+	"Car-Management-System/keys"
+	"Car-Management-System/middleware"
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+type TokenService struct {
+	store  store.TokenStoreInterface
+	users  store.UserStoreInterface
+	keySet *keys.KeySet
+}
+
+func NewTokenService(store store.TokenStoreInterface, users store.UserStoreInterface, keySet *keys.KeySet) *TokenService {
+	return &TokenService{
+		store:  store,
+		users:  users,
+		keySet: keySet,
+	}
+}
+
+func (s *TokenService) IssueTokens(ctx context.Context, user *models.User) (*models.TokenPair, error) {
+	tracer := otel.Tracer("TokenService")
+	ctx, span := tracer.Start(ctx, "IssueTokens-Service")
+	defer span.End()
+
+	pair, refreshToken, err := s.newTokenPair(user, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.CreateRefreshToken(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+func (s *TokenService) Refresh(ctx context.Context, rawRefreshToken string) (*models.TokenPair, error) {
+	tracer := otel.Tracer("TokenService")
+	ctx, span := tracer.Start(ctx, "Refresh-Service")
+	defer span.End()
+
+	current, err := s.store.GetRefreshToken(ctx, hashToken(rawRefreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		if err := s.store.RevokeFamily(ctx, current.FamilyID.String()); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.GetUserById(ctx, current.UserID.String())
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			return nil, models.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if user.Disabled {
+		if err := s.store.RevokeFamily(ctx, current.FamilyID.String()); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrUserDisabled
+	}
+
+	pair, next, err := s.newTokenPair(&user, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.RotateRefreshToken(ctx, current.ID.String(), next)
+	if err != nil {
+		if errors.Is(err, models.ErrRefreshTokenReused) {
+			if rvErr := s.store.RevokeFamily(ctx, current.FamilyID.String()); rvErr != nil {
+				return nil, rvErr
+			}
+		}
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+func (s *TokenService) Logout(ctx context.Context, jti string, expiresAt time.Time, rawRefreshToken string) error {
+	tracer := otel.Tracer("TokenService")
+	ctx, span := tracer.Start(ctx, "Logout-Service")
+	defer span.End()
+
+	if err := s.store.RevokeJTI(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	if rawRefreshToken == "" {
+		return nil
+	}
+
+	current, err := s.store.GetRefreshToken(ctx, hashToken(rawRefreshToken))
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+
+	return s.store.RevokeFamily(ctx, current.FamilyID.String())
+}
+
+func (s *TokenService) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	tracer := otel.Tracer("TokenService")
+	ctx, span := tracer.Start(ctx, "IsRevoked-Service")
+	defer span.End()
+
+	return s.store.IsRevoked(ctx, jti)
+}
+
+func (s *TokenService) newTokenPair(user *models.User, familyID uuid.UUID) (*models.TokenPair, models.RefreshToken, error) {
+	now := time.Now()
+	jti := uuid.New().String()
+	accessExpiresAt := now.Add(AccessTokenTTL)
+
+	accessToken, err := s.generateAccessToken(user, jti, now, accessExpiresAt)
+	if err != nil {
+		return nil, models.RefreshToken{}, err
+	}
+
+	rawRefreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, models.RefreshToken{}, err
+	}
+
+	refreshToken := models.RefreshToken{
+		ID:              uuid.New(),
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hashToken(rawRefreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.Add(RefreshTokenTTL),
+		CreatedAt:       now,
+	}
+
+	pair := &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(AccessTokenTTL.Seconds()),
+	}
+
+	return pair, refreshToken, nil
+}
+
+func (s *TokenService) generateAccessToken(user *models.User, jti string, issuedAt time.Time, expiresAt time.Time) (string, error) {
+	claims := &middleware.Claims{
+		UserName: user.UserName,
+		Role:     user.Role,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			ExpiresAt: expiresAt.Unix(),
+			IssuedAt:  issuedAt.Unix(),
+			Subject:   user.UserName,
+		},
+	}
+
+	return s.keySet.Sign(claims)
+}
+
+func generateRefreshToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
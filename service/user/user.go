@@ -0,0 +1,193 @@
+package user
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyHash is compared against when the username does not exist so that
+// unknown and known usernames take the same time to reject.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type UserService struct {
+	store store.UserStoreInterface
+}
+
+func NewUserService(store store.UserStoreInterface) *UserService {
+	return &UserService{
+		store: store,
+	}
+}
+
+func (s *UserService) Authenticate(ctx context.Context, userName string, password string) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "Authenticate-Service")
+	defer span.End()
+
+	user, err := s.store.GetUserByUsername(ctx, userName)
+	if err != nil {
+		if errors.Is(err, models.ErrUserNotFound) {
+			_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+			return nil, models.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, models.ErrInvalidCredentials
+	}
+
+	if user.Disabled {
+		return nil, models.ErrUserDisabled
+	}
+
+	return &user, nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context) ([]models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "ListUsers-Service")
+	defer span.End()
+
+	users, err := s.store.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (s *UserService) RegisterUser(ctx context.Context, userReq *models.UserRequest) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "RegisterUser-Service")
+	defer span.End()
+
+	if userReq.Role == "" {
+		userReq.Role = models.RoleViewer
+	}
+
+	if err := models.ValidateUserRequest(*userReq); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidUserRequest, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(userReq.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	createdUser, err := s.store.CreateUser(ctx, userReq.UserName, string(hash), userReq.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createdUser, nil
+}
+
+// EnsureUser registers userReq unless a user with its name exists, and
+// reports whether it did. An existing user keeps its password and its
+// role, unless it was created before roles existed: its viewer role is
+// only a default, and is replaced by the role of userReq so that it does
+// not lock out the admin. A role an admin has since chosen, such as a
+// demotion, is kept across restarts.
+func (s *UserService) EnsureUser(ctx context.Context, userReq *models.UserRequest) (bool, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "EnsureUser-Service")
+	defer span.End()
+
+	user, err := s.store.GetUserByUsername(ctx, userReq.UserName)
+	if err == nil {
+		if userReq.Role == "" || user.RoleAssigned || user.Role == userReq.Role {
+			return false, nil
+		}
+		_, err = s.store.SetUserRole(ctx, user.ID.String(), userReq.Role)
+		return false, err
+	}
+	if !errors.Is(err, models.ErrUserNotFound) {
+		return false, err
+	}
+
+	_, err = s.RegisterUser(ctx, userReq)
+	if errors.Is(err, models.ErrUserExists) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *UserService) ChangePassword(ctx context.Context, userName string, passwordReq *models.PasswordChangeRequest) error {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "ChangePassword-Service")
+	defer span.End()
+
+	user, err := s.Authenticate(ctx, userName, passwordReq.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	if err := models.ValidatePassword(passwordReq.NewPassword); err != nil {
+		return fmt.Errorf("%w: %v", models.ErrInvalidUserRequest, err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(passwordReq.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.store.UpdateUserPassword(ctx, user.ID.String(), string(hash))
+}
+
+func (s *UserService) DisableUser(ctx context.Context, id string) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "DisableUser-Service")
+	defer span.End()
+
+	user, err := s.store.SetUserDisabled(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserService) EnableUser(ctx context.Context, id string) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "EnableUser-Service")
+	defer span.End()
+
+	user, err := s.store.SetUserDisabled(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserService) SetUserRole(ctx context.Context, id string, roleReq *models.RoleRequest) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "SetUserRole-Service")
+	defer span.End()
+
+	if err := models.ValidateRole(roleReq.Role); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidUserRequest, err)
+	}
+
+	user, err := s.store.SetUserRole(ctx, id, roleReq.Role)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, id string) (*models.User, error) {
+	tracer := otel.Tracer("UserService")
+	ctx, span := tracer.Start(ctx, "DeleteUser-Service")
+	defer span.End()
+
+	deletedUser, err := s.store.DeleteUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &deletedUser, nil
+}
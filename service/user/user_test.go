@@ -0,0 +1,130 @@
+package user
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// memoryStore keeps users in a map, enough for EnsureUser.
+type memoryStore struct {
+	store.UserStoreInterface
+	users map[string]models.User
+}
+
+func (m *memoryStore) GetUserByUsername(ctx context.Context, userName string) (models.User, error) {
+	user, ok := m.users[userName]
+	if !ok {
+		return user, models.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (m *memoryStore) CreateUser(ctx context.Context, userName string, passwordHash string, role string) (models.User, error) {
+	if _, ok := m.users[userName]; ok {
+		return models.User{}, models.ErrUserExists
+	}
+	user := models.User{ID: uuid.New(), UserName: userName, PasswordHash: passwordHash, Role: role, RoleAssigned: true}
+	m.users[userName] = user
+	return user, nil
+}
+
+func (m *memoryStore) SetUserRole(ctx context.Context, id string, role string) (models.User, error) {
+	for name, user := range m.users {
+		if user.ID.String() == id {
+			user.Role = role
+			user.RoleAssigned = true
+			m.users[name] = user
+			return user, nil
+		}
+	}
+	return models.User{}, models.ErrUserNotFound
+}
+
+func TestEnsureUser(t *testing.T) {
+	admin := &models.UserRequest{UserName: "admin", Password: "correct-horse-battery", Role: models.RoleAdmin}
+
+	tests := []struct {
+		name     string
+		existing *models.User
+		created  bool
+		role     string
+	}{
+		{
+			name:    "missing user is created",
+			created: true,
+			role:    models.RoleAdmin,
+		},
+		{
+			name:     "account from before roles is promoted",
+			existing: &models.User{Role: models.RoleViewer},
+			role:     models.RoleAdmin,
+		},
+		{
+			name:     "admin demoted to viewer stays a viewer",
+			existing: &models.User{Role: models.RoleViewer, RoleAssigned: true},
+			role:     models.RoleViewer,
+		},
+		{
+			name:     "admin demoted to editor stays an editor",
+			existing: &models.User{Role: models.RoleEditor, RoleAssigned: true},
+			role:     models.RoleEditor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := &memoryStore{users: map[string]models.User{}}
+			if tt.existing != nil {
+				existing := *tt.existing
+				existing.ID = uuid.New()
+				existing.UserName = admin.UserName
+				users.users[admin.UserName] = existing
+			}
+			service := NewUserService(users)
+
+			for restart := 0; restart < 2; restart++ {
+				req := *admin
+				created, err := service.EnsureUser(context.Background(), &req)
+				if err != nil {
+					t.Fatalf("EnsureUser() error = %v", err)
+				}
+				if want := tt.created && restart == 0; created != want {
+					t.Fatalf("EnsureUser() on start %d created = %v, want %v", restart+1, created, want)
+				}
+			}
+
+			if got := users.users[admin.UserName].Role; got != tt.role {
+				t.Fatalf("role = %q, want %q", got, tt.role)
+			}
+		})
+	}
+}
+
+func TestEnsureUserKeepsDemotionAcrossRestarts(t *testing.T) {
+	users := &memoryStore{users: map[string]models.User{}}
+	service := NewUserService(users)
+	admin := models.UserRequest{UserName: "admin", Password: "correct-horse-battery", Role: models.RoleAdmin}
+
+	req := admin
+	if _, err := service.EnsureUser(context.Background(), &req); err != nil {
+		t.Fatalf("EnsureUser() error = %v", err)
+	}
+
+	created := users.users[admin.UserName]
+	if _, err := users.SetUserRole(context.Background(), created.ID.String(), models.RoleViewer); err != nil {
+		t.Fatalf("SetUserRole() error = %v", err)
+	}
+
+	req = admin
+	if _, err := service.EnsureUser(context.Background(), &req); err != nil {
+		t.Fatalf("EnsureUser() after restart error = %v", err)
+	}
+
+	if got := users.users[admin.UserName].Role; got != models.RoleViewer {
+		t.Fatalf("role after restart = %q, want %q", got, models.RoleViewer)
+	}
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// MaxAttempts is how often a delivery is tried before it is moved to
+	// the dead-letter table.
+	MaxAttempts = 8
+
+	initialBackoff  = 30 * time.Second
+	maxBackoff      = time.Hour
+	deliveryTimeout = 10 * time.Second
+
+	// deliveryLease must outlast deliveryTimeout, or a slow delivery could
+	// be claimed again while it is still being sent.
+	deliveryLease = time.Minute
+	claimBatch    = 20
+
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Dispatcher sends queued deliveries to their webhooks. Any number of
+// dispatchers can share the queue.
+type Dispatcher struct {
+	store  store.WebhookStoreInterface
+	client *http.Client
+}
+
+func NewDispatcher(store store.WebhookStoreInterface) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		client: &http.Client{Timeout: deliveryTimeout},
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled, checking for new ones
+// every interval.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			sent, err := d.DispatchDue(ctx)
+			if err != nil {
+				log.Println("Error dispatching webhooks : ", err)
+			}
+			if err != nil || sent < claimBatch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue claims one batch of due deliveries, sends them concurrently
+// and returns how many it claimed.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDeliveries(ctx, claimBatch, time.Now().Add(deliveryLease))
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery models.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	tracer := otel.Tracer("WebhookDispatcher")
+	ctx, span := tracer.Start(ctx, "DeliverWebhook-Dispatcher", trace.WithAttributes(
+		attribute.String("webhook.id", delivery.WebhookID.String()),
+		attribute.String("event.id", delivery.EventID.String()),
+		attribute.String("event.type", delivery.EventType),
+		attribute.Int("webhook.attempt", delivery.Attempts),
+	))
+	defer span.End()
+
+	sendErr := d.send(ctx, delivery)
+
+	var err error
+	switch {
+	case sendErr == nil:
+		err = d.store.DeleteDelivery(ctx, delivery.ID)
+	case delivery.Attempts >= MaxAttempts:
+		span.RecordError(sendErr)
+		span.SetStatus(codes.Error, sendErr.Error())
+		err = d.store.DeadLetterDelivery(ctx, delivery.ID, sendErr.Error())
+	default:
+		span.RecordError(sendErr)
+		err = d.store.RetryDelivery(ctx, delivery.ID, time.Now().Add(Backoff(delivery.Attempts)), sendErr.Error())
+	}
+
+	if err != nil {
+		log.Println("Error recording webhook delivery : ", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Car-Management-System-Webhooks")
+	req.Header.Set("X-Webhook-Id", delivery.EventID.String())
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(delivery.Attempts))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, time.Now(), delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the signature header value for body: the Unix timestamp and
+// the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with secret, as
+// "t=<timestamp>,v1=<hmac>". Receivers recompute the HMAC to check that
+// the payload came from us, and check the timestamp to reject replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is how long to wait after the given failed attempt: 30s doubled
+// for each attempt, capped at an hour, plus up to 10% jitter so that
+// deliveries that failed together do not retry together.
+func Backoff(attempt int) time.Duration {
+	backoff := maxBackoff
+	if attempt < 20 {
+		backoff = initialBackoff << (attempt - 1)
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return backoff + time.Duration(rand.Int63n(int64(backoff)/10+1))
+}
@@ -0,0 +1,464 @@
+package webhook
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "whsec_test"
+
+// memoryStore keeps deliveries and dead letters in memory, claiming and
+// moving them the way WebhookStore does in SQL.
+type memoryStore struct {
+	mu          sync.Mutex
+	url         string
+	nextID      int64
+	deliveries  map[int64]*models.WebhookDelivery
+	lastErrors  map[int64]string
+	deadLetters []models.DeadLetter
+}
+
+func newMemoryStore(url string) *memoryStore {
+	return &memoryStore{
+		url:        url,
+		deliveries: map[int64]*models.WebhookDelivery{},
+		lastErrors: map[int64]string{},
+	}
+}
+
+func (m *memoryStore) enqueue(eventType string, payload string) *models.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	delivery := &models.WebhookDelivery{
+		ID:            m.nextID,
+		WebhookID:     uuid.New(),
+		URL:           m.url,
+		Secret:        testSecret,
+		EventID:       uuid.New(),
+		EventType:     eventType,
+		Payload:       []byte(payload),
+		NextAttemptAt: time.Now(),
+	}
+	m.deliveries[delivery.ID] = delivery
+	return delivery
+}
+
+// makeDue lets every queued delivery be claimed again straight away.
+func (m *memoryStore) makeDue() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, delivery := range m.deliveries {
+		delivery.NextAttemptAt = time.Now()
+	}
+}
+
+func (m *memoryStore) delivery(id int64) (models.WebhookDelivery, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery, ok := m.deliveries[id]
+	if !ok {
+		return models.WebhookDelivery{}, false
+	}
+	return *delivery, true
+}
+
+func (m *memoryStore) CreateWebhook(ctx context.Context, webhook models.Webhook) error {
+	return errors.New("not implemented")
+}
+
+func (m *memoryStore) GetWebhookById(ctx context.Context, id string) (models.Webhook, error) {
+	return models.Webhook{}, errors.New("not implemented")
+}
+
+func (m *memoryStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *memoryStore) UpdateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	return models.Webhook{}, errors.New("not implemented")
+}
+
+func (m *memoryStore) DeleteWebhook(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (m *memoryStore) EnqueueDeliveries(ctx context.Context, event models.Event) (int64, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *memoryStore) ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var claimed []models.WebhookDelivery
+	for id := int64(1); id <= m.nextID && len(claimed) < limit; id++ {
+		delivery, ok := m.deliveries[id]
+		if !ok || delivery.NextAttemptAt.After(time.Now()) {
+			continue
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (m *memoryStore) DeleteDelivery(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.deliveries, id)
+	return nil
+}
+
+func (m *memoryStore) RetryDelivery(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deliveries[id].NextAttemptAt = nextAttemptAt
+	m.lastErrors[id] = lastError
+	return nil
+}
+
+func (m *memoryStore) DeadLetterDelivery(ctx context.Context, id int64, lastError string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivery := m.deliveries[id]
+	delete(m.deliveries, id)
+	m.deadLetters = append(m.deadLetters, models.DeadLetter{
+		ID:        int64(len(m.deadLetters) + 1),
+		WebhookID: delivery.WebhookID,
+		EventID:   delivery.EventID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+		Attempts:  delivery.Attempts,
+		LastError: lastError,
+		FailedAt:  time.Now(),
+	})
+	return nil
+}
+
+func (m *memoryStore) ListDeadLetters(ctx context.Context, webhookID string) ([]models.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]models.DeadLetter(nil), m.deadLetters...), nil
+}
+
+func (m *memoryStore) ReplayDeadLetter(ctx context.Context, id int64) (models.DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id < 1 || id > int64(len(m.deadLetters)) {
+		return models.DeadLetter{}, models.ErrDeadLetterNotFound
+	}
+
+	now := time.Now()
+	deadLetter := &m.deadLetters[id-1]
+	deadLetter.ReplayedAt = &now
+
+	m.nextID++
+	m.deliveries[m.nextID] = &models.WebhookDelivery{
+		ID:            m.nextID,
+		WebhookID:     deadLetter.WebhookID,
+		URL:           m.url,
+		Secret:        testSecret,
+		EventID:       deadLetter.EventID,
+		EventType:     deadLetter.EventType,
+		Payload:       deadLetter.Payload,
+		NextAttemptAt: now,
+	}
+	return *deadLetter, nil
+}
+
+// verify checks a signature header the way a receiver would.
+func verify(header string, secret string, body []byte, maxAge time.Duration) error {
+	timestamp, signature, ok := strings.Cut(header, ",")
+	if !ok || !strings.HasPrefix(timestamp, "t=") || !strings.HasPrefix(signature, "v1=") {
+		return errors.New("malformed signature header")
+	}
+
+	unix, err := strconv.ParseInt(strings.TrimPrefix(timestamp, "t="), 10, 64)
+	if err != nil {
+		return errors.New("malformed timestamp")
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > maxAge || age < -maxAge {
+		return errors.New("timestamp out of range")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.TrimPrefix(timestamp, "t=") + "."))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "v1="))) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// receiver is a webhook endpoint that answers with status and records
+// what it was sent.
+type receiver struct {
+	status   atomic.Int32
+	requests atomic.Int32
+	mu       sync.Mutex
+	headers  []http.Header
+	errs     []error
+}
+
+func newReceiver(t *testing.T, status int) (*receiver, *httptest.Server) {
+	rec := &receiver{}
+	rec.status.Store(int32(status))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err == nil {
+			err = verify(r.Header.Get(SignatureHeader), testSecret, body, 5*time.Minute)
+		}
+
+		rec.requests.Add(1)
+		rec.mu.Lock()
+		rec.headers = append(rec.headers, r.Header.Clone())
+		rec.errs = append(rec.errs, err)
+		rec.mu.Unlock()
+
+		w.WriteHeader(int(rec.status.Load()))
+	}))
+	t.Cleanup(server.Close)
+
+	return rec, server
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"car.created"}`)
+	timestamp := time.Unix(1700000000, 0)
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "t=1700000000,v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign(testSecret, timestamp, body); got != want {
+		t.Fatalf("Sign() = %q, want %q", got, want)
+	}
+
+	header := Sign(testSecret, time.Now(), body)
+	tests := []struct {
+		name    string
+		secret  string
+		body    []byte
+		header  string
+		wantErr bool
+	}{
+		{name: "valid", secret: testSecret, body: body, header: header},
+		{name: "wrong secret", secret: "other", body: body, header: header, wantErr: true},
+		{name: "tampered body", secret: testSecret, body: []byte(`{"type":"car.deleted"}`), header: header, wantErr: true},
+		{name: "old timestamp", secret: testSecret, body: body, header: Sign(testSecret, time.Now().Add(-time.Hour), body), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verify(tt.header, tt.secret, tt.body, 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{attempt: 1, base: 30 * time.Second},
+		{attempt: 2, base: time.Minute},
+		{attempt: 3, base: 2 * time.Minute},
+		{attempt: 7, base: 32 * time.Minute},
+		{attempt: 8, base: time.Hour},
+		{attempt: 25, base: time.Hour},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			got := Backoff(tt.attempt)
+			if got < tt.base || got > tt.base+tt.base/10 {
+				t.Fatalf("Backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.base, tt.base+tt.base/10)
+			}
+		}
+	}
+}
+
+func TestDeliverSendsSignedRequest(t *testing.T) {
+	rec, server := newReceiver(t, http.StatusNoContent)
+	store := newMemoryStore(server.URL)
+	queued := store.enqueue(models.EventCarCreated, `{"id":"1"}`)
+
+	sent, err := NewDispatcher(store).DispatchDue(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("DispatchDue() = %d, %v, want 1, nil", sent, err)
+	}
+
+	if rec.requests.Load() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", rec.requests.Load())
+	}
+	if rec.errs[0] != nil {
+		t.Fatalf("signature did not verify: %v", rec.errs[0])
+	}
+
+	header := rec.headers[0]
+	for name, want := range map[string]string{
+		"X-Webhook-Id":      queued.EventID.String(),
+		"X-Webhook-Event":   models.EventCarCreated,
+		"X-Webhook-Attempt": "1",
+		"Content-Type":      "application/json",
+	} {
+		if got := header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	if _, ok := store.delivery(queued.ID); ok {
+		t.Fatal("delivered delivery is still queued")
+	}
+}
+
+func TestDeliverSchedulesRetry(t *testing.T) {
+	_, server := newReceiver(t, http.StatusInternalServerError)
+	store := newMemoryStore(server.URL)
+	queued := store.enqueue(models.EventCarUpdated, `{"id":"1"}`)
+
+	dispatcher := NewDispatcher(store)
+	for attempt := 1; attempt <= 3; attempt++ {
+		store.makeDue()
+		before := time.Now()
+		if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+
+		delivery, ok := store.delivery(queued.ID)
+		if !ok {
+			t.Fatalf("attempt %d: delivery was removed from the queue", attempt)
+		}
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt)
+		}
+
+		base := initialBackoff << (attempt - 1)
+		wait := delivery.NextAttemptAt.Sub(before)
+		if wait < base || wait > base+base/10+time.Second {
+			t.Fatalf("attempt %d: next attempt in %v, want about %v", attempt, wait, base)
+		}
+		if !strings.Contains(store.lastErrors[queued.ID], "500") {
+			t.Fatalf("last error = %q, want the response status", store.lastErrors[queued.ID])
+		}
+	}
+}
+
+func TestDeliverDeadLettersAfterMaxAttempts(t *testing.T) {
+	rec, server := newReceiver(t, http.StatusBadGateway)
+	store := newMemoryStore(server.URL)
+	queued := store.enqueue(models.EventCarDeleted, `{"id":"1"}`)
+
+	dispatcher := NewDispatcher(store)
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		store.makeDue()
+		if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+
+		_, queuedStill := store.delivery(queued.ID)
+		if attempt < MaxAttempts && !queuedStill {
+			t.Fatalf("delivery dead-lettered after %d attempts, want %d", attempt, MaxAttempts)
+		}
+	}
+
+	if _, ok := store.delivery(queued.ID); ok {
+		t.Fatalf("delivery is still queued after %d attempts", MaxAttempts)
+	}
+	if got := rec.requests.Load(); got != MaxAttempts {
+		t.Fatalf("receiver got %d requests, want %d", got, MaxAttempts)
+	}
+
+	deadLetters, _ := store.ListDeadLetters(context.Background(), "")
+	if len(deadLetters) != 1 {
+		t.Fatalf("got %d dead letters, want 1", len(deadLetters))
+	}
+	deadLetter := deadLetters[0]
+	if deadLetter.EventID != queued.EventID || deadLetter.Attempts != MaxAttempts {
+		t.Fatalf("dead letter = %+v, want event %s after %d attempts", deadLetter, queued.EventID, MaxAttempts)
+	}
+	if !strings.Contains(deadLetter.LastError, "502") {
+		t.Fatalf("last error = %q, want the response status", deadLetter.LastError)
+	}
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	rec, server := newReceiver(t, http.StatusServiceUnavailable)
+	store := newMemoryStore(server.URL)
+	queued := store.enqueue(models.EventEngineUpdated, `{"id":"2"}`)
+
+	dispatcher := NewDispatcher(store)
+	for attempt := 1; attempt <= MaxAttempts; attempt++ {
+		store.makeDue()
+		if _, err := dispatcher.DispatchDue(context.Background()); err != nil {
+			t.Fatalf("DispatchDue() error = %v", err)
+		}
+	}
+
+	service := NewWebhookService(store)
+	if _, err := service.ReplayDeadLetter(context.Background(), "not-a-number"); !errors.Is(err, models.ErrDeadLetterNotFound) {
+		t.Fatalf("ReplayDeadLetter(not-a-number) error = %v, want ErrDeadLetterNotFound", err)
+	}
+	if _, err := service.ReplayDeadLetter(context.Background(), "42"); !errors.Is(err, models.ErrDeadLetterNotFound) {
+		t.Fatalf("ReplayDeadLetter(42) error = %v, want ErrDeadLetterNotFound", err)
+	}
+
+	rec.status.Store(http.StatusOK)
+	replayed, err := service.ReplayDeadLetter(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("ReplayDeadLetter() error = %v", err)
+	}
+	if replayed.ReplayedAt == nil {
+		t.Fatal("replayed dead letter has no replayed_at")
+	}
+
+	sent, err := dispatcher.DispatchDue(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("DispatchDue() = %d, %v, want 1, nil", sent, err)
+	}
+
+	last := rec.headers[len(rec.headers)-1]
+	if got := last.Get("X-Webhook-Id"); got != queued.EventID.String() {
+		t.Fatalf("replayed X-Webhook-Id = %q, want %q", got, queued.EventID)
+	}
+	if got := last.Get("X-Webhook-Attempt"); got != "1" {
+		t.Fatalf("replayed X-Webhook-Attempt = %q, want a fresh count", got)
+	}
+	if err := rec.errs[len(rec.errs)-1]; err != nil {
+		t.Fatalf("replayed signature did not verify: %v", err)
+	}
+
+	if len(store.deliveries) != 0 {
+		t.Fatalf("%d deliveries still queued after the replay succeeded", len(store.deliveries))
+	}
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+const secretPrefix = "whsec_"
+
+type WebhookService struct {
+	store store.WebhookStoreInterface
+}
+
+func NewWebhookService(store store.WebhookStoreInterface) *WebhookService {
+	return &WebhookService{
+		store: store,
+	}
+}
+
+// CreateWebhook registers a subscription with a newly generated signing
+// secret, which is returned only this once.
+func (s *WebhookService) CreateWebhook(ctx context.Context, userName string, webhookReq *models.WebhookRequest) (*models.CreatedWebhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-Service")
+	defer span.End()
+
+	normalizeWebhookRequest(webhookReq)
+	if err := models.ValidateWebhookRequest(*webhookReq); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidWebhookRequest, err)
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := models.Webhook{
+		ID:        uuid.New(),
+		URL:       webhookReq.URL,
+		Events:    webhookReq.Events,
+		Active:    webhookReq.Active == nil || *webhookReq.Active,
+		Secret:    secret,
+		CreatedBy: userName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.store.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedWebhook{Webhook: webhook, Secret: secret}, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-Service")
+	defer span.End()
+
+	return s.store.ListWebhooks(ctx)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "GetWebhook-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, models.ErrWebhookNotFound
+	}
+
+	webhook, err := s.store.GetWebhookById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// UpdateWebhook replaces a webhook's URL and event filters. Active is left
+// as it is when the request does not set it. The secret never changes.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id string, webhookReq *models.WebhookRequest) (*models.Webhook, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-Service")
+	defer span.End()
+
+	normalizeWebhookRequest(webhookReq)
+	if err := models.ValidateWebhookRequest(*webhookReq); err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidWebhookRequest, err)
+	}
+
+	current, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current.URL = webhookReq.URL
+	current.Events = webhookReq.Events
+	if webhookReq.Active != nil {
+		current.Active = *webhookReq.Active
+	}
+
+	updatedWebhook, err := s.store.UpdateWebhook(ctx, *current)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updatedWebhook, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id string) error {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-Service")
+	defer span.End()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return models.ErrWebhookNotFound
+	}
+
+	return s.store.DeleteWebhook(ctx, id)
+}
+
+func (s *WebhookService) ListDeadLetters(ctx context.Context, webhookID string) ([]models.DeadLetter, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "ListDeadLetters-Service")
+	defer span.End()
+
+	if webhookID != "" {
+		if _, err := uuid.Parse(webhookID); err != nil {
+			return nil, models.ErrWebhookNotFound
+		}
+	}
+
+	return s.store.ListDeadLetters(ctx, webhookID)
+}
+
+func (s *WebhookService) ReplayDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "ReplayDeadLetter-Service")
+	defer span.End()
+
+	deadLetterID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, models.ErrDeadLetterNotFound
+	}
+
+	deadLetter, err := s.store.ReplayDeadLetter(ctx, deadLetterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deadLetter, nil
+}
+
+// Publish queues event for every webhook subscribed to it. The Dispatcher
+// delivers it from there.
+func (s *WebhookService) Publish(ctx context.Context, event models.Event) error {
+	tracer := otel.Tracer("WebhookService")
+	ctx, span := tracer.Start(ctx, "Publish-Service")
+	defer span.End()
+
+	_, err := s.store.EnqueueDeliveries(ctx, event)
+	return err
+}
+
+func normalizeWebhookRequest(webhookReq *models.WebhookRequest) {
+	webhookReq.URL = strings.TrimSpace(webhookReq.URL)
+	if webhookReq.Events == nil {
+		webhookReq.Events = []string{}
+	}
+}
+
+func generateSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+const lastUsedResolution = time.Minute
+
+const selectAPIKey = `SELECT k.id, k.user_id, u.username, k.name, k.prefix, k.key_hash, k.role, k.expires_at, k.last_used_at, k.revoked_at, k.created_at FROM api_keys k JOIN users u ON k.user_id = u.id`
+
+type APIKeyStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *APIKeyStore {
+	return &APIKeyStore{db: db}
+}
+
+func (a APIKeyStore) CreateAPIKey(ctx context.Context, key models.APIKey) error {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "CreateAPIKey-Store")
+	defer span.End()
+
+	_, err := a.db.ExecContext(ctx,
+		"INSERT INTO api_keys (id, user_id, name, prefix, key_hash, role, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, key.Role, key.ExpiresAt, key.CreatedAt)
+	return err
+}
+
+func (a APIKeyStore) GetAPIKeyById(ctx context.Context, id string) (models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "GetAPIKeyById-Store")
+	defer span.End()
+
+	key, err := scanAPIKey(a.db.QueryRowContext(ctx, selectAPIKey+" WHERE k.id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return key, models.ErrAPIKeyNotFound
+		}
+		return key, err
+	}
+
+	return key, nil
+}
+
+func (a APIKeyStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, models.User, error) {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "GetAPIKeyByHash-Store")
+	defer span.End()
+
+	var key models.APIKey
+	var owner models.User
+
+	err := a.db.QueryRowContext(ctx,
+		`SELECT k.id, k.user_id, u.username, k.name, k.prefix, k.key_hash, k.role, k.expires_at, k.last_used_at, k.revoked_at, k.created_at, u.role, u.disabled FROM api_keys k JOIN users u ON k.user_id = u.id WHERE k.key_hash = $1`,
+		keyHash).Scan(
+		&key.ID,
+		&key.UserID,
+		&key.UserName,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+		&owner.Role,
+		&owner.Disabled,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return key, owner, models.ErrAPIKeyNotFound
+		}
+		return key, owner, err
+	}
+
+	owner.ID = key.UserID
+	owner.UserName = key.UserName
+
+	return key, owner, nil
+}
+
+func (a APIKeyStore) ListAPIKeys(ctx context.Context, userID string) ([]models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "ListAPIKeys-Store")
+	defer span.End()
+
+	rows, err := a.db.QueryContext(ctx, selectAPIKey+" WHERE k.user_id = $1 ORDER BY k.created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (a APIKeyStore) RevokeAPIKey(ctx context.Context, id string) (models.APIKey, error) {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "RevokeAPIKey-Store")
+	defer span.End()
+
+	_, err := a.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL", id, time.Now())
+	if err != nil {
+		return models.APIKey{}, err
+	}
+
+	return a.GetAPIKeyById(ctx, id)
+}
+
+func (a APIKeyStore) TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error {
+	tracer := otel.Tracer("APIKeyStore")
+	ctx, span := tracer.Start(ctx, "TouchAPIKey-Store")
+	defer span.End()
+
+	_, err := a.db.ExecContext(ctx,
+		"UPDATE api_keys SET last_used_at = $2 WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $3)",
+		id, usedAt, usedAt.Add(-lastUsedResolution))
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.UserName,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	return key, err
+}
@@ -2,9 +2,12 @@ package car
 
 import (
 	"Car-Management-System/models"
+	"Car-Management-System/store/outbox"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,35 +22,53 @@ func New(db *sql.DB) Store {
 	return Store{db: db}
 }
 
-func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
+func (s Store) GetCarById(ctx context.Context, id string, includeDeleted bool) (models.Car, error) {
 	tracer := otel.Tracer("CarStore")
 	ctx, span := tracer.Start(ctx, "GetCarById-Store")
 	defer span.End()
 
+	return s.getCar(ctx, "c.id=$1", id, includeDeleted)
+}
+
+// getCar returns the car matching where, which compares one column with
+// $1, together with its engine.
+func (s Store) getCar(ctx context.Context, where string, arg interface{}, includeDeleted bool) (models.Car, error) {
 	var car models.Car
 
-	query := `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range FROM car c LEFT JOIN engine e ON c.engine_id = e.id WHERE c.id=$1`
+	query := `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, COALESCE(c.vin, ''), c.engine_id, c.price, c.version, c.created_at, c.updated_at, c.deleted_at, e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version FROM car c LEFT JOIN engine e ON c.engine_id = e.id WHERE ` + where
+	if !includeDeleted {
+		query += " AND c.deleted_at IS NULL"
+	}
 
-	row := s.db.QueryRowContext(ctx, query, id)
+	row := s.db.QueryRowContext(ctx, query, arg)
 	err := row.Scan(
 		&car.ID,
 		&car.Name,
 		&car.Year,
 		&car.Brand,
 		&car.FuelType,
+		&car.VIN,
 		&car.Engine.EngineID,
 		&car.Price,
+		&car.Version,
 		&car.CreatedAt,
 		&car.UpdatedAt,
+		&car.DeletedAt,
 		&car.Engine.EngineID,
 		&car.Engine.Displacement,
 		&car.Engine.NoOfCylinders,
 		&car.Engine.CarRange,
+		&car.Engine.Type,
+		&car.Engine.BatteryKWh,
+		&car.Engine.ChargePowerKW,
+		&car.Engine.MotorPowerKW,
+		&car.Engine.MotorTorqueNm,
+		&car.Engine.Version,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return car, nil
+			return car, models.ErrCarNotFound
 		}
 		return car, err
 	}
@@ -55,91 +76,12 @@ func (s Store) GetCarById(ctx context.Context, id string) (models.Car, error) {
 	return car, nil
 }
 
-func (s Store) GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error) {
-	tracer := otel.Tracer("CarStore")
-	ctx, span := tracer.Start(ctx, "GetCarByBrand-Store")
-	defer span.End()
-
-	var cars []models.Car
-	var query string
-
-	if isEngine {
-		query = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range FROM car c LEFT JOIN engine e ON c.engine_id = e.id WHERE c.brand=$1`
-	} else {
-		query = `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, c.engine_id, c.price, c.created_at, c.updated_at FROM car c WHERE brand = $1`
-	}
-
-	rows, err := s.db.QueryContext(ctx, query, brand)
-	if err != nil {
-		return nil, err
-	}
-
-	defer rows.Close()
-
-	for rows.Next() {
-		var car models.Car
-		if isEngine {
-			var engine models.Engine
-			err := rows.Scan(
-				&car.ID,
-				&car.Name,
-				&car.Year,
-				&car.Brand,
-				&car.FuelType,
-				&car.Engine.EngineID,
-				&car.Price,
-				&car.CreatedAt,
-				&car.UpdatedAt,
-				&car.Engine.EngineID,
-				&car.Engine.Displacement,
-				&car.Engine.NoOfCylinders,
-				&car.Engine.CarRange,
-			)
-			if err != nil {
-				return nil, err
-			}
-			car.Engine = engine
-		} else {
-			err := rows.Scan(
-				&car.ID,
-				&car.Name,
-				&car.Year,
-				&car.Brand,
-				&car.FuelType,
-				&car.Engine.EngineID,
-				&car.Price,
-				&car.CreatedAt,
-				&car.UpdatedAt,
-			)
-			if err != nil {
-				return nil, err
-			}
-		}
-		cars = append(cars, car)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return cars, nil
-}
-
 func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models.Car, error) {
 	tracer := otel.Tracer("CarStore")
 	ctx, span := tracer.Start(ctx, "CreateCar-Store")
 	defer span.End()
 
 	var createdCar models.Car
-	var engineID uuid.UUID
-
-	err := s.db.QueryRowContext(ctx, "SELECT id FROM engine WHERE id = $1", carReq.Engine.EngineID).Scan(&engineID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return createdCar, errors.New("engine_id does not exists in the engine table")
-		}
-		return createdCar, err
-	}
 
 	carID := uuid.New()
 
@@ -152,6 +94,7 @@ func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models
 		Year:      carReq.Year,
 		Brand:     carReq.Brand,
 		FuelType:  carReq.FuelType,
+		VIN:       carReq.VIN,
 		Engine:    carReq.Engine,
 		Price:     carReq.Price,
 		CreatedAt: createdAt,
@@ -170,7 +113,12 @@ func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models
 		err = tx.Commit()
 	}()
 
-	query := `INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)RETURNING id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at`
+	engine, err := liveEngine(ctx, tx, carReq.Engine.EngineID)
+	if err != nil {
+		return createdCar, err
+	}
+
+	query := `INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at, vin) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)RETURNING id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at`
 
 	err = tx.QueryRowContext(ctx, query,
 		&newCar.ID,
@@ -182,18 +130,33 @@ func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models
 		&newCar.Price,
 		&newCar.CreatedAt,
 		&newCar.UpdatedAt,
+		nullableVIN(newCar.VIN),
 	).Scan(
 		&createdCar.ID,
 		&createdCar.Name,
 		&createdCar.Year,
 		&createdCar.Brand,
 		&createdCar.FuelType,
+		&createdCar.VIN,
 		&createdCar.Engine.EngineID,
 		&createdCar.Price,
+		&createdCar.Version,
 		&createdCar.CreatedAt,
 		&createdCar.UpdatedAt,
 	)
 
+	if err != nil {
+		err = vinError(err)
+		return createdCar, err
+	}
+	createdCar.Engine = engine
+
+	err = RecordChange(ctx, tx, createdCar.ID, models.CarChangeCreate, nil, &createdCar)
+	if err != nil {
+		return createdCar, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventCarCreated, createdCar.ID, createdCar)
 	if err != nil {
 		return createdCar, err
 	}
@@ -201,7 +164,7 @@ func (s Store) CreateCar(ctx context.Context, carReq *models.CarRequest) (models
 	return createdCar, nil
 }
 
-func (s Store) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (models.Car, error) {
+func (s Store) UpdateCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (models.Car, error) {
 	tracer := otel.Tracer("CarStore")
 	ctx, span := tracer.Start(ctx, "UpdateCar-Store")
 	defer span.End()
@@ -220,11 +183,26 @@ func (s Store) UpdateCar(ctx context.Context, id string, carReq *models.CarReque
 		err = tx.Commit()
 	}()
 
+	before, err := lockCar(ctx, tx, id)
+	if err != nil {
+		return updatedCar, err
+	}
+
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		err = models.ErrVersionMismatch
+		return updatedCar, err
+	}
+
+	engine, err := liveEngine(ctx, tx, carReq.Engine.EngineID)
+	if err != nil {
+		return updatedCar, err
+	}
+
 	query := `
 		UPDATE car 
-		SET name = $2, year = $3, brand = $4, fuel_type = $5, engine_id = $6, price = $7, updated_at = $8
-		WHERE id = $1
-		RETURNING id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at
+		SET name = $2, year = $3, brand = $4, fuel_type = $5, engine_id = $6, price = $7, updated_at = $8, vin = $9, version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at
 	`
 
 	err = tx.QueryRowContext(ctx, query,
@@ -236,27 +214,148 @@ func (s Store) UpdateCar(ctx context.Context, id string, carReq *models.CarReque
 		carReq.Engine.EngineID,
 		carReq.Price,
 		time.Now(),
+		nullableVIN(carReq.VIN),
 	).Scan(
 		&updatedCar.ID,
 		&updatedCar.Name,
 		&updatedCar.Year,
 		&updatedCar.Brand,
 		&updatedCar.FuelType,
+		&updatedCar.VIN,
 		&updatedCar.Engine.EngineID,
 		&updatedCar.Price,
+		&updatedCar.Version,
 		&updatedCar.CreatedAt,
 		&updatedCar.UpdatedAt,
 	)
 
 	if err != nil {
-		return updatedCar, nil
+		err = vinError(err)
+		return updatedCar, err
+	}
+	updatedCar.Engine = engine
+
+	err = RecordChange(ctx, tx, updatedCar.ID, models.CarChangeUpdate, &before, &updatedCar)
+	if err != nil {
+		return updatedCar, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventCarUpdated, updatedCar.ID, updatedCar)
+	if err != nil {
+		return updatedCar, err
 	}
 
 	return updatedCar, nil
 
 }
 
-func (s Store) DeleteCar(ctx context.Context, id string) (models.Car, error) {
+// PatchCar writes only the columns whose value differs from the stored
+// row. Nothing is written, and the version is left alone, when the patch
+// changes nothing.
+func (s Store) PatchCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "PatchCar-Store")
+	defer span.End()
+
+	var patchedCar models.Car
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return patchedCar, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	before, err := lockCar(ctx, tx, id)
+	if err != nil {
+		return patchedCar, err
+	}
+
+	if expectedVersion != 0 && before.Version != expectedVersion {
+		err = models.ErrVersionMismatch
+		return patchedCar, err
+	}
+
+	columns := []struct {
+		name    string
+		changed bool
+		value   interface{}
+	}{
+		{"name", carReq.Name != before.Name, carReq.Name},
+		{"year", carReq.Year != before.Year, carReq.Year},
+		{"brand", carReq.Brand != before.Brand, carReq.Brand},
+		{"fuel_type", carReq.FuelType != before.FuelType, carReq.FuelType},
+		{"vin", carReq.VIN != before.VIN, nullableVIN(carReq.VIN)},
+		{"engine_id", carReq.Engine.EngineID != before.Engine.EngineID, carReq.Engine.EngineID},
+		{"price", carReq.Price != before.Price, carReq.Price},
+	}
+
+	args := []interface{}{id}
+	var set []string
+	for _, column := range columns {
+		if column.changed {
+			args = append(args, column.value)
+			set = append(set, fmt.Sprintf("%s = $%d", column.name, len(args)))
+		}
+	}
+
+	if len(set) == 0 {
+		return before, nil
+	}
+
+	engine := before.Engine
+	if carReq.Engine.EngineID != before.Engine.EngineID {
+		engine, err = liveEngine(ctx, tx, carReq.Engine.EngineID)
+		if err != nil {
+			return patchedCar, err
+		}
+	}
+
+	args = append(args, time.Now())
+	set = append(set, fmt.Sprintf("updated_at = $%d", len(args)), "version = version + 1")
+
+	query := "UPDATE car SET " + strings.Join(set, ", ") +
+		" WHERE id = $1 RETURNING id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at"
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&patchedCar.ID,
+		&patchedCar.Name,
+		&patchedCar.Year,
+		&patchedCar.Brand,
+		&patchedCar.FuelType,
+		&patchedCar.VIN,
+		&patchedCar.Engine.EngineID,
+		&patchedCar.Price,
+		&patchedCar.Version,
+		&patchedCar.CreatedAt,
+		&patchedCar.UpdatedAt,
+	)
+
+	if err != nil {
+		err = vinError(err)
+		return patchedCar, err
+	}
+	patchedCar.Engine = engine
+
+	err = RecordChange(ctx, tx, patchedCar.ID, models.CarChangeUpdate, &before, &patchedCar)
+	if err != nil {
+		return patchedCar, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventCarUpdated, patchedCar.ID, patchedCar)
+	if err != nil {
+		return patchedCar, err
+	}
+
+	return patchedCar, nil
+}
+
+func (s Store) DeleteCar(ctx context.Context, id string, expectedVersion int64) (models.Car, error) {
 	tracer := otel.Tracer("CarStore")
 	ctx, span := tracer.Start(ctx, "DeleteCar-Store")
 	defer span.End()
@@ -275,27 +374,23 @@ func (s Store) DeleteCar(ctx context.Context, id string) (models.Car, error) {
 		err = tx.Commit()
 	}()
 
-	err = tx.QueryRowContext(ctx, "SELECT id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at FROM car WHERE id = $1", id).
-		Scan(
-			&deletedCar.ID,
-			&deletedCar.Name,
-			&deletedCar.Year,
-			&deletedCar.Brand,
-			&deletedCar.FuelType,
-			&deletedCar.Engine.EngineID,
-			&deletedCar.Price,
-			&deletedCar.CreatedAt,
-			&deletedCar.UpdatedAt,
-		)
+	deletedCar, err = lockCar(ctx, tx, id)
+	if err != nil {
+		return models.Car{}, err
+	}
 
+	if expectedVersion != 0 && deletedCar.Version != expectedVersion {
+		err = models.ErrVersionMismatch
+		return models.Car{}, err
+	}
+
+	err = RecordChange(ctx, tx, deletedCar.ID, models.CarChangeDelete, &deletedCar, nil)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.Car{}, errors.New("Car not found")
-		}
 		return models.Car{}, err
 	}
 
-	result, err := tx.ExecContext(ctx, "DELETE FROM car WHERE id = $1", id)
+	deletedAt := time.Now()
+	result, err := tx.ExecContext(ctx, "UPDATE car SET deleted_at = $2, version = version + 1 WHERE id = $1 AND deleted_at IS NULL", id, deletedAt)
 	if err != nil {
 		return models.Car{}, err
 	}
@@ -307,5 +402,359 @@ func (s Store) DeleteCar(ctx context.Context, id string) (models.Car, error) {
 	if rowsAffected == 0 {
 		return models.Car{}, errors.New("No rows were deleted")
 	}
+
+	deletedCar.Version++
+	deletedCar.DeletedAt = &deletedAt
+
+	err = outbox.Record(ctx, tx, models.EventCarDeleted, deletedCar.ID, deletedCar)
+	if err != nil {
+		return models.Car{}, err
+	}
+
 	return deletedCar, nil
 }
+
+func (s Store) ListCars(ctx context.Context, filter models.CarFilter) (models.CarPage, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "ListCars-Store")
+	defer span.End()
+
+	var page models.CarPage
+
+	col, err := normalizeFilter(&filter)
+	if err != nil {
+		return page, err
+	}
+	page.Limit = filter.Limit
+
+	q := buildFilterQuery(filter)
+
+	countQuery := `SELECT COUNT(*) FROM car c JOIN engine e ON c.engine_id = e.id` + q.Where()
+	if err := s.db.QueryRowContext(ctx, countQuery, q.Args...).Scan(&page.TotalCount); err != nil {
+		return page, err
+	}
+
+	if filter.Cursor != "" {
+		c, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return page, err
+		}
+		if c.SortBy != filter.SortBy || c.Order != filter.Order {
+			return page, models.ErrInvalidCursor
+		}
+		addCursor(q, col, filter, c)
+	}
+
+	query := fmt.Sprintf(`SELECT c.id, c.name, c.year, c.brand, c.fuel_type, COALESCE(c.vin, ''), c.engine_id, c.price, c.version, c.created_at, c.updated_at, c.deleted_at, e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version, CAST(%s AS TEXT) FROM car c JOIN engine e ON c.engine_id = e.id`, col.expr) +
+		q.Where() + orderBy(col, filter.Order) + fmt.Sprintf(" LIMIT %d", filter.Limit+1)
+
+	rows, err := s.db.QueryContext(ctx, query, q.Args...)
+	if err != nil {
+		return page, err
+	}
+
+	defer rows.Close()
+
+	var lastSortValue string
+	page.Cars = make([]models.Car, 0, filter.Limit)
+
+	for rows.Next() {
+		var car models.Car
+		var sortValue string
+		err := rows.Scan(
+			&car.ID,
+			&car.Name,
+			&car.Year,
+			&car.Brand,
+			&car.FuelType,
+			&car.VIN,
+			&car.Engine.EngineID,
+			&car.Price,
+			&car.Version,
+			&car.CreatedAt,
+			&car.UpdatedAt,
+			&car.DeletedAt,
+			&car.Engine.EngineID,
+			&car.Engine.Displacement,
+			&car.Engine.NoOfCylinders,
+			&car.Engine.CarRange,
+			&car.Engine.Type,
+			&car.Engine.BatteryKWh,
+			&car.Engine.ChargePowerKW,
+			&car.Engine.MotorPowerKW,
+			&car.Engine.MotorTorqueNm,
+			&car.Engine.Version,
+			&sortValue,
+		)
+		if err != nil {
+			return page, err
+		}
+
+		if len(page.Cars) == filter.Limit {
+			page.NextCursor, err = encodeCursor(cursor{
+				SortBy: filter.SortBy,
+				Order:  filter.Order,
+				Value:  lastSortValue,
+				ID:     page.Cars[len(page.Cars)-1].ID.String(),
+			})
+			if err != nil {
+				return page, err
+			}
+			break
+		}
+
+		if !filter.IsEngine {
+			car.Engine = models.Engine{EngineID: car.Engine.EngineID}
+		}
+
+		page.Cars = append(page.Cars, car)
+		lastSortValue = sortValue
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, err
+	}
+
+	return page, nil
+}
+
+// lockCar returns the live car id with its engine, and locks the car for
+// the rest of tx. History snapshots are taken from it, so they carry the
+// engine's details and not just its id.
+func lockCar(ctx context.Context, tx *sql.Tx, id string) (models.Car, error) {
+	var car models.Car
+	err := tx.QueryRowContext(ctx, "SELECT c.id, c.name, c.year, c.brand, c.fuel_type, COALESCE(c.vin, ''), c.price, c.version, c.created_at, c.updated_at, e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.id = $1 AND c.deleted_at IS NULL FOR UPDATE OF c", id).
+		Scan(
+			&car.ID,
+			&car.Name,
+			&car.Year,
+			&car.Brand,
+			&car.FuelType,
+			&car.VIN,
+			&car.Price,
+			&car.Version,
+			&car.CreatedAt,
+			&car.UpdatedAt,
+			&car.Engine.EngineID,
+			&car.Engine.Displacement,
+			&car.Engine.NoOfCylinders,
+			&car.Engine.CarRange,
+			&car.Engine.Type,
+			&car.Engine.BatteryKWh,
+			&car.Engine.ChargePowerKW,
+			&car.Engine.MotorPowerKW,
+			&car.Engine.MotorTorqueNm,
+			&car.Engine.Version,
+		)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return car, models.ErrCarNotFound
+		}
+		return car, err
+	}
+	return car, nil
+}
+
+// liveEngine returns the engine a car is written with, or
+// ErrCarEngineMissing if it does not exist or is deleted. The engine is
+// share-locked for the rest of tx, so it cannot be deleted before the car
+// that references it is written.
+func liveEngine(ctx context.Context, tx *sql.Tx, id uuid.UUID) (models.Engine, error) {
+	var engine models.Engine
+	err := tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version FROM engine WHERE id = $1 AND deleted_at IS NULL FOR SHARE", id).Scan(
+		&engine.EngineID,
+		&engine.Displacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&engine.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return engine, models.ErrCarEngineMissing
+		}
+		return engine, err
+	}
+	return engine, nil
+}
+
+// RestoreCar clears deleted_at on a soft-deleted car. A car whose engine
+// is still deleted cannot be restored on its own.
+func (s Store) RestoreCar(ctx context.Context, id string) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "RestoreCar-Store")
+	defer span.End()
+
+	var restoredCar models.Car
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return restoredCar, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var deletedAt, engineDeletedAt *time.Time
+	err = tx.QueryRowContext(ctx, "SELECT c.deleted_at, e.deleted_at FROM car c JOIN engine e ON c.engine_id = e.id WHERE c.id = $1 FOR UPDATE OF c", id).
+		Scan(&deletedAt, &engineDeletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return restoredCar, models.ErrCarNotFound
+		}
+		return restoredCar, err
+	}
+
+	if deletedAt == nil {
+		err = models.ErrCarNotDeleted
+		return restoredCar, err
+	}
+	if engineDeletedAt != nil {
+		err = models.ErrEngineDeleted
+		return restoredCar, err
+	}
+
+	err = tx.QueryRowContext(ctx,
+		"UPDATE car SET deleted_at = NULL, updated_at = $2, version = version + 1 WHERE id = $1 RETURNING id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at",
+		id, time.Now()).Scan(
+		&restoredCar.ID,
+		&restoredCar.Name,
+		&restoredCar.Year,
+		&restoredCar.Brand,
+		&restoredCar.FuelType,
+		&restoredCar.VIN,
+		&restoredCar.Engine.EngineID,
+		&restoredCar.Price,
+		&restoredCar.Version,
+		&restoredCar.CreatedAt,
+		&restoredCar.UpdatedAt,
+	)
+	if err != nil {
+		return restoredCar, err
+	}
+
+	restoredCar.Engine, err = liveEngine(ctx, tx, restoredCar.Engine.EngineID)
+	if err != nil {
+		return restoredCar, err
+	}
+
+	err = RecordChange(ctx, tx, restoredCar.ID, models.CarChangeRestore, nil, &restoredCar)
+	if err != nil {
+		return restoredCar, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventCarRestored, restoredCar.ID, restoredCar)
+	if err != nil {
+		return restoredCar, err
+	}
+
+	return restoredCar, nil
+}
+
+// PurgeCar hard-deletes a car that is already soft-deleted.
+func (s Store) PurgeCar(ctx context.Context, id string) error {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "PurgeCar-Store")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var carID uuid.UUID
+	var deletedAt *time.Time
+	err = tx.QueryRowContext(ctx, "SELECT id, deleted_at FROM car WHERE id = $1 FOR UPDATE", id).Scan(&carID, &deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = models.ErrCarNotFound
+		}
+		return err
+	}
+	if deletedAt == nil {
+		err = models.ErrCarNotDeleted
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM car WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventCarPurged, carID, map[string]uuid.UUID{"id": carID})
+	return err
+}
+
+// PurgeDeletedCars hard-deletes every car soft-deleted before olderThan and
+// returns how many were removed.
+func (s Store) PurgeDeletedCars(ctx context.Context, olderThan time.Time) (int64, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "PurgeDeletedCars-Store")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	ids, err := DeleteReturningIDs(ctx, tx, "DELETE FROM car WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id", olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	events, err := outbox.PurgeEvents(models.EventCarPurged, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	err = outbox.Append(ctx, tx, events...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
+
+// DeleteReturningIDs runs a DELETE ... RETURNING id and returns the ids of
+// the removed rows.
+func DeleteReturningIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
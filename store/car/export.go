@@ -0,0 +1,71 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+)
+
+// ExportCars streams every car matching filter, joined with its engine, to
+// fn one row at a time straight off the cursor. Paging fields in filter
+// are ignored; sorting is honoured. An error from fn stops the export.
+func (s Store) ExportCars(ctx context.Context, filter models.CarFilter, fn func(models.Car) error) error {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "ExportCars-Store")
+	defer span.End()
+
+	col, err := normalizeFilter(&filter)
+	if err != nil {
+		return err
+	}
+
+	q := buildFilterQuery(filter)
+
+	query := `SELECT c.id, c.name, c.year, c.brand, c.fuel_type, COALESCE(c.vin, ''), c.price, c.version, c.created_at, c.updated_at, c.deleted_at, e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version FROM car c JOIN engine e ON c.engine_id = e.id` +
+		q.Where() + orderBy(col, filter.Order)
+
+	rows, err := s.db.QueryContext(ctx, query, q.Args...)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var car models.Car
+		err := rows.Scan(
+			&car.ID,
+			&car.Name,
+			&car.Year,
+			&car.Brand,
+			&car.FuelType,
+			&car.VIN,
+			&car.Price,
+			&car.Version,
+			&car.CreatedAt,
+			&car.UpdatedAt,
+			&car.DeletedAt,
+			&car.Engine.EngineID,
+			&car.Engine.Displacement,
+			&car.Engine.NoOfCylinders,
+			&car.Engine.CarRange,
+			&car.Engine.Type,
+			&car.Engine.BatteryKWh,
+			&car.Engine.ChargePowerKW,
+			&car.Engine.MotorPowerKW,
+			&car.Engine.MotorTorqueNm,
+			&car.Engine.Version,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(car); err != nil {
+			return fmt.Errorf("writing exported car %s: %w", car.ID, err)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,175 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// RecordChange adds a car_history row for one change of a car. It is meant
+// to run in the same transaction as the change itself. The acting user is
+// taken from the "username" context value.
+func RecordChange(ctx context.Context, exec execer, carID uuid.UUID, operation string, before *models.Car, after *models.Car) error {
+	beforeState, err := marshalState(before)
+	if err != nil {
+		return err
+	}
+
+	afterState, err := marshalState(after)
+	if err != nil {
+		return err
+	}
+
+	userName, _ := ctx.Value("username").(string)
+
+	_, err = exec.ExecContext(ctx,
+		"INSERT INTO car_history (car_id, operation, before_state, after_state, changed_by, changed_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		carID, operation, beforeState, afterState, sql.NullString{String: userName, Valid: userName != ""}, time.Now())
+	return err
+}
+
+// recordCreates adds a "create" history row for each car with a single
+// multi-row INSERT.
+func recordCreates(ctx context.Context, exec execer, cars []models.Car) error {
+	if len(cars) == 0 {
+		return nil
+	}
+
+	userName, _ := ctx.Value("username").(string)
+	changedBy := sql.NullString{String: userName, Valid: userName != ""}
+	changedAt := time.Now()
+
+	values := make([]string, 0, len(cars))
+	args := make([]interface{}, 0, len(cars)*4+2)
+	args = append(args, changedBy, changedAt)
+	for i := range cars {
+		afterState, err := marshalState(&cars[i])
+		if err != nil {
+			return err
+		}
+
+		args = append(args, cars[i].ID, afterState)
+		values = append(values, fmt.Sprintf("($%d, '%s', NULL, $%d, $1, $2)", len(args)-1, models.CarChangeCreate, len(args)))
+	}
+
+	_, err := exec.ExecContext(ctx,
+		"INSERT INTO car_history (car_id, operation, before_state, after_state, changed_by, changed_at) VALUES "+strings.Join(values, ", "),
+		args...)
+	return err
+}
+
+func (s Store) GetCarHistory(ctx context.Context, id string) ([]models.CarHistory, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarHistory-Store")
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, car_id, operation, before_state, after_state, changed_by, changed_at FROM car_history WHERE car_id = $1 ORDER BY changed_at, id", id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	history := []models.CarHistory{}
+	for rows.Next() {
+		var entry models.CarHistory
+		var beforeState, afterState []byte
+		var changedBy sql.NullString
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.CarID,
+			&entry.Operation,
+			&beforeState,
+			&afterState,
+			&changedBy,
+			&entry.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Before, err = unmarshalState(beforeState); err != nil {
+			return nil, err
+		}
+		if entry.After, err = unmarshalState(afterState); err != nil {
+			return nil, err
+		}
+		entry.ChangedBy = changedBy.String
+
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetCarAsOf returns the car as it was at asOf, taken from the latest
+// change recorded at or before that time.
+func (s Store) GetCarAsOf(ctx context.Context, id string, asOf time.Time) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarAsOf-Store")
+	defer span.End()
+
+	var afterState []byte
+
+	err := s.db.QueryRowContext(ctx,
+		"SELECT after_state FROM car_history WHERE car_id = $1 AND changed_at <= $2 ORDER BY changed_at DESC, id DESC LIMIT 1",
+		id, asOf).Scan(&afterState)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Car{}, models.ErrCarNotFound
+		}
+		return models.Car{}, err
+	}
+
+	car, err := unmarshalState(afterState)
+	if err != nil {
+		return models.Car{}, err
+	}
+	if car == nil {
+		return models.Car{}, models.ErrCarNotFound
+	}
+
+	return *car, nil
+}
+
+func marshalState(car *models.Car) (sql.NullString, error) {
+	if car == nil {
+		return sql.NullString{}, nil
+	}
+
+	state, err := json.Marshal(car)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(state), Valid: true}, nil
+}
+
+func unmarshalState(state []byte) (*models.Car, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	var car models.Car
+	if err := json.Unmarshal(state, &car); err != nil {
+		return nil, err
+	}
+	return &car, nil
+}
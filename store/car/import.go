@@ -0,0 +1,166 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store/outbox"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+// importBatchSize keeps each multi-row INSERT well under Postgres' limit
+// of 65535 bind parameters.
+const importBatchSize = 500
+
+// GetEnginesByIds returns the live engines among ids, keyed by id.
+func (s Store) GetEnginesByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Engine, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetEnginesByIds-Store")
+	defer span.End()
+
+	engines := map[uuid.UUID]models.Engine{}
+	if len(ids) == 0 {
+		return engines, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version FROM engine WHERE id = ANY($1) AND deleted_at IS NULL", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var engine models.Engine
+		err := rows.Scan(
+			&engine.EngineID,
+			&engine.Displacement,
+			&engine.NoOfCylinders,
+			&engine.CarRange,
+			&engine.Type,
+			&engine.BatteryKWh,
+			&engine.ChargePowerKW,
+			&engine.MotorPowerKW,
+			&engine.MotorTorqueNm,
+			&engine.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		engines[engine.EngineID] = engine
+	}
+
+	return engines, rows.Err()
+}
+
+// CreateCars inserts all cars in one transaction, in batches of
+// importBatchSize rows. The referenced engines are locked for the length
+// of the transaction so none of them can be deleted halfway through.
+func (s Store) CreateCars(ctx context.Context, carReqs []models.CarRequest) ([]models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "CreateCars-Store")
+	defer span.End()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	engineIDs := map[uuid.UUID]bool{}
+	for _, carReq := range carReqs {
+		engineIDs[carReq.Engine.EngineID] = true
+	}
+	ids := make([]uuid.UUID, 0, len(engineIDs))
+	for id := range engineIDs {
+		ids = append(ids, id)
+	}
+
+	var locked int
+	err = tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM (SELECT id FROM engine WHERE id = ANY($1) AND deleted_at IS NULL FOR SHARE) e", pq.Array(ids)).Scan(&locked)
+	if err != nil {
+		return nil, err
+	}
+	if locked != len(ids) {
+		err = models.ErrCarEngineMissing
+		return nil, err
+	}
+
+	now := time.Now()
+	cars := make([]models.Car, 0, len(carReqs))
+	for start := 0; start < len(carReqs); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(carReqs) {
+			end = len(carReqs)
+		}
+
+		batch := make([]models.Car, 0, end-start)
+		values := make([]string, 0, end-start)
+		args := []interface{}{now}
+		for _, carReq := range carReqs[start:end] {
+			car := models.Car{
+				ID:        uuid.New(),
+				Name:      carReq.Name,
+				Year:      carReq.Year,
+				Brand:     carReq.Brand,
+				FuelType:  carReq.FuelType,
+				VIN:       carReq.VIN,
+				Engine:    carReq.Engine,
+				Price:     carReq.Price,
+				Version:   1,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			batch = append(batch, car)
+
+			n := len(args)
+			args = append(args, car.ID, car.Name, car.Year, car.Brand, car.FuelType, car.Engine.EngineID, car.Price, nullableVIN(car.VIN))
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $1, $1, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8))
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO car (id, name, year, brand, fuel_type, engine_id, price, created_at, updated_at, vin) VALUES "+strings.Join(values, ", "),
+			args...)
+		if err != nil {
+			err = vinError(err)
+			return nil, err
+		}
+
+		err = recordCreates(ctx, tx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]models.Event, 0, len(batch))
+		for _, car := range batch {
+			event, eventErr := models.NewEvent(models.EventCarCreated, car.ID, car)
+			if eventErr != nil {
+				err = eventErr
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		err = outbox.Append(ctx, tx, events...)
+		if err != nil {
+			return nil, err
+		}
+
+		cars = append(cars, batch...)
+	}
+
+	return cars, nil
+}
@@ -0,0 +1,175 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store/listquery"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type sortColumn struct {
+	expr string
+	cast string
+}
+
+var carSortColumns = map[string]sortColumn{
+	"id":              {expr: "c.id", cast: "uuid"},
+	"name":            {expr: "c.name", cast: "text"},
+	"year":            {expr: "c.year", cast: "text"},
+	"brand":           {expr: "c.brand", cast: "text"},
+	"fuel_type":       {expr: "c.fuel_type", cast: "text"},
+	"price":           {expr: "c.price", cast: "numeric"},
+	"created_at":      {expr: "c.created_at", cast: "timestamp"},
+	"updated_at":      {expr: "c.updated_at", cast: "timestamp"},
+	"displacement":    {expr: "e.displacement", cast: "int"},
+	"no_of_cylinders": {expr: "e.no_of_cylinders", cast: "int"},
+	"car_range":       {expr: "e.car_range", cast: "int"},
+}
+
+// timestampLayouts are the forms Postgres writes a timestamp in when it is
+// cast to text.
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05.999999-07",
+	"2006-01-02 15:04:05.999999-07:00",
+}
+
+// numericPattern matches the decimal numbers Postgres writes for a
+// numeric cast to text.
+var numericPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+type cursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Value  string `json:"v"`
+	ID     string `json:"id"`
+}
+
+func encodeCursor(c cursor) (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return listquery.EncodeCursor(raw), nil
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	raw, err := listquery.DecodeCursor(s)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, models.ErrInvalidCursor
+	}
+	col, ok := carSortColumns[c.SortBy]
+	if !ok || !validCursorValue(col.cast, c.Value) {
+		return c, models.ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(c.ID); err != nil {
+		return c, models.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// validCursorValue reports whether value can be cast to cast, so that a
+// tampered cursor is rejected here instead of failing in the database.
+func validCursorValue(cast string, value string) bool {
+	var err error
+	switch cast {
+	case "uuid":
+		_, err = uuid.Parse(value)
+	case "numeric":
+		if !numericPattern.MatchString(value) {
+			return false
+		}
+	case "int":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "timestamp":
+		for _, layout := range timestampLayouts {
+			if _, err = time.Parse(layout, value); err == nil {
+				break
+			}
+		}
+	}
+	return err == nil
+}
+
+func normalizeFilter(filter *models.CarFilter) (sortColumn, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = "created_at"
+	}
+	col, ok := carSortColumns[filter.SortBy]
+	if !ok {
+		return col, fmt.Errorf("%w: %s", models.ErrInvalidSortField, filter.SortBy)
+	}
+
+	filter.Order = strings.ToLower(filter.Order)
+	if filter.Order != "desc" {
+		filter.Order = "asc"
+	}
+
+	filter.Limit = listquery.Limit(filter.Limit)
+
+	return col, nil
+}
+
+func buildFilterQuery(filter models.CarFilter) *listquery.Builder {
+	q := &listquery.Builder{}
+
+	if !filter.IncludeDeleted {
+		q.Add("c.deleted_at IS NULL")
+	}
+
+	if filter.Brand != "" {
+		q.Add("c.brand = $%d", filter.Brand)
+	}
+	if filter.FuelType != "" {
+		q.Add("c.fuel_type = $%d", filter.FuelType)
+	}
+	if filter.MinYear > 0 {
+		q.Add("CAST(c.year AS INT) >= $%d", filter.MinYear)
+	}
+	if filter.MaxYear > 0 {
+		q.Add("CAST(c.year AS INT) <= $%d", filter.MaxYear)
+	}
+	if filter.MinPrice > 0 {
+		q.Add("c.price >= $%d", filter.MinPrice)
+	}
+	if filter.MaxPrice > 0 {
+		q.Add("c.price <= $%d", filter.MaxPrice)
+	}
+	if filter.MinDisplacement > 0 {
+		q.Add("e.displacement >= $%d", filter.MinDisplacement)
+	}
+	if filter.MaxDisplacement > 0 {
+		q.Add("e.displacement <= $%d", filter.MaxDisplacement)
+	}
+	if filter.Cylinders > 0 {
+		q.Add("e.no_of_cylinders = $%d", filter.Cylinders)
+	}
+
+	return q
+}
+
+func addCursor(q *listquery.Builder, col sortColumn, filter models.CarFilter, c cursor) {
+	op := ">"
+	if filter.Order == "desc" {
+		op = "<"
+	}
+	q.Add(fmt.Sprintf("(%s, c.id) %s (CAST($%%d AS %s), CAST($%%d AS uuid))", col.expr, op, col.cast), c.Value, c.ID)
+}
+
+func orderBy(col sortColumn, order string) string {
+	dir := "ASC"
+	if order == "desc" {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, c.id %s", col.expr, dir, dir)
+}
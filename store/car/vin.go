@@ -0,0 +1,41 @@
+package car
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"errors"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+const uniqueViolation = "23505"
+
+// GetCarByVIN returns the car with vin. VINs are stored normalized, so vin
+// must be too.
+func (s Store) GetCarByVIN(ctx context.Context, vin string, includeDeleted bool) (models.Car, error) {
+	tracer := otel.Tracer("CarStore")
+	ctx, span := tracer.Start(ctx, "GetCarByVIN-Store")
+	defer span.End()
+
+	return s.getCar(ctx, "c.vin=$1", vin, includeDeleted)
+}
+
+// nullableVIN stores a car without a VIN as NULL, so that the unique index
+// on vin only applies to cars that have one.
+func nullableVIN(vin string) interface{} {
+	if vin == "" {
+		return nil
+	}
+	return vin
+}
+
+// vinError turns a violation of the unique index on vin into
+// ErrCarVINTaken.
+func vinError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "idx_car_vin" {
+		return models.ErrCarVINTaken
+	}
+	return err
+}
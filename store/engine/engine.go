@@ -2,12 +2,18 @@ package engine
 
 import (
 	"Car-Management-System/models"
+	carStore "Car-Management-System/store/car"
+	"Car-Management-System/store/listquery"
+	"Car-Management-System/store/outbox"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel"
 )
 
@@ -19,7 +25,7 @@ func New(db *sql.DB) *EngineStore {
 	return &EngineStore{db: db}
 }
 
-func (e EngineStore) EngineById(ctx context.Context, id string) (models.Engine, error) {
+func (e EngineStore) EngineById(ctx context.Context, id string, includeDeleted bool) (models.Engine, error) {
 	tracer := otel.Tracer("EngineStore")
 	ctx, span := tracer.Start(ctx, "EngineById-Store")
 	defer span.End()
@@ -42,16 +48,28 @@ func (e EngineStore) EngineById(ctx context.Context, id string) (models.Engine,
 		}
 	}()
 
-	tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range FROM engine WHERE id=$1", id).Scan(
+	query := "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version, deleted_at FROM engine WHERE id=$1"
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	err = tx.QueryRowContext(ctx, query, id).Scan(
 		&engine.EngineID,
 		&engine.Displacement,
 		&engine.NoOfCylinders,
 		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&engine.Version,
+		&engine.DeletedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return engine, nil
+			return engine, models.ErrEngineNotFound
 		}
 		return engine, err
 	}
@@ -59,6 +77,54 @@ func (e EngineStore) EngineById(ctx context.Context, id string) (models.Engine,
 	return engine, err
 }
 
+// EnginesByIds returns the engines among ids in one query, keyed by id.
+// Ids that match no engine are left out.
+func (e EngineStore) EnginesByIds(ctx context.Context, ids []uuid.UUID, includeDeleted bool) (map[uuid.UUID]models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "EnginesByIds-Store")
+	defer span.End()
+
+	engines := map[uuid.UUID]models.Engine{}
+	if len(ids) == 0 {
+		return engines, nil
+	}
+
+	query := "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version, deleted_at FROM engine WHERE id = ANY($1)"
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	rows, err := e.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var engine models.Engine
+		err := rows.Scan(
+			&engine.EngineID,
+			&engine.Displacement,
+			&engine.NoOfCylinders,
+			&engine.CarRange,
+			&engine.Type,
+			&engine.BatteryKWh,
+			&engine.ChargePowerKW,
+			&engine.MotorPowerKW,
+			&engine.MotorTorqueNm,
+			&engine.Version,
+			&engine.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		engines[engine.EngineID] = engine
+	}
+
+	return engines, rows.Err()
+}
+
 func (e EngineStore) EngineCreate(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error) {
 	tracer := otel.Tracer("EngineStore")
 	ctx, span := tracer.Start(ctx, "EngineCreate-Store")
@@ -83,7 +149,9 @@ func (e EngineStore) EngineCreate(ctx context.Context, engineReq *models.EngineR
 	engineID := uuid.New()
 
 	_, err = tx.ExecContext(ctx,
-		"INSERT INTO engine (id, displacement, no_of_cylinders, car_range) VALUES ($1, $2, $3, $4)", engineID, engineReq.Displacement, engineReq.NoOfCylinders, engineReq.CarRange)
+		"INSERT INTO engine (id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)",
+		engineID, engineReq.Displacement, engineReq.NoOfCylinders, engineReq.CarRange,
+		engineReq.Type, engineReq.BatteryKWh, engineReq.ChargePowerKW, engineReq.MotorPowerKW, engineReq.MotorTorqueNm)
 
 	if err != nil {
 		return models.Engine{}, err
@@ -94,12 +162,19 @@ func (e EngineStore) EngineCreate(ctx context.Context, engineReq *models.EngineR
 		Displacement:  engineReq.Displacement,
 		NoOfCylinders: engineReq.NoOfCylinders,
 		CarRange:      engineReq.CarRange,
+		Powertrain:    engineReq.Powertrain,
+		Version:       1,
+	}
+
+	err = outbox.Record(ctx, tx, models.EventEngineCreated, engine.EngineID, engine)
+	if err != nil {
+		return models.Engine{}, err
 	}
 
 	return engine, err
 }
 
-func (e EngineStore) EngineUpdate(ctx context.Context, id string, engineReq *models.EngineRequest) (models.Engine, error) {
+func (e EngineStore) EngineUpdate(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (models.Engine, error) {
 	tracer := otel.Tracer("EngineStore")
 	ctx, span := tracer.Start(ctx, "EngineUpdate-Store")
 	defer span.End()
@@ -125,19 +200,18 @@ func (e EngineStore) EngineUpdate(ctx context.Context, id string, engineReq *mod
 		}
 	}()
 
-	results, err := tx.ExecContext(ctx,
-		"UPDATE engine SET displacement = $1, no_of_cylinders = $2, car_range = $3 WHERE id = $4", engineReq.Displacement, engineReq.NoOfCylinders, engineReq.CarRange, engineID)
-
+	var currentVersion int64
+	err = tx.QueryRowContext(ctx, "SELECT version FROM engine WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", engineID).Scan(&currentVersion)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Engine{}, models.ErrEngineNotFound
+		}
 		return models.Engine{}, err
 	}
 
-	rowAffected, err := results.RowsAffected()
-	if err != nil {
-		return models.Engine{}, errors.New("No Rows were Updated")
-	}
-	if rowAffected == 0 {
-		return models.Engine{}, errors.New("No Rows were Updated")
+	if expectedVersion != 0 && currentVersion != expectedVersion {
+		err = models.ErrVersionMismatch
+		return models.Engine{}, err
 	}
 
 	engine := models.Engine{
@@ -145,12 +219,130 @@ func (e EngineStore) EngineUpdate(ctx context.Context, id string, engineReq *mod
 		Displacement:  engineReq.Displacement,
 		NoOfCylinders: engineReq.NoOfCylinders,
 		CarRange:      engineReq.CarRange,
+		Powertrain:    engineReq.Powertrain,
+	}
+
+	err = tx.QueryRowContext(ctx,
+		"UPDATE engine SET displacement = $1, no_of_cylinders = $2, car_range = $3, powertrain = $4, battery_kwh = $5, charge_power_kw = $6, motor_power_kw = $7, motor_torque_nm = $8, version = version + 1 WHERE id = $9 RETURNING version",
+		engineReq.Displacement, engineReq.NoOfCylinders, engineReq.CarRange,
+		engineReq.Type, engineReq.BatteryKWh, engineReq.ChargePowerKW, engineReq.MotorPowerKW, engineReq.MotorTorqueNm, engineID).
+		Scan(&engine.Version)
+
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventEngineUpdated, engine.EngineID, engine)
+	if err != nil {
+		return models.Engine{}, err
 	}
 
 	return engine, nil
 }
 
-func (e EngineStore) EngineDelete(ctx context.Context, id string) (models.Engine, error) {
+// EnginePatch writes only the columns whose value differs from the stored
+// row, and nothing at all when the patch changes nothing.
+func (e EngineStore) EnginePatch(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "EnginePatch-Store")
+	defer span.End()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Engine{}, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(); cmErr != nil {
+				fmt.Printf("Transaction commit error: %v\n", cmErr)
+			}
+		}
+	}()
+
+	var engine models.Engine
+	err = tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version FROM engine WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(
+		&engine.EngineID,
+		&engine.Displacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&engine.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = models.ErrEngineNotFound
+		}
+		return models.Engine{}, err
+	}
+
+	if expectedVersion != 0 && engine.Version != expectedVersion {
+		err = models.ErrVersionMismatch
+		return models.Engine{}, err
+	}
+
+	columns := []struct {
+		name    string
+		changed bool
+		value   interface{}
+	}{
+		{"displacement", engineReq.Displacement != engine.Displacement, engineReq.Displacement},
+		{"no_of_cylinders", engineReq.NoOfCylinders != engine.NoOfCylinders, engineReq.NoOfCylinders},
+		{"car_range", engineReq.CarRange != engine.CarRange, engineReq.CarRange},
+		{"powertrain", engineReq.Type != engine.Type, engineReq.Type},
+		{"battery_kwh", engineReq.BatteryKWh != engine.BatteryKWh, engineReq.BatteryKWh},
+		{"charge_power_kw", engineReq.ChargePowerKW != engine.ChargePowerKW, engineReq.ChargePowerKW},
+		{"motor_power_kw", engineReq.MotorPowerKW != engine.MotorPowerKW, engineReq.MotorPowerKW},
+		{"motor_torque_nm", engineReq.MotorTorqueNm != engine.MotorTorqueNm, engineReq.MotorTorqueNm},
+	}
+
+	args := []interface{}{id}
+	var set []string
+	for _, column := range columns {
+		if column.changed {
+			args = append(args, column.value)
+			set = append(set, fmt.Sprintf("%s = $%d", column.name, len(args)))
+		}
+	}
+
+	if len(set) == 0 {
+		return engine, nil
+	}
+
+	query := "UPDATE engine SET " + strings.Join(set, ", ") +
+		", version = version + 1 WHERE id = $1 RETURNING displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version"
+
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&engine.Displacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&engine.Version,
+	)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	err = outbox.Record(ctx, tx, models.EventEngineUpdated, engine.EngineID, engine)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	return engine, nil
+}
+
+func (e EngineStore) EngineDelete(ctx context.Context, id string, expectedVersion int64) (models.Engine, error) {
 	tracer := otel.Tracer("EngineStore")
 	ctx, span := tracer.Start(ctx, "EngineDelete-Store")
 	defer span.End()
@@ -173,11 +365,17 @@ func (e EngineStore) EngineDelete(ctx context.Context, id string) (models.Engine
 		}
 	}()
 
-	err = tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range FROM engine WHERE id=$1", id).Scan(
+	err = tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, version FROM engine WHERE id=$1 AND deleted_at IS NULL FOR UPDATE", id).Scan(
 		&engine.EngineID,
 		&engine.Displacement,
 		&engine.NoOfCylinders,
 		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&engine.Version,
 	)
 
 	if err != nil {
@@ -187,7 +385,19 @@ func (e EngineStore) EngineDelete(ctx context.Context, id string) (models.Engine
 		return engine, err
 	}
 
-	result, err := tx.ExecContext(ctx, "DELETE FROM engine WHERE id=$1", id)
+	if expectedVersion != 0 && engine.Version != expectedVersion {
+		err = models.ErrVersionMismatch
+		return models.Engine{}, err
+	}
+
+	deletedAt := time.Now()
+
+	err = softDeleteCars(ctx, tx, engine, deletedAt)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	result, err := tx.ExecContext(ctx, "UPDATE engine SET deleted_at = $2, version = version + 1 WHERE id=$1 AND deleted_at IS NULL", id, deletedAt)
 	if err != nil {
 		return models.Engine{}, err
 	}
@@ -200,5 +410,395 @@ func (e EngineStore) EngineDelete(ctx context.Context, id string) (models.Engine
 		return models.Engine{}, errors.New("No Rows were Updated")
 	}
 
+	engine.Version++
+	engine.DeletedAt = &deletedAt
+
+	err = outbox.Record(ctx, tx, models.EventEngineDeleted, engine.EngineID, engine)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
 	return engine, nil
 }
+
+func (e EngineStore) ListEngines(ctx context.Context, filter models.EngineFilter) (models.EnginePage, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "ListEngines-Store")
+	defer span.End()
+
+	var page models.EnginePage
+	page.Limit = listquery.Limit(filter.Limit)
+
+	q := buildFilterQuery(filter)
+
+	carJoin := "c.engine_id = e.id"
+	if !filter.IncludeDeleted {
+		carJoin += " AND c.deleted_at IS NULL"
+	}
+
+	err := e.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM engine e"+q.Where(), q.Args...).Scan(&page.TotalCount)
+	if err != nil {
+		return page, err
+	}
+
+	if filter.Cursor != "" {
+		after, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return page, err
+		}
+		q.Add("e.id > $%d", after)
+	}
+
+	var query string
+	if filter.WithCars {
+		query = `SELECT e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version, e.deleted_at, COUNT(c.id), COALESCE(ARRAY_AGG(c.id::text) FILTER (WHERE c.id IS NOT NULL), '{}') FROM engine e LEFT JOIN car c ON ` + carJoin +
+			q.Where() + " GROUP BY e.id"
+	} else {
+		query = `SELECT e.id, e.displacement, e.no_of_cylinders, e.car_range, e.powertrain, e.battery_kwh, e.charge_power_kw, e.motor_power_kw, e.motor_torque_nm, e.version, e.deleted_at FROM engine e` + q.Where()
+	}
+	query += fmt.Sprintf(" ORDER BY e.id LIMIT %d", page.Limit+1)
+
+	rows, err := e.db.QueryContext(ctx, query, q.Args...)
+	if err != nil {
+		return page, err
+	}
+
+	defer rows.Close()
+
+	page.Engines = make([]models.EngineListItem, 0, page.Limit)
+
+	for rows.Next() {
+		if len(page.Engines) == page.Limit {
+			page.NextCursor = encodeCursor(page.Engines[len(page.Engines)-1].EngineID)
+			break
+		}
+
+		var item models.EngineListItem
+		if filter.WithCars {
+			var carCount int
+			var carIDs []string
+			err = rows.Scan(
+				&item.EngineID,
+				&item.Displacement,
+				&item.NoOfCylinders,
+				&item.CarRange,
+				&item.Type,
+				&item.BatteryKWh,
+				&item.ChargePowerKW,
+				&item.MotorPowerKW,
+				&item.MotorTorqueNm,
+				&item.Version,
+				&item.DeletedAt,
+				&carCount,
+				pq.Array(&carIDs),
+			)
+			if err != nil {
+				return page, err
+			}
+
+			item.CarCount = &carCount
+			item.CarIDs = make([]uuid.UUID, 0, len(carIDs))
+			for _, id := range carIDs {
+				carID, err := uuid.Parse(id)
+				if err != nil {
+					return page, err
+				}
+				item.CarIDs = append(item.CarIDs, carID)
+			}
+		} else {
+			err = rows.Scan(
+				&item.EngineID,
+				&item.Displacement,
+				&item.NoOfCylinders,
+				&item.CarRange,
+				&item.Type,
+				&item.BatteryKWh,
+				&item.ChargePowerKW,
+				&item.MotorPowerKW,
+				&item.MotorTorqueNm,
+				&item.Version,
+				&item.DeletedAt,
+			)
+			if err != nil {
+				return page, err
+			}
+		}
+		page.Engines = append(page.Engines, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return page, err
+	}
+
+	return page, nil
+}
+
+// softDeleteCars marks every live car of the engine as deleted at the same
+// time as the engine, so restoring the engine can bring exactly those cars
+// back. Each car gets its own history entry and event.
+func softDeleteCars(ctx context.Context, tx *sql.Tx, engine models.Engine, deletedAt time.Time) error {
+	cars, err := selectCars(ctx, tx, "SELECT id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at FROM car WHERE engine_id = $1 AND deleted_at IS NULL FOR UPDATE", engine.EngineID)
+	if err != nil {
+		return err
+	}
+
+	for i := range cars {
+		cars[i].Engine = engine
+		if err := carStore.RecordChange(ctx, tx, cars[i].ID, models.CarChangeDelete, &cars[i], nil); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE car SET deleted_at = $2, version = version + 1 WHERE engine_id = $1 AND deleted_at IS NULL", engine.EngineID, deletedAt)
+	if err != nil {
+		return err
+	}
+
+	for i := range cars {
+		cars[i].Version++
+		cars[i].DeletedAt = &deletedAt
+	}
+
+	return recordCarEvents(ctx, tx, models.EventCarDeleted, cars)
+}
+
+// recordCarEvents adds an event of eventType for each of cars to the
+// outbox.
+func recordCarEvents(ctx context.Context, tx *sql.Tx, eventType string, cars []models.Car) error {
+	events := make([]models.Event, 0, len(cars))
+	for _, car := range cars {
+		event, err := models.NewEvent(eventType, car.ID, car)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+
+	return outbox.Append(ctx, tx, events...)
+}
+
+// RestoreEngine clears deleted_at on a soft-deleted engine together with
+// the cars that were deleted along with it.
+func (e EngineStore) RestoreEngine(ctx context.Context, id string) (models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "RestoreEngine-Store")
+	defer span.End()
+
+	var engine models.Engine
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return engine, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(); cmErr != nil {
+				fmt.Printf("Transaction commit error: %v\n", cmErr)
+			}
+		}
+	}()
+
+	var deletedAt *time.Time
+	err = tx.QueryRowContext(ctx, "SELECT id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm, deleted_at FROM engine WHERE id=$1 FOR UPDATE", id).Scan(
+		&engine.EngineID,
+		&engine.Displacement,
+		&engine.NoOfCylinders,
+		&engine.CarRange,
+		&engine.Type,
+		&engine.BatteryKWh,
+		&engine.ChargePowerKW,
+		&engine.MotorPowerKW,
+		&engine.MotorTorqueNm,
+		&deletedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Engine{}, models.ErrEngineNotFound
+		}
+		return models.Engine{}, err
+	}
+
+	if deletedAt == nil {
+		err = models.ErrEngineNotDeleted
+		return models.Engine{}, err
+	}
+
+	err = tx.QueryRowContext(ctx, "UPDATE engine SET deleted_at = NULL, version = version + 1 WHERE id=$1 RETURNING version", id).Scan(&engine.Version)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	cars, err := selectCars(ctx, tx,
+		"UPDATE car SET deleted_at = NULL, updated_at = $3, version = version + 1 WHERE engine_id = $1 AND deleted_at = $2 RETURNING id, name, year, brand, fuel_type, COALESCE(vin, ''), engine_id, price, version, created_at, updated_at",
+		id, *deletedAt, time.Now())
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	for i := range cars {
+		cars[i].Engine = engine
+		err = carStore.RecordChange(ctx, tx, cars[i].ID, models.CarChangeRestore, nil, &cars[i])
+		if err != nil {
+			return models.Engine{}, err
+		}
+	}
+
+	err = outbox.Record(ctx, tx, models.EventEngineRestored, engine.EngineID, engine)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	err = recordCarEvents(ctx, tx, models.EventCarRestored, cars)
+	if err != nil {
+		return models.Engine{}, err
+	}
+
+	return engine, nil
+}
+
+// PurgeEngine hard-deletes an engine that is already soft-deleted. Its
+// cars, which were deleted with it, are removed first rather than through
+// fk_engine_id ON DELETE CASCADE, so each gets a purge event.
+func (e EngineStore) PurgeEngine(ctx context.Context, id string) error {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "PurgeEngine-Store")
+	defer span.End()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(); cmErr != nil {
+				fmt.Printf("Transaction commit error: %v\n", cmErr)
+			}
+		}
+	}()
+
+	var engineID uuid.UUID
+	var deletedAt *time.Time
+	err = tx.QueryRowContext(ctx, "SELECT id, deleted_at FROM engine WHERE id=$1 FOR UPDATE", id).Scan(&engineID, &deletedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = models.ErrEngineNotFound
+		}
+		return err
+	}
+	if deletedAt == nil {
+		err = models.ErrEngineNotDeleted
+		return err
+	}
+
+	carIDs, err := carStore.DeleteReturningIDs(ctx, tx, "DELETE FROM car WHERE engine_id=$1 RETURNING id", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM engine WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+
+	err = appendPurgeEvents(ctx, tx, carIDs, []uuid.UUID{engineID})
+	return err
+}
+
+// appendPurgeEvents adds purge events for the removed cars and then for
+// the removed engines to the outbox.
+func appendPurgeEvents(ctx context.Context, tx *sql.Tx, carIDs []uuid.UUID, engineIDs []uuid.UUID) error {
+	carEvents, err := outbox.PurgeEvents(models.EventCarPurged, carIDs)
+	if err != nil {
+		return err
+	}
+
+	engineEvents, err := outbox.PurgeEvents(models.EventEnginePurged, engineIDs)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Append(ctx, tx, append(carEvents, engineEvents...)...)
+}
+
+// PurgeDeletedEngines hard-deletes every engine soft-deleted before
+// olderThan and returns how many were removed.
+func (e EngineStore) PurgeDeletedEngines(ctx context.Context, olderThan time.Time) (int64, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "PurgeDeletedEngines-Store")
+	defer span.End()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(); cmErr != nil {
+				fmt.Printf("Transaction commit error: %v\n", cmErr)
+			}
+		}
+	}()
+
+	carIDs, err := carStore.DeleteReturningIDs(ctx, tx,
+		"DELETE FROM car WHERE engine_id IN (SELECT id FROM engine WHERE deleted_at IS NOT NULL AND deleted_at < $1) RETURNING id", olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	engineIDs, err := carStore.DeleteReturningIDs(ctx, tx, "DELETE FROM engine WHERE deleted_at IS NOT NULL AND deleted_at < $1 RETURNING id", olderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	err = appendPurgeEvents(ctx, tx, carIDs, engineIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(engineIDs)), nil
+}
+
+func selectCars(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]models.Car, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var cars []models.Car
+	for rows.Next() {
+		var car models.Car
+		err := rows.Scan(
+			&car.ID,
+			&car.Name,
+			&car.Year,
+			&car.Brand,
+			&car.FuelType,
+			&car.VIN,
+			&car.Engine.EngineID,
+			&car.Price,
+			&car.Version,
+			&car.CreatedAt,
+			&car.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		cars = append(cars, car)
+	}
+
+	return cars, rows.Err()
+}
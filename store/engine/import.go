@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store/outbox"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+// importBatchSize keeps each multi-row INSERT well under Postgres' limit
+// of 65535 bind parameters.
+const importBatchSize = 1000
+
+// engineColumns is the number of columns each imported engine fills.
+const engineColumns = 9
+
+// EngineCreateMany inserts all engines in one transaction, in batches of
+// importBatchSize rows.
+func (e EngineStore) EngineCreateMany(ctx context.Context, engineReqs []models.EngineRequest) ([]models.Engine, error) {
+	tracer := otel.Tracer("EngineStore")
+	ctx, span := tracer.Start(ctx, "EngineCreateMany-Store")
+	defer span.End()
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+		} else {
+			if cmErr := tx.Commit(); cmErr != nil {
+				fmt.Printf("Transaction commit error: %v\n", cmErr)
+			}
+		}
+	}()
+
+	engines := make([]models.Engine, 0, len(engineReqs))
+	for start := 0; start < len(engineReqs); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(engineReqs) {
+			end = len(engineReqs)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*engineColumns)
+		for _, engineReq := range engineReqs[start:end] {
+			engine := models.Engine{
+				EngineID:      uuid.New(),
+				Displacement:  engineReq.Displacement,
+				NoOfCylinders: engineReq.NoOfCylinders,
+				CarRange:      engineReq.CarRange,
+				Powertrain:    engineReq.Powertrain,
+				Version:       1,
+			}
+			engines = append(engines, engine)
+
+			n := len(args)
+			args = append(args, engine.EngineID, engine.Displacement, engine.NoOfCylinders, engine.CarRange,
+				engine.Type, engine.BatteryKWh, engine.ChargePowerKW, engine.MotorPowerKW, engine.MotorTorqueNm)
+			placeholders := make([]string, engineColumns)
+			for i := range placeholders {
+				placeholders[i] = fmt.Sprintf("$%d", n+i+1)
+			}
+			values = append(values, "("+strings.Join(placeholders, ", ")+")")
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO engine (id, displacement, no_of_cylinders, car_range, powertrain, battery_kwh, charge_power_kw, motor_power_kw, motor_torque_nm) VALUES "+strings.Join(values, ", "),
+			args...)
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]models.Event, 0, end-start)
+		for _, engine := range engines[start:end] {
+			event, eventErr := models.NewEvent(models.EventEngineCreated, engine.EngineID, engine)
+			if eventErr != nil {
+				err = eventErr
+				return nil, err
+			}
+			events = append(events, event)
+		}
+
+		err = outbox.Append(ctx, tx, events...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return engines, nil
+}
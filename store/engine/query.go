@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"Car-Management-System/models"
+	"Car-Management-System/store/listquery"
+
+	"github.com/google/uuid"
+)
+
+func buildFilterQuery(filter models.EngineFilter) *listquery.Builder {
+	q := &listquery.Builder{}
+
+	if !filter.IncludeDeleted {
+		q.Add("e.deleted_at IS NULL")
+	}
+
+	if filter.MinDisplacement > 0 {
+		q.Add("e.displacement >= $%d", filter.MinDisplacement)
+	}
+	if filter.MaxDisplacement > 0 {
+		q.Add("e.displacement <= $%d", filter.MaxDisplacement)
+	}
+	if filter.Cylinders > 0 {
+		q.Add("e.no_of_cylinders = $%d", filter.Cylinders)
+	}
+	if filter.MinRange > 0 {
+		q.Add("e.car_range >= $%d", filter.MinRange)
+	}
+	if filter.MaxRange > 0 {
+		q.Add("e.car_range <= $%d", filter.MaxRange)
+	}
+
+	return q
+}
+
+func encodeCursor(id uuid.UUID) string {
+	return listquery.EncodeCursor([]byte(id.String()))
+}
+
+func decodeCursor(s string) (uuid.UUID, error) {
+	raw, err := listquery.DecodeCursor(s)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	id, err := uuid.Parse(string(raw))
+	if err != nil {
+		return uuid.Nil, models.ErrInvalidCursor
+	}
+	return id, nil
+}
@@ -3,19 +3,102 @@ package store
 import (
 	"Car-Management-System/models"
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type CarStoreInterface interface {
-	GetCarById(ctx context.Context, id string) (models.Car, error)
-	GetCarByBrand(ctx context.Context, brand string, isEngine bool) ([]models.Car, error)
+	GetCarById(ctx context.Context, id string, includeDeleted bool) (models.Car, error)
+	GetCarByVIN(ctx context.Context, vin string, includeDeleted bool) (models.Car, error)
 	CreateCar(ctx context.Context, carReq *models.CarRequest) (models.Car, error)
-	UpdateCar(ctx context.Context, id string, carReq *models.CarRequest) (models.Car, error)
-	DeleteCar(ctx context.Context, id string) (models.Car, error)
+	CreateCars(ctx context.Context, carReqs []models.CarRequest) ([]models.Car, error)
+	GetEnginesByIds(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Engine, error)
+	UpdateCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (models.Car, error)
+	PatchCar(ctx context.Context, id string, carReq *models.CarRequest, expectedVersion int64) (models.Car, error)
+	DeleteCar(ctx context.Context, id string, expectedVersion int64) (models.Car, error)
+	ListCars(ctx context.Context, filter models.CarFilter) (models.CarPage, error)
+	ExportCars(ctx context.Context, filter models.CarFilter, fn func(models.Car) error) error
+	GetCarHistory(ctx context.Context, id string) ([]models.CarHistory, error)
+	GetCarAsOf(ctx context.Context, id string, asOf time.Time) (models.Car, error)
+	RestoreCar(ctx context.Context, id string) (models.Car, error)
+	PurgeCar(ctx context.Context, id string) error
+	PurgeDeletedCars(ctx context.Context, olderThan time.Time) (int64, error)
 }
 
 type EngineStoreInterface interface {
-	EngineById(ctx context.Context, id string) (models.Engine, error)
+	EngineById(ctx context.Context, id string, includeDeleted bool) (models.Engine, error)
+	EnginesByIds(ctx context.Context, ids []uuid.UUID, includeDeleted bool) (map[uuid.UUID]models.Engine, error)
 	EngineCreate(ctx context.Context, engineReq *models.EngineRequest) (models.Engine, error)
-	EngineUpdate(ctx context.Context, id string, engineReq *models.EngineRequest) (models.Engine, error)
-	EngineDelete(ctx context.Context, id string) (models.Engine, error)
+	EngineCreateMany(ctx context.Context, engineReqs []models.EngineRequest) ([]models.Engine, error)
+	EngineUpdate(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (models.Engine, error)
+	EnginePatch(ctx context.Context, id string, engineReq *models.EngineRequest, expectedVersion int64) (models.Engine, error)
+	EngineDelete(ctx context.Context, id string, expectedVersion int64) (models.Engine, error)
+	ListEngines(ctx context.Context, filter models.EngineFilter) (models.EnginePage, error)
+	RestoreEngine(ctx context.Context, id string) (models.Engine, error)
+	PurgeEngine(ctx context.Context, id string) error
+	PurgeDeletedEngines(ctx context.Context, olderThan time.Time) (int64, error)
+}
+
+type UserStoreInterface interface {
+	GetUserByUsername(ctx context.Context, userName string) (models.User, error)
+	GetUserById(ctx context.Context, id string) (models.User, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+	CreateUser(ctx context.Context, userName string, passwordHash string, role string) (models.User, error)
+	UpdateUserPassword(ctx context.Context, id string, passwordHash string) error
+	SetUserDisabled(ctx context.Context, id string, disabled bool) (models.User, error)
+	SetUserRole(ctx context.Context, id string, role string) (models.User, error)
+	DeleteUser(ctx context.Context, id string) (models.User, error)
+}
+
+type TokenStoreInterface interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldID string, next models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+type APIKeyStoreInterface interface {
+	CreateAPIKey(ctx context.Context, key models.APIKey) error
+	GetAPIKeyById(ctx context.Context, id string) (models.APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, models.User, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) (models.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string, usedAt time.Time) error
+}
+
+type JobStoreInterface interface {
+	CreateJob(ctx context.Context, job models.Job) error
+	GetJobById(ctx context.Context, id string) (models.Job, error)
+	ClaimJob(ctx context.Context, types []string, staleBefore time.Time, maxAttempts int) (models.Job, error)
+	TouchJob(ctx context.Context, id string, done int64, total int64) (bool, error)
+	FinishJob(ctx context.Context, id string, status string, resultLocation string, errMsg string) error
+	CancelJob(ctx context.Context, id string) (models.Job, error)
+	FailStaleJobs(ctx context.Context, staleBefore time.Time, maxAttempts int) (int64, error)
+}
+
+type WebhookStoreInterface interface {
+	CreateWebhook(ctx context.Context, webhook models.Webhook) error
+	GetWebhookById(ctx context.Context, id string) (models.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]models.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	EnqueueDeliveries(ctx context.Context, event models.Event) (int64, error)
+	ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error)
+	DeleteDelivery(ctx context.Context, id int64) error
+	RetryDelivery(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	DeadLetterDelivery(ctx context.Context, id int64, lastError string) error
+	ListDeadLetters(ctx context.Context, webhookID string) ([]models.DeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id int64) (models.DeadLetter, error)
+}
+
+type OutboxStoreInterface interface {
+	RelayPending(ctx context.Context, limit int, publish func(models.Event) error) (int, error)
+	PendingStats(ctx context.Context) (int64, *time.Time, error)
+	DeletePublished(ctx context.Context, olderThan time.Time) (int64, error)
+	ListPublished(ctx context.Context, after models.EventCursor, limit int) ([]models.Event, error)
+	LatestCursor(ctx context.Context) (models.EventCursor, error)
+	PublishedCursor(ctx context.Context, sequence int64) (models.EventCursor, error)
 }
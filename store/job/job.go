@@ -0,0 +1,198 @@
+package job
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+const selectJob = `SELECT id, type, status, params, progress_done, progress_total, result_location, error, attempts, created_by, created_at, started_at, finished_at FROM jobs`
+
+type JobStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *JobStore {
+	return &JobStore{db: db}
+}
+
+func (j JobStore) CreateJob(ctx context.Context, job models.Job) error {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "CreateJob-Store")
+	defer span.End()
+
+	_, err := j.db.ExecContext(ctx,
+		"INSERT INTO jobs (id, type, status, params, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		job.ID, job.Type, job.Status, string(job.Params), sql.NullString{String: job.CreatedBy, Valid: job.CreatedBy != ""}, job.CreatedAt)
+	return err
+}
+
+func (j JobStore) GetJobById(ctx context.Context, id string) (models.Job, error) {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "GetJobById-Store")
+	defer span.End()
+
+	job, err := scanJob(j.db.QueryRowContext(ctx, selectJob+" WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return job, models.ErrJobNotFound
+		}
+		return job, err
+	}
+
+	return job, nil
+}
+
+// ClaimJob marks the oldest claimable job of one of types as running and
+// returns it. A running job whose heartbeat is older than staleBefore is
+// claimable again, as its worker is assumed dead, until it has been tried
+// maxAttempts times. SKIP LOCKED lets several workers claim at once
+// without waiting on each other. It returns ErrJobNotFound when there is
+// nothing to do.
+func (j JobStore) ClaimJob(ctx context.Context, types []string, staleBefore time.Time, maxAttempts int) (models.Job, error) {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "ClaimJob-Store")
+	defer span.End()
+
+	job, err := scanJob(j.db.QueryRowContext(ctx,
+		`UPDATE jobs SET status = $1, attempts = attempts + 1, started_at = $2, heartbeat_at = $2, progress_done = 0
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type = ANY($3) AND (status = $4 OR (status = $1 AND heartbeat_at < $5 AND attempts < $6))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, type, status, params, progress_done, progress_total, result_location, error, attempts, created_by, created_at, started_at, finished_at`,
+		models.JobRunning, time.Now(), pq.Array(types), models.JobQueued, staleBefore, maxAttempts))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return job, models.ErrJobNotFound
+		}
+		return job, err
+	}
+
+	return job, nil
+}
+
+// TouchJob records the progress of a running job and refreshes its
+// heartbeat. It reports false once the job is no longer running, which is
+// how a worker learns that the job was cancelled.
+func (j JobStore) TouchJob(ctx context.Context, id string, done int64, total int64) (bool, error) {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "TouchJob-Store")
+	defer span.End()
+
+	result, err := j.db.ExecContext(ctx,
+		"UPDATE jobs SET progress_done = $2, progress_total = $3, heartbeat_at = $4 WHERE id = $1 AND status = $5",
+		id, done, total, time.Now(), models.JobRunning)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// FinishJob moves a running job to status. A job that was cancelled in the
+// meantime keeps its cancelled status.
+func (j JobStore) FinishJob(ctx context.Context, id string, status string, resultLocation string, errMsg string) error {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "FinishJob-Store")
+	defer span.End()
+
+	_, err := j.db.ExecContext(ctx,
+		"UPDATE jobs SET status = $2, result_location = $3, error = $4, finished_at = $5 WHERE id = $1 AND status = $6",
+		id, status, sql.NullString{String: resultLocation, Valid: resultLocation != ""}, sql.NullString{String: errMsg, Valid: errMsg != ""}, time.Now(), models.JobRunning)
+	return err
+}
+
+// CancelJob cancels a queued or running job. A running job stops at its
+// worker's next heartbeat.
+func (j JobStore) CancelJob(ctx context.Context, id string) (models.Job, error) {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "CancelJob-Store")
+	defer span.End()
+
+	result, err := j.db.ExecContext(ctx,
+		"UPDATE jobs SET status = $2, finished_at = $3 WHERE id = $1 AND status IN ($4, $5)",
+		id, models.JobCancelled, time.Now(), models.JobQueued, models.JobRunning)
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	job, err := j.GetJobById(ctx, id)
+	if err != nil {
+		return job, err
+	}
+
+	if rowsAffected == 0 {
+		return job, models.ErrJobFinished
+	}
+
+	return job, nil
+}
+
+// FailStaleJobs fails running jobs whose heartbeat is older than
+// staleBefore and that have no attempts left, so they do not stay running
+// forever.
+func (j JobStore) FailStaleJobs(ctx context.Context, staleBefore time.Time, maxAttempts int) (int64, error) {
+	tracer := otel.Tracer("JobStore")
+	ctx, span := tracer.Start(ctx, "FailStaleJobs-Store")
+	defer span.End()
+
+	result, err := j.db.ExecContext(ctx,
+		"UPDATE jobs SET status = $1, error = $2, finished_at = $3 WHERE status = $4 AND heartbeat_at < $5 AND attempts >= $6",
+		models.JobFailed, "worker stopped responding", time.Now(), models.JobRunning, staleBefore, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (models.Job, error) {
+	var job models.Job
+	var params []byte
+	var resultLocation, errMsg, createdBy sql.NullString
+
+	err := row.Scan(
+		&job.ID,
+		&job.Type,
+		&job.Status,
+		&params,
+		&job.ProgressDone,
+		&job.ProgressTotal,
+		&resultLocation,
+		&errMsg,
+		&job.Attempts,
+		&createdBy,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+
+	job.Params = params
+	job.ResultLocation = resultLocation.String
+	job.Error = errMsg.String
+	job.CreatedBy = createdBy.String
+	return job, err
+}
@@ -0,0 +1,69 @@
+// Package listquery holds the pieces the stores share to build paginated
+// list queries: WHERE clauses with bound arguments, page size limits and
+// opaque cursors.
+package listquery
+
+import (
+	"Car-Management-System/models"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Builder accumulates WHERE conditions and their bound arguments so that
+// no filter value is ever interpolated into the SQL text.
+type Builder struct {
+	Args       []interface{}
+	conditions []string
+}
+
+// Add appends condition, which has a $%d verb for each of values. The
+// verbs are replaced by the placeholders of the values.
+func (q *Builder) Add(condition string, values ...interface{}) {
+	placeholders := make([]interface{}, len(values))
+	for i, v := range values {
+		q.Args = append(q.Args, v)
+		placeholders[i] = len(q.Args)
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+// Where returns the conditions as a WHERE clause, or "" if there are none.
+func (q *Builder) Where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// Limit clamps a requested page size to between 1 and MaxLimit, with
+// DefaultLimit for none.
+func Limit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// EncodeCursor makes raw safe to pass around in a URL.
+func EncodeCursor(raw []byte) string {
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reverses EncodeCursor, reporting ErrInvalidCursor for
+// anything it did not produce.
+func DecodeCursor(s string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	return raw, nil
+}
@@ -0,0 +1,300 @@
+package outbox
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+// relayLockID is the key of the transaction-level advisory lock held by
+// the relay publishing a batch, so only one relay publishes at a time and
+// events keep their order.
+const relayLockID int64 = 727360382
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Record adds an event of eventType about aggregateID to the outbox. Like
+// RecordChange for car history, it is meant to run in the same transaction
+// as the change, so the event exists exactly when the change is committed.
+func Record(ctx context.Context, exec execer, eventType string, aggregateID uuid.UUID, data interface{}) error {
+	event, err := models.NewEvent(eventType, aggregateID, data)
+	if err != nil {
+		return err
+	}
+	return Append(ctx, exec, event)
+}
+
+// appendBatchSize keeps each INSERT of Append well under the 65535
+// parameters Postgres allows in one statement.
+const appendBatchSize = 1000
+
+// Append adds events to the outbox with multi-row INSERTs, in the order
+// given.
+func Append(ctx context.Context, exec execer, events ...models.Event) error {
+	for start := 0; start < len(events); start += appendBatchSize {
+		end := start + appendBatchSize
+		if end > len(events) {
+			end = len(events)
+		}
+
+		values := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*5)
+		for _, event := range events[start:end] {
+			args = append(args, event.ID, event.AggregateID, event.Type, string(event.Data), event.OccurredAt)
+			n := len(args)
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", n-4, n-3, n-2, n-1, n))
+		}
+
+		_, err := exec.ExecContext(ctx,
+			"INSERT INTO outbox (event_id, aggregate_id, event_type, payload, occurred_at) VALUES "+strings.Join(values, ", "),
+			args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PurgeEvents returns an event of eventType for each of ids, carrying only
+// the id, as purged cars and engines no longer exist.
+func PurgeEvents(eventType string, ids []uuid.UUID) ([]models.Event, error) {
+	events := make([]models.Event, 0, len(ids))
+	for _, id := range ids {
+		event, err := models.NewEvent(eventType, id, map[string]uuid.UUID{"id": id})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type OutboxStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// RelayPending passes up to limit unpublished events to publish, oldest
+// first, and marks the ones it accepted as published. Once publish fails
+// for an event, later events of the same aggregate are held back until it
+// succeeds, so each aggregate's events are published in order. If another
+// relay holds the lock it returns straight away.
+//
+// Events of one aggregate are written while its row is locked, so they
+// are committed, and numbered, in the order the changes happened.
+func (o OutboxStore) RelayPending(ctx context.Context, limit int, publish func(models.Event) error) (int, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "RelayPending-Store")
+	defer span.End()
+
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	err = tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", relayLockID).Scan(&locked)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	events, err := selectPending(ctx, tx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var published []int64
+	held := map[uuid.UUID]bool{}
+	for _, event := range events {
+		if held[event.AggregateID] {
+			continue
+		}
+
+		if publishErr := publish(event); publishErr != nil {
+			held[event.AggregateID] = true
+			_, err := tx.ExecContext(ctx, "UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1", event.Sequence, publishErr.Error())
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+		published = append(published, event.Sequence)
+	}
+
+	// clock_timestamp() is taken after the lock, so each batch is stamped
+	// later than every batch committed before it. ListPublished relies on
+	// this.
+	if len(published) > 0 {
+		_, err := tx.ExecContext(ctx, "UPDATE outbox SET published_at = clock_timestamp() WHERE id = ANY($1)", pq.Array(published))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// Should the commit fail, the events stay unpublished and are
+	// published again by the next run.
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return len(published), nil
+}
+
+func selectPending(ctx context.Context, tx *sql.Tx, limit int) ([]models.Event, error) {
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id, event_id, aggregate_id, event_type, payload, occurred_at FROM outbox WHERE published_at IS NULL ORDER BY id LIMIT $1",
+		limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		var payload []byte
+
+		err := rows.Scan(
+			&event.Sequence,
+			&event.ID,
+			&event.AggregateID,
+			&event.Type,
+			&payload,
+			&event.OccurredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Data = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// ListPublished returns up to limit published events that come after
+// cursor, in the order they were published.
+func (o OutboxStore) ListPublished(ctx context.Context, after models.EventCursor, limit int) ([]models.Event, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "ListPublished-Store")
+	defer span.End()
+
+	rows, err := o.db.QueryContext(ctx,
+		"SELECT id, event_id, aggregate_id, event_type, payload, occurred_at, published_at FROM outbox WHERE published_at IS NOT NULL AND (published_at, id) > ($1, $2) ORDER BY published_at, id LIMIT $3",
+		after.PublishedAt, after.Sequence, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		var payload []byte
+
+		err := rows.Scan(
+			&event.Sequence,
+			&event.ID,
+			&event.AggregateID,
+			&event.Type,
+			&payload,
+			&event.OccurredAt,
+			&event.PublishedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		event.Data = payload
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// LatestCursor returns the position of the last published event, or the
+// zero cursor when none has been published.
+func (o OutboxStore) LatestCursor(ctx context.Context) (models.EventCursor, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "LatestCursor-Store")
+	defer span.End()
+
+	var cursor models.EventCursor
+
+	err := o.db.QueryRowContext(ctx,
+		"SELECT published_at, id FROM outbox WHERE published_at IS NOT NULL ORDER BY published_at DESC, id DESC LIMIT 1").
+		Scan(&cursor.PublishedAt, &cursor.Sequence)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.EventCursor{}, err
+	}
+
+	return cursor, nil
+}
+
+// PublishedCursor returns the position of the published event with the
+// given sequence, or ErrEventNotFound if it has not been published or has
+// been removed already.
+func (o OutboxStore) PublishedCursor(ctx context.Context, sequence int64) (models.EventCursor, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "PublishedCursor-Store")
+	defer span.End()
+
+	cursor := models.EventCursor{Sequence: sequence}
+
+	err := o.db.QueryRowContext(ctx, "SELECT published_at FROM outbox WHERE id = $1 AND published_at IS NOT NULL", sequence).
+		Scan(&cursor.PublishedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.EventCursor{}, models.ErrEventNotFound
+		}
+		return models.EventCursor{}, err
+	}
+
+	return cursor, nil
+}
+
+// PendingStats returns how many events are waiting to be published and
+// when the oldest of them happened, which is nil when none are waiting.
+func (o OutboxStore) PendingStats(ctx context.Context) (int64, *time.Time, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "PendingStats-Store")
+	defer span.End()
+
+	var pending int64
+	var oldest *time.Time
+
+	err := o.db.QueryRowContext(ctx, "SELECT COUNT(*), MIN(occurred_at) FROM outbox WHERE published_at IS NULL").Scan(&pending, &oldest)
+	return pending, oldest, err
+}
+
+// DeletePublished removes events published before olderThan and returns
+// how many were removed.
+func (o OutboxStore) DeletePublished(ctx context.Context, olderThan time.Time) (int64, error) {
+	tracer := otel.Tracer("OutboxStore")
+	ctx, span := tracer.Start(ctx, "DeletePublished-Store")
+	defer span.End()
+
+	result, err := o.db.ExecContext(ctx, "DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < $1", olderThan)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
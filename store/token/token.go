@@ -0,0 +1,178 @@
+package token
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+type TokenStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *TokenStore {
+	return &TokenStore{db: db}
+}
+
+func (t TokenStore) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "CreateRefreshToken-Store")
+	defer span.End()
+
+	return insertRefreshToken(ctx, t.db, token)
+}
+
+func (t TokenStore) GetRefreshToken(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "GetRefreshToken-Store")
+	defer span.End()
+
+	var token models.RefreshToken
+
+	err := t.db.QueryRowContext(ctx,
+		`SELECT id, user_id, family_id, token_hash, access_jti, access_expires_at, expires_at, used_at, revoked_at, created_at FROM refresh_tokens WHERE token_hash = $1`,
+		tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.AccessExpiresAt,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return token, models.ErrInvalidRefreshToken
+		}
+		return token, err
+	}
+
+	return token, nil
+}
+
+func (t TokenStore) RotateRefreshToken(ctx context.Context, oldID string, next models.RefreshToken) (err error) {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "RotateRefreshToken-Store")
+	defer span.End()
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL", oldID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		err = models.ErrRefreshTokenReused
+		return err
+	}
+
+	err = insertRefreshToken(ctx, tx, next)
+	return err
+}
+
+func (t TokenStore) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "RevokeFamily-Store")
+	defer span.End()
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("Transaction rollback error; %v\n", rbErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $2 WHERE family_id = $1 AND revoked_at IS NULL", familyID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO revoked_tokens (jti, expires_at)
+		SELECT access_jti, access_expires_at FROM refresh_tokens WHERE family_id = $1 AND access_expires_at > $2
+		ON CONFLICT (jti) DO NOTHING`, familyID, time.Now())
+	return err
+}
+
+func (t TokenStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "RevokeJTI-Store")
+	defer span.End()
+
+	_, err := t.db.ExecContext(ctx,
+		"INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING", jti, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < $1", time.Now())
+	return err
+}
+
+func (t TokenStore) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	tracer := otel.Tracer("TokenStore")
+	ctx, span := tracer.Start(ctx, "IsRevoked-Store")
+	defer span.End()
+
+	var revoked bool
+	err := t.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)", jti).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertRefreshToken(ctx context.Context, db execer, token models.RefreshToken) error {
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, access_jti, access_expires_at, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.AccessJTI,
+		token.AccessExpiresAt,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
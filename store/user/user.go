@@ -0,0 +1,251 @@
+package user
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+const uniqueViolation = "23505"
+
+type UserStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *UserStore {
+	return &UserStore{db: db}
+}
+
+func (u UserStore) GetUserByUsername(ctx context.Context, userName string) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "GetUserByUsername-Store")
+	defer span.End()
+
+	var user models.User
+
+	err := u.db.QueryRowContext(ctx, "SELECT id, username, password_hash, role, role_assigned, disabled, created_at, updated_at FROM users WHERE username = $1", userName).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.PasswordHash,
+		&user.Role,
+		&user.RoleAssigned,
+		&user.Disabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, models.ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (u UserStore) GetUserById(ctx context.Context, id string) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "GetUserById-Store")
+	defer span.End()
+
+	var user models.User
+
+	err := u.db.QueryRowContext(ctx, "SELECT id, username, password_hash, role, role_assigned, disabled, created_at, updated_at FROM users WHERE id = $1", id).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.PasswordHash,
+		&user.Role,
+		&user.RoleAssigned,
+		&user.Disabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, models.ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (u UserStore) ListUsers(ctx context.Context) ([]models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "ListUsers-Store")
+	defer span.End()
+
+	rows, err := u.db.QueryContext(ctx, "SELECT id, username, role, disabled, created_at, updated_at FROM users ORDER BY username")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.UserName,
+			&user.Role,
+			&user.Disabled,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (u UserStore) CreateUser(ctx context.Context, userName string, passwordHash string, role string) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "CreateUser-Store")
+	defer span.End()
+
+	now := time.Now()
+	user := models.User{
+		ID:           uuid.New(),
+		UserName:     userName,
+		PasswordHash: passwordHash,
+		Role:         role,
+		RoleAssigned: true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	_, err := u.db.ExecContext(ctx,
+		"INSERT INTO users (id, username, password_hash, role, role_assigned, disabled, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		user.ID, user.UserName, user.PasswordHash, user.Role, user.RoleAssigned, user.Disabled, user.CreatedAt, user.UpdatedAt)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return models.User{}, models.ErrUserExists
+		}
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (u UserStore) UpdateUserPassword(ctx context.Context, id string, passwordHash string) error {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "UpdateUserPassword-Store")
+	defer span.End()
+
+	result, err := u.db.ExecContext(ctx, "UPDATE users SET password_hash = $2, updated_at = $3 WHERE id = $1", id, passwordHash, time.Now())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (u UserStore) SetUserDisabled(ctx context.Context, id string, disabled bool) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "SetUserDisabled-Store")
+	defer span.End()
+
+	var user models.User
+
+	err := u.db.QueryRowContext(ctx,
+		"UPDATE users SET disabled = $2, updated_at = $3 WHERE id = $1 RETURNING id, username, role, disabled, created_at, updated_at",
+		id, disabled, time.Now()).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.Role,
+		&user.Disabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, models.ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (u UserStore) SetUserRole(ctx context.Context, id string, role string) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "SetUserRole-Store")
+	defer span.End()
+
+	var user models.User
+
+	err := u.db.QueryRowContext(ctx,
+		"UPDATE users SET role = $2, role_assigned = TRUE, updated_at = $3 WHERE id = $1 RETURNING id, username, role, role_assigned, disabled, created_at, updated_at",
+		id, role, time.Now()).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.Role,
+		&user.RoleAssigned,
+		&user.Disabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, models.ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (u UserStore) DeleteUser(ctx context.Context, id string) (models.User, error) {
+	tracer := otel.Tracer("UserStore")
+	ctx, span := tracer.Start(ctx, "DeleteUser-Store")
+	defer span.End()
+
+	var user models.User
+
+	err := u.db.QueryRowContext(ctx,
+		"DELETE FROM users WHERE id = $1 RETURNING id, username, role, disabled, created_at, updated_at", id).Scan(
+		&user.ID,
+		&user.UserName,
+		&user.Role,
+		&user.Disabled,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, models.ErrUserNotFound
+		}
+		return user, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,357 @@
+package webhook
+
+import (
+	"Car-Management-System/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+)
+
+const selectWebhook = `SELECT id, url, secret, events, active, created_by, created_at, updated_at FROM webhooks`
+
+const selectDeadLetter = `SELECT id, webhook_id, event_id, event_type, payload, attempts, last_error, failed_at, replayed_at FROM webhook_dead_letters`
+
+type WebhookStore struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+func (s WebhookStore) CreateWebhook(ctx context.Context, webhook models.Webhook) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "CreateWebhook-Store")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO webhooks (id, url, secret, events, active, created_by, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $7)",
+		webhook.ID, webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Active,
+		sql.NullString{String: webhook.CreatedBy, Valid: webhook.CreatedBy != ""}, webhook.CreatedAt)
+	return err
+}
+
+func (s WebhookStore) GetWebhookById(ctx context.Context, id string) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "GetWebhookById-Store")
+	defer span.End()
+
+	webhook, err := scanWebhook(s.db.QueryRowContext(ctx, selectWebhook+" WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhook, models.ErrWebhookNotFound
+		}
+		return webhook, err
+	}
+
+	return webhook, nil
+}
+
+func (s WebhookStore) ListWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListWebhooks-Store")
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx, selectWebhook+" ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (s WebhookStore) UpdateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "UpdateWebhook-Store")
+	defer span.End()
+
+	updated, err := scanWebhook(s.db.QueryRowContext(ctx,
+		"UPDATE webhooks SET url = $2, events = $3, active = $4, updated_at = $5 WHERE id = $1 RETURNING id, url, secret, events, active, created_by, created_at, updated_at",
+		webhook.ID, webhook.URL, pq.Array(webhook.Events), webhook.Active, time.Now()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return updated, models.ErrWebhookNotFound
+		}
+		return updated, err
+	}
+
+	return updated, nil
+}
+
+// DeleteWebhook removes a webhook together with its pending deliveries and
+// dead letters.
+func (s WebhookStore) DeleteWebhook(ctx context.Context, id string) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeleteWebhook-Store")
+	defer span.End()
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return models.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries queues event for every active webhook whose filters
+// select it and returns how many deliveries were queued.
+func (s WebhookStore) EnqueueDeliveries(ctx context.Context, event models.Event) (int64, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "EnqueueDeliveries-Store")
+	defer span.End()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+
+	resource, _, _ := strings.Cut(event.Type, ".")
+
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at, created_at)
+		SELECT id, $1::uuid, $2::text, $3::jsonb, $4::timestamptz, $4::timestamptz FROM webhooks
+		WHERE active AND (cardinality(events) = 0 OR $2::text = ANY(events) OR $5::text = ANY(events) OR '*' = ANY(events))`,
+		event.ID, event.Type, string(payload), time.Now(), resource+".*")
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ClaimDeliveries takes up to limit deliveries that are due, counts an
+// attempt for each and hides them from other dispatchers until
+// leaseUntil. A delivery whose dispatcher dies is retried once the lease
+// runs out. Deliveries of inactive webhooks wait until they are
+// reactivated.
+func (s WebhookStore) ClaimDeliveries(ctx context.Context, limit int, leaseUntil time.Time) ([]models.WebhookDelivery, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ClaimDeliveries-Store")
+	defer span.End()
+
+	rows, err := s.db.QueryContext(ctx,
+		`UPDATE webhook_deliveries d SET attempts = d.attempts + 1, next_attempt_at = $1
+		FROM webhooks w
+		WHERE d.webhook_id = w.id AND d.id IN (
+			SELECT q.id FROM webhook_deliveries q JOIN webhooks qw ON q.webhook_id = qw.id
+			WHERE q.next_attempt_at <= $2 AND qw.active
+			ORDER BY q.id
+			LIMIT $3
+			FOR UPDATE OF q SKIP LOCKED
+		)
+		RETURNING d.id, d.webhook_id, w.url, w.secret, d.event_id, d.event_type, d.payload, d.attempts, d.next_attempt_at`,
+		leaseUntil, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload []byte
+
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.URL,
+			&delivery.Secret,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (s WebhookStore) DeleteDelivery(ctx context.Context, id int64) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeleteDelivery-Store")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE id = $1", id)
+	return err
+}
+
+func (s WebhookStore) RetryDelivery(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "RetryDelivery-Store")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx,
+		"UPDATE webhook_deliveries SET next_attempt_at = $2, last_error = $3 WHERE id = $1",
+		id, nextAttemptAt, lastError)
+	return err
+}
+
+// DeadLetterDelivery moves a delivery that has run out of attempts to the
+// dead-letter table.
+func (s WebhookStore) DeadLetterDelivery(ctx context.Context, id int64, lastError string) error {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "DeadLetterDelivery-Store")
+	defer span.End()
+
+	_, err := s.db.ExecContext(ctx,
+		`WITH moved AS (
+			DELETE FROM webhook_deliveries WHERE id = $1
+			RETURNING webhook_id, event_id, event_type, payload, attempts
+		)
+		INSERT INTO webhook_dead_letters (webhook_id, event_id, event_type, payload, attempts, last_error, failed_at)
+		SELECT webhook_id, event_id, event_type, payload, attempts, $2, $3 FROM moved`,
+		id, lastError, time.Now())
+	return err
+}
+
+// ListDeadLetters lists dead letters, newest first, optionally for a
+// single webhook.
+func (s WebhookStore) ListDeadLetters(ctx context.Context, webhookID string) ([]models.DeadLetter, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ListDeadLetters-Store")
+	defer span.End()
+
+	query := selectDeadLetter
+	var args []interface{}
+	if webhookID != "" {
+		query += " WHERE webhook_id = $1"
+		args = append(args, webhookID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query+" ORDER BY failed_at DESC, id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deadLetters := []models.DeadLetter{}
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deadLetters, nil
+}
+
+// ReplayDeadLetter queues a dead letter for delivery again with a fresh
+// set of attempts.
+func (s WebhookStore) ReplayDeadLetter(ctx context.Context, id int64) (models.DeadLetter, error) {
+	tracer := otel.Tracer("WebhookStore")
+	ctx, span := tracer.Start(ctx, "ReplayDeadLetter-Store")
+	defer span.End()
+
+	deadLetter, err := scanDeadLetter(s.db.QueryRowContext(ctx,
+		`WITH replayed AS (
+			UPDATE webhook_dead_letters SET replayed_at = $2 WHERE id = $1
+			RETURNING id, webhook_id, event_id, event_type, payload, attempts, last_error, failed_at, replayed_at
+		), queued AS (
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, next_attempt_at, created_at)
+			SELECT webhook_id, event_id, event_type, payload, $2, $2 FROM replayed
+		)
+		SELECT id, webhook_id, event_id, event_type, payload, attempts, last_error, failed_at, replayed_at FROM replayed`,
+		id, time.Now()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return deadLetter, models.ErrDeadLetterNotFound
+		}
+		return deadLetter, err
+	}
+
+	return deadLetter, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanWebhook(row rowScanner) (models.Webhook, error) {
+	var webhook models.Webhook
+	var createdBy sql.NullString
+
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.Events),
+		&webhook.Active,
+		&createdBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	webhook.CreatedBy = createdBy.String
+	return webhook, err
+}
+
+func scanDeadLetter(row rowScanner) (models.DeadLetter, error) {
+	var deadLetter models.DeadLetter
+	var payload []byte
+
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.WebhookID,
+		&deadLetter.EventID,
+		&deadLetter.EventType,
+		&payload,
+		&deadLetter.Attempts,
+		&deadLetter.LastError,
+		&deadLetter.FailedAt,
+		&deadLetter.ReplayedAt,
+	)
+
+	deadLetter.Payload = payload
+	return deadLetter, err
+}
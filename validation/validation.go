@@ -0,0 +1,156 @@
+// Package validation checks values against declarative rules and reports
+// every violation, each with the path of the field at fault, a code for
+// programs and a message for people.
+package validation
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Codes of the built-in rules.
+const (
+	CodeRequired   = "required"
+	CodeOutOfRange = "out_of_range"
+	CodeNotAllowed = "not_allowed"
+	CodePattern    = "pattern"
+	CodeMismatch   = "mismatch"
+	CodeInvalid    = "invalid"
+)
+
+// Violation is one way in which a value breaks a rule. Field is the JSON
+// path of the field, with nested fields joined by dots.
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Rule checks one aspect of a T. It returns nil when the value passes.
+type Rule[T any] func(value T) []Violation
+
+// Rules is a set of rules that are all checked.
+type Rules[T any] []Rule[T]
+
+// Validate checks value against every rule and returns all violations in
+// rule order.
+func (rules Rules[T]) Validate(value T) []Violation {
+	var violations []Violation
+	for _, rule := range rules {
+		violations = append(violations, rule(value)...)
+	}
+	return violations
+}
+
+// Required reports field when it holds its zero value.
+func Required[T any, V comparable](field string, get func(T) V) Rule[T] {
+	return func(value T) []Violation {
+		var zero V
+		if get(value) == zero {
+			return violation(field, CodeRequired, "%s is required", field)
+		}
+		return nil
+	}
+}
+
+// Absent reports field when it is set, for fields that do not apply to
+// the value.
+func Absent[T any, V comparable](field string, get func(T) V) Rule[T] {
+	return func(value T) []Violation {
+		var zero V
+		if get(value) != zero {
+			return violation(field, CodeNotAllowed, "%s must not be set", field)
+		}
+		return nil
+	}
+}
+
+// Range reports field when it is outside [min, max].
+func Range[T any, V cmp.Ordered](field string, get func(T) V, min V, max V) Rule[T] {
+	return func(value T) []Violation {
+		if v := get(value); v < min || v > max {
+			return violation(field, CodeOutOfRange, "%s must be between %v and %v", field, min, max)
+		}
+		return nil
+	}
+}
+
+// GreaterThan reports field when it is not greater than bound.
+func GreaterThan[T any, V cmp.Ordered](field string, get func(T) V, bound V) Rule[T] {
+	return func(value T) []Violation {
+		if get(value) <= bound {
+			return violation(field, CodeOutOfRange, "%s must be greater than %v", field, bound)
+		}
+		return nil
+	}
+}
+
+// OneOf reports field when it is not one of allowed.
+func OneOf[T any, V comparable](field string, get func(T) V, allowed ...V) Rule[T] {
+	names := make([]string, len(allowed))
+	for i, a := range allowed {
+		names[i] = fmt.Sprint(a)
+	}
+	list := strings.Join(names, ", ")
+
+	return func(value T) []Violation {
+		v := get(value)
+		for _, a := range allowed {
+			if v == a {
+				return nil
+			}
+		}
+		return violation(field, CodeNotAllowed, "%s must be one of: %s", field, list)
+	}
+}
+
+// Matches reports field when it does not match pattern. what describes
+// the expected form for the message, e.g. "a four-digit year".
+func Matches[T any](field string, get func(T) string, pattern *regexp.Regexp, what string) Rule[T] {
+	return func(value T) []Violation {
+		if !pattern.MatchString(get(value)) {
+			return violation(field, CodePattern, "%s must be %s", field, what)
+		}
+		return nil
+	}
+}
+
+// Check reports field with code and message when ok returns false. It is
+// the rule for checks across fields.
+func Check[T any](field string, code string, message string, ok func(T) bool) Rule[T] {
+	return func(value T) []Violation {
+		if !ok(value) {
+			return []Violation{{Field: field, Code: code, Message: message}}
+		}
+		return nil
+	}
+}
+
+// When applies rules only to values for which cond holds.
+func When[T any](cond func(T) bool, rules ...Rule[T]) Rule[T] {
+	return func(value T) []Violation {
+		if !cond(value) {
+			return nil
+		}
+		return Rules[T](rules).Validate(value)
+	}
+}
+
+// FirstOf applies rules in order and stops at the first that fails, so
+// that a missing field is not also reported as malformed.
+func FirstOf[T any](rules ...Rule[T]) Rule[T] {
+	return func(value T) []Violation {
+		for _, rule := range rules {
+			if violations := rule(value); len(violations) > 0 {
+				return violations
+			}
+		}
+		return nil
+	}
+}
+
+func violation(field string, code string, format string, args ...interface{}) []Violation {
+	return []Violation{{Field: field, Code: code, Message: fmt.Sprintf(format, args...)}}
+}
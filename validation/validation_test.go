@@ -0,0 +1,161 @@
+package validation
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type sample struct {
+	Name  string
+	Count int
+	Code  string
+}
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule[sample]
+		value sample
+		want  []Violation
+	}{
+		{
+			name:  "required and set",
+			rule:  Required("name", func(s sample) string { return s.Name }),
+			value: sample{Name: "a"},
+		},
+		{
+			name: "required and missing",
+			rule: Required("name", func(s sample) string { return s.Name }),
+			want: []Violation{{Field: "name", Code: CodeRequired, Message: "name is required"}},
+		},
+		{
+			name: "absent and missing",
+			rule: Absent("count", func(s sample) int { return s.Count }),
+		},
+		{
+			name:  "absent and set",
+			rule:  Absent("count", func(s sample) int { return s.Count }),
+			value: sample{Count: 2},
+			want:  []Violation{{Field: "count", Code: CodeNotAllowed, Message: "count must not be set"}},
+		},
+		{
+			name:  "range at the lower bound",
+			rule:  Range("count", func(s sample) int { return s.Count }, 1, 3),
+			value: sample{Count: 1},
+		},
+		{
+			name:  "range at the upper bound",
+			rule:  Range("count", func(s sample) int { return s.Count }, 1, 3),
+			value: sample{Count: 3},
+		},
+		{
+			name:  "range above the upper bound",
+			rule:  Range("count", func(s sample) int { return s.Count }, 1, 3),
+			value: sample{Count: 4},
+			want:  []Violation{{Field: "count", Code: CodeOutOfRange, Message: "count must be between 1 and 3"}},
+		},
+		{
+			name:  "greater than the bound",
+			rule:  GreaterThan("count", func(s sample) int { return s.Count }, 0),
+			value: sample{Count: 1},
+		},
+		{
+			name: "equal to the bound",
+			rule: GreaterThan("count", func(s sample) int { return s.Count }, 0),
+			want: []Violation{{Field: "count", Code: CodeOutOfRange, Message: "count must be greater than 0"}},
+		},
+		{
+			name:  "one of the allowed values",
+			rule:  OneOf("code", func(s sample) string { return s.Code }, "a", "b"),
+			value: sample{Code: "b"},
+		},
+		{
+			name:  "not one of the allowed values",
+			rule:  OneOf("code", func(s sample) string { return s.Code }, "a", "b"),
+			value: sample{Code: "c"},
+			want:  []Violation{{Field: "code", Code: CodeNotAllowed, Message: "code must be one of: a, b"}},
+		},
+		{
+			name:  "matches the pattern",
+			rule:  Matches("code", func(s sample) string { return s.Code }, regexp.MustCompile(`^[0-9]+$`), "digits"),
+			value: sample{Code: "42"},
+		},
+		{
+			name:  "does not match the pattern",
+			rule:  Matches("code", func(s sample) string { return s.Code }, regexp.MustCompile(`^[0-9]+$`), "digits"),
+			value: sample{Code: "4x"},
+			want:  []Violation{{Field: "code", Code: CodePattern, Message: "code must be digits"}},
+		},
+		{
+			name:  "check fails",
+			rule:  Check("code", CodeMismatch, "code must match name", func(s sample) bool { return s.Code == s.Name }),
+			value: sample{Name: "a", Code: "b"},
+			want:  []Violation{{Field: "code", Code: CodeMismatch, Message: "code must match name"}},
+		},
+		{
+			name: "when the condition does not hold",
+			rule: When(func(s sample) bool { return s.Code != "" },
+				Required("name", func(s sample) string { return s.Name }),
+			),
+		},
+		{
+			name: "when the condition holds",
+			rule: When(func(s sample) bool { return s.Code != "" },
+				Required("name", func(s sample) string { return s.Name }),
+				GreaterThan("count", func(s sample) int { return s.Count }, 0),
+			),
+			value: sample{Code: "a"},
+			want: []Violation{
+				{Field: "name", Code: CodeRequired, Message: "name is required"},
+				{Field: "count", Code: CodeOutOfRange, Message: "count must be greater than 0"},
+			},
+		},
+		{
+			name: "first of stops at the first failure",
+			rule: FirstOf(
+				Required("code", func(s sample) string { return s.Code }),
+				Matches("code", func(s sample) string { return s.Code }, regexp.MustCompile(`^[0-9]+$`), "digits"),
+			),
+			want: []Violation{{Field: "code", Code: CodeRequired, Message: "code is required"}},
+		},
+		{
+			name: "first of runs later rules",
+			rule: FirstOf(
+				Required("code", func(s sample) string { return s.Code }),
+				Matches("code", func(s sample) string { return s.Code }, regexp.MustCompile(`^[0-9]+$`), "digits"),
+			),
+			value: sample{Code: "x"},
+			want:  []Violation{{Field: "code", Code: CodePattern, Message: "code must be digits"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("rule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateReportsEveryViolationInOrder(t *testing.T) {
+	rules := Rules[sample]{
+		Required("name", func(s sample) string { return s.Name }),
+		Range("count", func(s sample) int { return s.Count }, 1, 3),
+		OneOf("code", func(s sample) string { return s.Code }, "a"),
+	}
+
+	got := rules.Validate(sample{Code: "a"})
+	want := []Violation{
+		{Field: "name", Code: CodeRequired, Message: "name is required"},
+		{Field: "count", Code: CodeOutOfRange, Message: "count must be between 1 and 3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Validate() = %+v, want %+v", got, want)
+	}
+
+	if got := rules.Validate(sample{Name: "n", Count: 2, Code: "a"}); got != nil {
+		t.Fatalf("Validate() of a valid value = %+v, want nil", got)
+	}
+}
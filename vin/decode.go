@@ -0,0 +1,164 @@
+package vin
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"strings"
+	"time"
+)
+
+// Info is what a VIN says about a vehicle. Manufacturer, Brand and
+// Country are empty when the WMI is not in the bundled table, and Plant
+// when the plant code is not.
+type Info struct {
+	VIN          string `json:"vin"`
+	WMI          string `json:"wmi"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Brand        string `json:"brand,omitempty"`
+	Country      string `json:"country,omitempty"`
+	Region       string `json:"region"`
+	VDS          string `json:"vds"`
+	ModelYear    int    `json:"model_year,omitempty"`
+	ModelYears   []int  `json:"model_years,omitempty"`
+	PlantCode    string `json:"plant_code"`
+	Plant        string `json:"plant,omitempty"`
+	Serial       string `json:"serial"`
+}
+
+type manufacturer struct {
+	name    string
+	brand   string
+	country string
+}
+
+//go:embed wmi.csv
+var wmiCSV []byte
+
+//go:embed plants.csv
+var plantsCSV []byte
+
+var (
+	manufacturers = loadManufacturers()
+	plants        = loadPlants()
+)
+
+// yearCodes are the model year codes in order. The sequence starts at
+// 1980 and repeats every 30 years.
+const yearCodes = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+const firstModelYear = 1980
+
+// Decode validates v, which must be normalized, and splits it into the
+// world manufacturer identifier (positions 1-3), the vehicle descriptor
+// section (4-8), the model year (10), the plant (11) and the serial
+// number (12-17). ModelYear is set only when it is certain: when
+// ModelYears holds a single year, or for a North American VIN, when
+// position 7 picks one of them.
+func Decode(v string) (Info, error) {
+	if err := Validate(v); err != nil {
+		return Info{}, err
+	}
+
+	info := Info{
+		VIN:       v,
+		WMI:       v[:3],
+		Region:    region(v[0]),
+		VDS:       v[3:8],
+		PlantCode: v[10:11],
+		Serial:    v[11:],
+	}
+
+	if m, ok := manufacturers[info.WMI]; ok {
+		info.Manufacturer = m.name
+		info.Brand = m.brand
+		info.Country = m.country
+	}
+	info.Plant = plants[info.WMI+info.PlantCode]
+
+	info.ModelYears = ModelYears(v[9])
+	candidates := info.ModelYears
+	if info.Region == "North America" {
+		// Since 2010, North American cars and light trucks have a letter in
+		// position 7 for model years from 2010 and a digit before.
+		letter := v[6] >= 'A' && v[6] <= 'Z'
+		candidates = nil
+		for _, year := range info.ModelYears {
+			if (year >= 2010) == letter {
+				candidates = append(candidates, year)
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		info.ModelYear = candidates[0]
+	}
+
+	return info, nil
+}
+
+// LatestModelYear is the newest model year a car can have. Model years
+// go on sale during the calendar year before, so it is next year.
+func LatestModelYear() int {
+	return time.Now().Year() + 1
+}
+
+// ModelYears returns every year up to LatestModelYear that code stands
+// for, oldest first. The code repeats every 30 years, so a VIN alone
+// cannot tell them apart.
+func ModelYears(code byte) []int {
+	i := strings.IndexByte(yearCodes, code)
+	if i < 0 {
+		return nil
+	}
+
+	var years []int
+	for year := firstModelYear + i; year <= LatestModelYear(); year += len(yearCodes) {
+		years = append(years, year)
+	}
+	return years
+}
+
+// region names the part of the world the first character of a VIN was
+// assigned to.
+func region(c byte) string {
+	switch {
+	case c >= 'A' && c <= 'H':
+		return "Africa"
+	case c >= 'J' && c <= 'R':
+		return "Asia"
+	case c >= 'S' && c <= 'Z':
+		return "Europe"
+	case c >= '1' && c <= '5':
+		return "North America"
+	case c == '6' || c == '7':
+		return "Oceania"
+	default:
+		return "South America"
+	}
+}
+
+func loadManufacturers() map[string]manufacturer {
+	table := map[string]manufacturer{}
+	for _, record := range readTable(wmiCSV) {
+		table[record[0]] = manufacturer{name: record[1], brand: record[2], country: record[3]}
+	}
+	return table
+}
+
+func loadPlants() map[string]string {
+	table := map[string]string{}
+	for _, record := range readTable(plantsCSV) {
+		table[record[0]+record[1]] = record[2]
+	}
+	return table
+}
+
+// readTable reads a bundled CSV table without its header. The tables are
+// part of the binary, so a malformed one is a programming error.
+func readTable(data []byte) [][]string {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		panic("vin: bundled table: " + err.Error())
+	}
+	return records[1:]
+}
@@ -0,0 +1,48 @@
+package vin
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDecodeModelYear(t *testing.T) {
+	tests := []struct {
+		name       string
+		vin        string
+		modelYears []int
+		modelYear  int
+	}{
+		{name: "single candidate", vin: "1HGCM82633A004352", modelYears: []int{2003}, modelYear: 2003},
+		{name: "north america before 2010", vin: "1M8GDM9AXKP042788", modelYears: []int{1989, 2019}, modelYear: 1989},
+		{name: "north america from 2010", vin: "5YJ3E1EA4LF123456", modelYears: []int{1990, 2020}, modelYear: 2020},
+		{name: "ambiguous outside north america", vin: "WDBRF61J5EF123456", modelYears: []int{1984, 2014}, modelYear: 0},
+		{name: "no year code", vin: "JHMCM56500C123456", modelYears: nil, modelYear: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := Decode(tt.vin)
+			if err != nil {
+				t.Fatalf("Decode(%s) error = %v", tt.vin, err)
+			}
+			if !slices.Equal(info.ModelYears, tt.modelYears) {
+				t.Errorf("ModelYears = %v, want %v", info.ModelYears, tt.modelYears)
+			}
+			if info.ModelYear != tt.modelYear {
+				t.Errorf("ModelYear = %d, want %d", info.ModelYear, tt.modelYear)
+			}
+		})
+	}
+}
+
+func TestModelYearsEndAtLatestModelYear(t *testing.T) {
+	for i := 0; i < len(yearCodes); i++ {
+		years := ModelYears(yearCodes[i])
+		if len(years) == 0 {
+			t.Fatalf("ModelYears(%c) is empty", yearCodes[i])
+		}
+		if last := years[len(years)-1]; last > LatestModelYear() || last+len(yearCodes) <= LatestModelYear() {
+			t.Errorf("ModelYears(%c) ends at %d, want the last year up to %d", yearCodes[i], last, LatestModelYear())
+		}
+	}
+}
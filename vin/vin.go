@@ -0,0 +1,96 @@
+// Package vin validates vehicle identification numbers as laid out by
+// ISO 3779, including the check digit in position 9 where it is
+// mandatory, and decodes them offline with a bundled table of world
+// manufacturer identifiers.
+package vin
+
+import (
+	"errors"
+	"strings"
+)
+
+// Length is the number of characters in a VIN.
+const Length = 17
+
+var (
+	ErrLength     = errors.New("vin must be 17 characters long")
+	ErrCharacters = errors.New("vin may only contain digits and the letters A to Z except I, O and Q")
+	ErrCheckDigit = errors.New("vin check digit does not match")
+)
+
+// weights are the check digit weights of each position. The check digit
+// itself, in position 9, has weight 0.
+var weights = [Length]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// Normalize trims v and upper-cases it, the form in which VINs are
+// validated, stored and looked up.
+func Normalize(v string) string {
+	return strings.ToUpper(strings.TrimSpace(v))
+}
+
+// Validate reports whether v, which must be normalized, is a well-formed
+// VIN. The check digit is only enforced where CheckDigitRequired says so;
+// elsewhere position 9 is often a filler such as Z.
+func Validate(v string) error {
+	digit, err := CheckDigit(v)
+	if err != nil {
+		return err
+	}
+	if CheckDigitRequired(v) && v[8] != digit {
+		return ErrCheckDigit
+	}
+	return nil
+}
+
+// CheckDigitRequired reports whether v must carry a matching check digit:
+// North American VINs, which start with 1 to 5, and Chinese ones, which
+// start with L. Other regions leave position 9 to the manufacturer.
+func CheckDigitRequired(v string) bool {
+	if v == "" {
+		return false
+	}
+	return (v[0] >= '1' && v[0] <= '5') || v[0] == 'L'
+}
+
+// CheckDigit computes the check digit of v: the weighted sum of the
+// transliterated characters modulo 11, with 10 written as X.
+func CheckDigit(v string) (byte, error) {
+	if len(v) != Length {
+		return 0, ErrLength
+	}
+
+	sum := 0
+	for i := 0; i < Length; i++ {
+		value, ok := transliterate(v[i])
+		if !ok {
+			return 0, ErrCharacters
+		}
+		sum += value * weights[i]
+	}
+
+	remainder := sum % 11
+	if remainder == 10 {
+		return 'X', nil
+	}
+	return byte('0' + remainder), nil
+}
+
+// transliterate returns the numeric value of c. I, O and Q are not
+// allowed because they are easily mistaken for 1 and 0.
+func transliterate(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	}
+	return 0, false
+}
@@ -0,0 +1,65 @@
+package vin
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		vin  string
+		want error
+	}{
+		{name: "north america with X check digit", vin: "1M8GDM9AXKP042788"},
+		{name: "north america with digit check digit", vin: "1HGCM82633A004352"},
+		{name: "north america with wrong check digit", vin: "1M8GDM9A1KP042788", want: ErrCheckDigit},
+		{name: "north america with filler check digit", vin: "1M8GDM9AZKP042788", want: ErrCheckDigit},
+		{name: "china with check digit", vin: "LSVAU2187N2123456"},
+		{name: "china with wrong check digit", vin: "LSVAU2180N2123456", want: ErrCheckDigit},
+		{name: "europe with filler check digit", vin: "WVWZZZ1JZ3W386752"},
+		{name: "europe with non-matching digit", vin: "WDBRF61J0EF123456"},
+		{name: "asia with non-matching digit", vin: "JHMCM56550C123456"},
+		{name: "letter I", vin: "1M8GDM9AXKI042788", want: ErrCharacters},
+		{name: "letter O", vin: "WVWZZZ1JZ3W38675O", want: ErrCharacters},
+		{name: "letter Q", vin: "QVWZZZ1JZ3W386752", want: ErrCharacters},
+		{name: "lower case", vin: "1m8gdm9axkp042788", want: ErrCharacters},
+		{name: "too short", vin: "1M8GDM9AXKP04278", want: ErrLength},
+		{name: "too long", vin: "1M8GDM9AXKP0427888", want: ErrLength},
+		{name: "empty", vin: "", want: ErrLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.vin)
+			if !errors.Is(err, tt.want) || (err == nil) != (tt.want == nil) {
+				t.Fatalf("Validate(%q) = %v, want %v", tt.vin, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		vin  string
+		want byte
+	}{
+		{vin: "1M8GDM9AXKP042788", want: 'X'},
+		{vin: "1HGCM82633A004352", want: '3'},
+		{vin: "LSVAU2187N2123456", want: '7'},
+		{vin: "WVWZZZ1JZ3W386752", want: '9'},
+	}
+
+	for _, tt := range tests {
+		got, err := CheckDigit(tt.vin)
+		if err != nil || got != tt.want {
+			t.Errorf("CheckDigit(%q) = %q, %v, want %q", tt.vin, got, err, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got := Normalize("  1m8gdm9axkp042788\n"); got != "1M8GDM9AXKP042788" {
+		t.Fatalf("Normalize() = %q", got)
+	}
+}